@@ -0,0 +1,67 @@
+package timewheel
+
+import "time"
+
+// WithAckTimeout makes delivery at-least-once. Each delivered task stays in
+// the wheel until Ack is called with its key, and is delivered again, as its
+// next attempt, if timeout passes first; a worker that dies between
+// receiving a task and finishing it does not lose it. A failed delivery need
+// not be acknowledged: it is redelivered, after the backoff delay under
+// WithRetryBackoff. Cron tasks are not tracked.
+func WithAckTimeout(timeout time.Duration) Option {
+	return func(tw *TimeWheel) {
+		tw.ackTimeout = timeout
+	}
+}
+
+// Ack acknowledges the delivery of a task so it is not redelivered, and
+// reports whether a delivery under the key was awaiting acknowledgment.
+func (tw *TimeWheel) Ack(key string) bool {
+	done := tw.lockFor(&tw.latency.delete)
+	defer tw.unlock()
+	defer done()
+
+	entry, exists := tw.keyMap[key]
+	if !exists || !entry.awaitingAck {
+		return false
+	}
+	tw.acked(entry, ReasonAcked)
+	return true
+}
+
+// awaitAck schedules the redelivery of a task being delivered, before any
+// consumer can acknowledge it.
+func (tw *TimeWheel) awaitAck(entry *taskEntry) {
+	if tw.ackTimeout <= 0 || entry.cron != nil {
+		return
+	}
+	entry.awaitingAck = true
+	if !tw.requeue(entry, tw.ackTimeout, true) {
+		entry.awaitingAck = false
+	}
+}
+
+// redelivers reports whether cur is the redelivery awaiting fired's ack.
+func (tw *TimeWheel) redelivers(cur, fired *taskEntry) bool {
+	return cur.awaitingAck && fired.awaitingAck && cur.retries == fired.retries+1
+}
+
+// dropRedelivery removes the redelivery of a task given up on.
+func (tw *TimeWheel) dropRedelivery(fired *taskEntry) {
+	tw.mu.Lock()
+	defer tw.unlock()
+
+	if cur, exists := tw.keyMap[fired.key]; exists && tw.redelivers(cur, fired) {
+		tw.acked(cur, ReasonExpired)
+	}
+}
+
+// acked removes a redelivery under the lock; it ends the task, so it is
+// logged and reported without the cancel hook.
+func (tw *TimeWheel) acked(entry *taskEntry, reason Reason) {
+	tw.untrack(entry)
+	tw.unlink(entry)
+	tw.journal(hookCancel, entry)
+	tw.removed(entry, reason)
+	releaseEntry(entry)
+}
@@ -0,0 +1,86 @@
+package timewheel
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestAck(t *testing.T) {
+	var attempts []int
+	tw := NewTimeWheel(0, 10, nil, WithSyncCallbacks(0), WithAckTimeout(3*ManualInterval),
+		WithContextCallback(func(ctx context.Context, key string, _ any) {
+			attempts = append(attempts, Attempt(ctx))
+		}))
+	defer tw.Stop()
+
+	tw.Set("job", nil, ManualInterval)
+	tw.Tick()
+	if len(attempts) != 1 {
+		t.Fatalf("Expected one delivery, got %v", attempts)
+	}
+
+	// Not acknowledged in time: delivered again as the next attempt
+	tw.Advance(3 * ManualInterval)
+	if len(attempts) != 2 || attempts[1] != 2 {
+		t.Fatalf("Expected a redelivery as attempt 2, got %v", attempts)
+	}
+
+	if !tw.Ack("job") {
+		t.Fatal("Expected Ack to find the delivery awaiting it")
+	}
+	if tw.Ack("job") {
+		t.Error("Expected a second Ack to find nothing")
+	}
+	tw.Advance(10 * ManualInterval)
+	if len(attempts) != 2 {
+		t.Errorf("Expected no delivery after Ack, got %v", attempts)
+	}
+}
+
+func TestAckFromCallback(t *testing.T) {
+	var tw *TimeWheel
+	var reasons []Reason
+	deliveries := 0
+	tw = NewTimeWheel(0, 10, nil, WithSyncCallbacks(0), WithAckTimeout(ManualInterval),
+		WithOnRemove(func(_ string, _ any, r Reason) { reasons = append(reasons, r) }),
+		WithErrCallback(func(key string, _ any) error {
+			if deliveries++; deliveries == 1 {
+				return errors.New("worker crashed")
+			}
+			tw.Ack(key)
+			return nil
+		}))
+	defer tw.Stop()
+
+	tw.Set("job", nil, ManualInterval)
+	tw.Advance(5 * ManualInterval)
+	if deliveries != 2 {
+		t.Errorf("Expected the failed delivery to be redelivered once, got %d", deliveries)
+	}
+	if len(reasons) != 1 || reasons[0] != ReasonAcked {
+		t.Errorf("Expected the task to end once, acked, got %v", reasons)
+	}
+}
+
+func TestAckGivesUpWithBackoff(t *testing.T) {
+	deliveries := 0
+	var dead int
+	tw := NewTimeWheel(0, 10, nil, WithSyncCallbacks(0), WithAckTimeout(10*ManualInterval),
+		WithRetryBackoff(ManualInterval, ManualInterval, 2),
+		WithErrCallback(func(string, any) error {
+			deliveries++
+			return errors.New("boom")
+		}),
+		WithDeadLetter(func(TaskInfo, error) { dead++ }))
+	defer tw.Stop()
+
+	tw.Set("job", nil, ManualInterval)
+	tw.Advance(30 * ManualInterval)
+	if deliveries != 2 || dead != 1 {
+		t.Errorf("Expected 2 deliveries and a dead letter, got %d and %d", deliveries, dead)
+	}
+	if n := tw.Stats().Pending; n != 0 {
+		t.Errorf("Expected no redelivery left pending, got %d", n)
+	}
+}
@@ -0,0 +1,83 @@
+package timewheel
+
+import "time"
+
+// WithAdaptiveTick lets the ticker slow down while the soonest deadline is
+// far off, to save wake-ups on battery-powered or serverless hosts. After
+// each tick the wheel sleeps until the next slot holding tasks, at most
+// maxStride base intervals and never past a demotion from the layer above,
+// then steps through the slots it skipped. A Set due sooner than the planned
+// wake-up re-plans it, so tasks still fire within a base interval of their
+// deadlines. Pre-expiry warnings and deferred expirations keep the ticker at
+// the base interval. Manual and grouped wheels ignore it.
+func WithAdaptiveTick(maxStride int) Option {
+	return func(tw *TimeWheel) {
+		if maxStride > 1 {
+			tw.maxStride = maxStride
+			tw.retime = make(chan struct{}, 1)
+		}
+	}
+}
+
+// restride plans the next wake-up after a tick and returns the ticker for
+// it, replacing the current one when the wait changes.
+func (tw *TimeWheel) restride(ticker Ticker, period time.Duration) (Ticker, time.Duration) {
+	tw.mu.Lock()
+	stride := tw.plan()
+	tw.stride = stride
+	tw.wakeAt = 0
+	if stride > 1 {
+		tw.wakeAt = tw.cursor + uint64(stride)
+	}
+	tw.mu.Unlock()
+
+	// Aim for the tick on the wheel's schedule, not a full stride from now
+	wait := tw.baseInterval
+	if stride > 1 {
+		due := tw.startedAt.Add(time.Duration(tw.ticksDone+int64(stride)) * tw.baseInterval)
+		wait = max(due.Sub(tw.clock.Now()), tw.baseInterval)
+	}
+	if wait == period && stride == 1 {
+		return ticker, period
+	}
+	ticker.Stop()
+	return tw.clock.NewTicker(wait), wait
+}
+
+// plan returns how many ticks the wheel can skip to the next one with work
+// to do. Callers hold the lock.
+func (tw *TimeWheel) plan() int {
+	if len(tw.backlog) > 0 || len(tw.warnings) > 0 {
+		return 1
+	}
+	limit := tw.maxStride
+	if len(tw.layers) > 1 {
+		span := tw.layers[1].span
+		limit = min(limit, int(span-tw.cursor%span))
+	}
+	base := tw.layers[0]
+	for j := 1; j < limit; j++ {
+		if base.buckets[(base.currentPos+j)%base.slots].n > 0 {
+			return j
+		}
+	}
+	return limit
+}
+
+// replanFor wakes the tick loop to re-plan if entry, just placed, falls due
+// before the planned wake-up. Callers hold the lock.
+func (tw *TimeWheel) replanFor(entry *taskEntry) {
+	if tw.wakeAt == 0 || entry.layerIndex != 0 {
+		return
+	}
+	base := tw.layers[0]
+	ahead := (entry.bucketPos - base.currentPos + base.slots) % base.slots
+	if tw.cursor+uint64(ahead) >= tw.wakeAt {
+		return
+	}
+	tw.wakeAt = 0
+	select {
+	case tw.retime <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,88 @@
+package timewheel
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// periodClock records the period of every ticker it creates.
+type periodClock struct {
+	systemClock
+	mu      sync.Mutex
+	periods []time.Duration
+}
+
+func (c *periodClock) NewTicker(d time.Duration) Ticker {
+	c.mu.Lock()
+	c.periods = append(c.periods, d)
+	c.mu.Unlock()
+	return c.systemClock.NewTicker(d)
+}
+
+func (c *periodClock) longest() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var longest time.Duration
+	for _, p := range c.periods {
+		longest = max(longest, p)
+	}
+	return longest
+}
+
+func TestAdaptiveTick(t *testing.T) {
+	clock := &periodClock{}
+	fired := make(chan time.Time, 1)
+	tw := NewTimeWheel(5*time.Millisecond, 100, func(string, any) {
+		fired <- time.Now()
+	}, WithClock(clock), WithAdaptiveTick(100))
+	defer tw.Stop()
+
+	start := time.Now()
+	tw.Set("far", nil, 200*time.Millisecond)
+	select {
+	case at := <-fired:
+		if d := at.Sub(start); d < 200*time.Millisecond || d > 250*time.Millisecond {
+			t.Errorf("Expected the task to fire on time, fired after %v", d)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Task did not fire")
+	}
+	if p := clock.longest(); p < 100*time.Millisecond {
+		t.Errorf("Expected the ticker to coarsen while the deadline was far off, longest period %v", p)
+	}
+}
+
+func TestAdaptiveTickRetightens(t *testing.T) {
+	fired := make(chan string, 2)
+	tw := NewTimeWheel(5*time.Millisecond, 100, func(key string, _ any) {
+		fired <- key
+	}, WithClock(&periodClock{}), WithAdaptiveTick(100))
+	defer tw.Stop()
+
+	tw.Set("far", nil, 2*time.Second)
+	time.Sleep(20 * time.Millisecond)
+
+	// The ticker now sleeps towards the next demotion; a short task cuts it short
+	start := time.Now()
+	tw.Set("near", nil, 30*time.Millisecond)
+	select {
+	case key := <-fired:
+		if d := time.Since(start); key != "near" || d > 80*time.Millisecond {
+			t.Errorf("Expected near to fire on time, got %s after %v", key, d)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Short task did not fire")
+	}
+}
+
+func TestAdaptiveTickHealthy(t *testing.T) {
+	tw := NewTimeWheel(5*time.Millisecond, 100, nil, WithAdaptiveTick(100))
+	defer tw.Stop()
+
+	tw.Set("far", nil, time.Hour)
+	time.Sleep(150 * time.Millisecond)
+	if err := tw.Healthy(); err != nil {
+		t.Errorf("Expected a wheel sleeping through a stride to be healthy, got %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+package timewheel
+
+// Annotations returns a copy of the annotations attached to a pending task.
+func (tw *TimeWheel) Annotations(key string) (map[string]string, bool) {
+	tw.mu.RLock()
+	defer tw.mu.RUnlock()
+
+	entry, exists := tw.keyMap[key]
+	if !exists {
+		return nil, false
+	}
+	return copyAnnotations(entry.annotations), true
+}
+
+func copyAnnotations(annotations map[string]string) map[string]string {
+	if annotations == nil {
+		return nil
+	}
+	c := make(map[string]string, len(annotations))
+	for k, v := range annotations {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,27 @@
+package timewheel
+
+import "testing"
+
+func TestAnnotations(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil, WithExpiredChannel(1, OverflowBlock))
+	defer tw.Stop()
+
+	annotations := map[string]string{"orderID": "42", "tenant": "acme"}
+	tw.SetWith("order", "data", ManualInterval, TaskAnnotations(annotations))
+	annotations["tenant"] = "changed"
+
+	got, ok := tw.Annotations("order")
+	if !ok || got["orderID"] != "42" || got["tenant"] != "acme" {
+		t.Errorf("Expected stored annotations to be a copy, got %v", got)
+	}
+
+	tw.Tick()
+	task := <-tw.Expired()
+	if task.Annotations["orderID"] != "42" {
+		t.Errorf("Expected annotations on expired task, got %v", task.Annotations)
+	}
+
+	if _, ok := tw.Annotations("order"); ok {
+		t.Error("Expected no annotations after expiry")
+	}
+}
@@ -0,0 +1,81 @@
+package timewheel
+
+import (
+	"context"
+	"log/slog"
+	"runtime/pprof"
+	"strconv"
+)
+
+// WithBatchCallback delivers expirations in batches instead of one callback
+// per task: the tasks a tick expires are handed to cb in slices of at most
+// maxBatch (unlimited if <= 0), each on one goroutine, or inline on the tick
+// goroutine with WithSyncCallbacks. It replaces the constructor's callback
+// and the error and context callbacks; tasks fired outside a tick, such as
+// zero-TTL ones, arrive in batches of one. A panic in cb goes to the panic
+// handler with an empty key and the batch as the value.
+func WithBatchCallback(cb func(tasks []ExpiredTask), maxBatch int) Option {
+	return func(tw *TimeWheel) {
+		tw.batchCallback = cb
+		tw.maxBatch = maxBatch
+	}
+}
+
+// dispatchBatches splits the entries a tick expired into batches.
+func (tw *TimeWheel) dispatchBatches(entries []*taskEntry) {
+	for len(entries) > 0 {
+		n := len(entries)
+		if tw.maxBatch > 0 && n > tw.maxBatch {
+			n = tw.maxBatch
+		}
+		batch := entries[:n:n]
+		entries = entries[n:]
+		if tw.syncMode {
+			tw.invokeBatch(batch)
+		} else {
+			tw.runtime.Go(func() { tw.invokeBatch(batch) })
+		}
+	}
+}
+
+func (tw *TimeWheel) invokeBatch(entries []*taskEntry) {
+	live := entries[:0]
+	for _, entry := range entries {
+		if tw.claim(entry) {
+			live = append(live, entry)
+		} else {
+			tw.superseded(entry, true)
+		}
+	}
+	if entries = live; len(entries) == 0 {
+		return
+	}
+	tasks := make([]ExpiredTask, len(entries))
+	for i, entry := range entries {
+		tasks[i] = entry.expiredTask()
+	}
+	defer func() {
+		for _, entry := range entries {
+			tw.finish(entry, false)
+		}
+	}()
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		tw.counters.panics.Add(1)
+		tw.log(slog.LevelError, "timewheel: batch callback panicked", "size", len(tasks), "panic", r)
+		if tw.panicHandler != nil {
+			tw.panicHandler("", tasks, r)
+		}
+	}()
+	if tw.labels != nil {
+		pprof.Do(context.Background(), pprof.Labels(batchLabel, strconv.Itoa(len(tasks))), func(context.Context) {
+			tw.batchCallback(tasks)
+		})
+		return
+	}
+	tw.batchCallback(tasks)
+}
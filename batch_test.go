@@ -0,0 +1,49 @@
+package timewheel
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBatchCallback(t *testing.T) {
+	var sizes []int
+	seen := make(map[string]bool)
+	tw := NewTimeWheel(0, 10, nil, WithSyncCallbacks(0), WithBatchCallback(func(tasks []ExpiredTask) {
+		sizes = append(sizes, len(tasks))
+		for _, task := range tasks {
+			seen[task.Key] = true
+		}
+	}, 4))
+	defer tw.Stop()
+
+	for i := 0; i < 10; i++ {
+		tw.Set(fmt.Sprint(i), i, 2*ManualInterval)
+	}
+	tw.Advance(2 * ManualInterval)
+
+	if len(sizes) != 3 || sizes[0] != 4 || sizes[1] != 4 || sizes[2] != 2 {
+		t.Errorf("Expected batches of 4, 4 and 2, got %v", sizes)
+	}
+	if len(seen) != 10 {
+		t.Errorf("Expected all 10 tasks delivered, got %d", len(seen))
+	}
+	if fired := tw.Stats().Fired; fired != 10 {
+		t.Errorf("Expected 10 fired, got %d", fired)
+	}
+}
+
+func TestBatchCallbackPanic(t *testing.T) {
+	recovered := make(chan any, 1)
+	tw := NewTimeWheel(0, 10, nil, WithSyncCallbacks(0), WithBatchCallback(func(tasks []ExpiredTask) {
+		panic("boom")
+	}, 0), WithPanicHandler(func(key string, value any, r any) {
+		recovered <- value
+	}))
+	defer tw.Stop()
+
+	tw.Set("a", 1, ManualInterval)
+	tw.Tick()
+	if batch, ok := (<-recovered).([]ExpiredTask); !ok || len(batch) != 1 || batch[0].Key != "a" {
+		t.Errorf("Expected the panic handler to receive the batch, got %v", batch)
+	}
+}
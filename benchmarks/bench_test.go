@@ -0,0 +1,127 @@
+package benchmarks
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nzai/timewheel"
+)
+
+var sizes = []int{10_000, 100_000, 1_000_000}
+
+// preload returns a manual wheel with n tasks spread over the next hour.
+func preload(b *testing.B, n int) (*timewheel.TimeWheel, []string) {
+	b.Helper()
+	tw := timewheel.NewTimeWheel(time.Millisecond, 60, nil, timewheel.WithManualMode())
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = "task:" + strconv.Itoa(i)
+		tw.Set(keys[i], i, time.Duration(i%3_600_000+1)*time.Millisecond)
+	}
+	b.Cleanup(tw.Stop)
+	b.ResetTimer()
+	return tw, keys
+}
+
+func BenchmarkSet(b *testing.B) {
+	for _, n := range sizes {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			tw, keys := preload(b, n)
+			for i := 0; i < b.N; i++ {
+				tw.Set(keys[i%n], i, time.Duration(i%3_600_000+1)*time.Millisecond)
+			}
+		})
+	}
+}
+
+func BenchmarkSetNew(b *testing.B) {
+	for _, n := range sizes {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			tw, _ := preload(b, n)
+			for i := 0; i < b.N; i++ {
+				key := "new:" + strconv.Itoa(i)
+				tw.Set(key, i, time.Minute)
+				tw.Delete(key)
+			}
+		})
+	}
+}
+
+func BenchmarkDelete(b *testing.B) {
+	for _, n := range sizes {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			tw, keys := preload(b, n)
+			for i := 0; i < b.N; i++ {
+				key := keys[i%n]
+				tw.Delete(key)
+				b.StopTimer()
+				tw.Set(key, i, time.Hour)
+				b.StartTimer()
+			}
+		})
+	}
+}
+
+func BenchmarkMove(b *testing.B) {
+	for _, n := range sizes {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			tw, keys := preload(b, n)
+			for i := 0; i < b.N; i++ {
+				tw.Move(keys[i%n], time.Duration(i%3_600_000+1)*time.Millisecond)
+			}
+		})
+	}
+}
+
+func BenchmarkTick(b *testing.B) {
+	for _, n := range sizes {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			tw, _ := preload(b, n)
+			for i := 0; i < b.N; i++ {
+				tw.Tick()
+			}
+		})
+	}
+}
+
+func BenchmarkSetParallel(b *testing.B) {
+	b.Run("single", func(b *testing.B) {
+		tw := timewheel.NewTimeWheel(time.Millisecond, 60, nil, timewheel.WithManualMode())
+		defer tw.Stop()
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				tw.Set("k"+strconv.Itoa(i%100_000), i, time.Minute)
+			}
+		})
+	})
+	b.Run("sharded", func(b *testing.B) {
+		s := timewheel.NewShardedTimeWheel(16, time.Millisecond, 60, nil, timewheel.WithManualMode())
+		defer s.Stop()
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				s.Set("k"+strconv.Itoa(i%100_000), i, time.Minute)
+			}
+		})
+	})
+}
+
+// BenchmarkIntKeys compares integer keys formatted for a TimeWheel with the
+// same keys used directly by a KeyedWheel.
+func BenchmarkIntKeys(b *testing.B) {
+	b.Run("formatted", func(b *testing.B) {
+		tw := timewheel.NewTimeWheel(time.Millisecond, 60, nil, timewheel.WithManualMode())
+		defer tw.Stop()
+		for i := 0; i < b.N; i++ {
+			tw.Set(strconv.FormatUint(uint64(i%100_000), 10), i, time.Minute)
+		}
+	})
+	b.Run("keyed", func(b *testing.B) {
+		w := timewheel.NewKeyedWheel(0, 60, func(uint64, int) {})
+		defer w.Stop()
+		for i := 0; i < b.N; i++ {
+			w.Set(uint64(i%100_000), i, time.Minute)
+		}
+	})
+}
@@ -0,0 +1,7 @@
+// Package benchmarks holds the wheel's benchmark suite. Run it with
+//
+//	go test -bench . -benchmem ./benchmarks
+//
+// Each benchmark runs against 10k, 100k and 1M pending tasks on a manual
+// wheel, so results measure the data structure rather than the ticker.
+package benchmarks
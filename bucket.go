@@ -0,0 +1,82 @@
+package timewheel
+
+import "sync"
+
+// bucket is a slot's intrusive doubly-linked list of entries. Unlike a map
+// per slot it allocates nothing per task and nothing when emptied.
+type bucket struct {
+	head *taskEntry
+	n    int
+}
+
+func (b *bucket) push(entry *taskEntry) {
+	entry.prev = nil
+	entry.next = b.head
+	if b.head != nil {
+		b.head.prev = entry
+	}
+	b.head = entry
+	b.n++
+}
+
+// remove unlinks entry, which is a no-op when it is not in b.
+func (b *bucket) remove(entry *taskEntry) {
+	if b.detach(entry) {
+		b.n--
+	}
+}
+
+func (b *bucket) detach(entry *taskEntry) bool {
+	if entry.prev != nil {
+		entry.prev.next = entry.next
+	} else if b.head == entry {
+		b.head = entry.next
+	} else {
+		return false
+	}
+	if entry.next != nil {
+		entry.next.prev = entry.prev
+	}
+	entry.prev, entry.next = nil, nil
+	return true
+}
+
+// mark links a marker in front of entry without counting it as a task.
+func (b *bucket) mark(marker, entry *taskEntry) {
+	marker.prev = entry.prev
+	marker.next = entry
+	if entry.prev != nil {
+		entry.prev.next = marker
+	} else {
+		b.head = marker
+	}
+	entry.prev = marker
+}
+
+func (b *bucket) len() int {
+	return b.n
+}
+
+// place puts entry in slot pos of l for rounds more revolutions.
+func (tw *TimeWheel) place(entry *taskEntry, l *layer, pos, rounds int) {
+	entry.layerIndex = tw.getLayerIndex(l)
+	entry.bucketPos = pos
+	entry.rounds = rounds
+	l.buckets[pos].push(entry)
+	tw.replanFor(entry)
+}
+
+// entryPool recycles entries that leave the wheel without firing. Fired
+// entries are left to the GC: callbacks and retries may still hold them.
+var entryPool = sync.Pool{
+	New: func() any { return new(taskEntry) },
+}
+
+func newEntry() *taskEntry {
+	return entryPool.Get().(*taskEntry)
+}
+
+func releaseEntry(entry *taskEntry) {
+	*entry = taskEntry{}
+	entryPool.Put(entry)
+}
@@ -0,0 +1,49 @@
+package timewheel
+
+import "testing"
+
+func TestBucket(t *testing.T) {
+	var b bucket
+	a, c, d := &taskEntry{key: "a"}, &taskEntry{key: "c"}, &taskEntry{key: "d"}
+	b.push(a)
+	b.push(c)
+	b.push(d)
+	if n := b.len(); n != 3 {
+		t.Fatalf("Expected 3 entries, got %d", n)
+	}
+
+	b.remove(c)
+	b.remove(c)
+	b.remove(&taskEntry{key: "stranger"})
+	if b.head != d || d.next != a || a.prev != d || b.len() != 2 {
+		t.Fatal("Expected removing the middle entry to relink its neighbours")
+	}
+	b.remove(d)
+	b.remove(a)
+	if b.head != nil {
+		t.Fatal("Expected an empty bucket")
+	}
+}
+
+func TestBucketReplaceWhileTicking(t *testing.T) {
+	var fired []string
+	tw := NewTimeWheel(0, 4, func(k string, v any) {
+		fired = append(fired, k)
+	}, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	// Both land in the same slot; one needs another revolution
+	tw.Set("now", "data", 4*ManualInterval)
+	tw.Set("later", "data", 8*ManualInterval)
+	tw.Delete("now")
+	tw.Set("now", "data", 4*ManualInterval)
+
+	tw.Advance(4 * ManualInterval)
+	if len(fired) != 1 || fired[0] != "now" {
+		t.Fatalf("Expected now to fire, got %v", fired)
+	}
+	tw.Advance(4 * ManualInterval)
+	if len(fired) != 2 || fired[1] != "later" {
+		t.Errorf("Expected later to fire a revolution later, got %v", fired)
+	}
+}
@@ -0,0 +1,86 @@
+package timewheel
+
+import "time"
+
+// DeleteBatch deletes every pending task among keys under one lock
+// acquisition and returns how many it deleted. Missing keys are skipped.
+func (tw *TimeWheel) DeleteBatch(keys []string) int {
+	done := tw.lockFor(&tw.latency.delete)
+	defer tw.unlock()
+	defer done()
+
+	n := 0
+	for _, key := range keys {
+		tw.abort(key)
+		if entry, exists := tw.keyMap[key]; exists {
+			tw.cancel(entry, ReasonDeleted)
+			n++
+		}
+	}
+	return n
+}
+
+// MoveBatch reschedules each pending task in moves to fire after its
+// duration, under one lock acquisition, and returns how many it moved.
+// Missing keys are skipped.
+func (tw *TimeWheel) MoveBatch(moves map[string]time.Duration) int {
+	done := tw.lockFor(&tw.latency.move)
+	defer tw.unlock()
+	defer done()
+
+	n := 0
+	for key, expiration := range moves {
+		if entry, exists := tw.keyMap[key]; exists {
+			tw.reschedule(entry, expiration)
+			n++
+		}
+	}
+	return n
+}
+
+func (ns *Namespace) DeleteBatch(keys []string) int {
+	full := make([]string, len(keys))
+	for i, key := range keys {
+		full[i] = ns.Key(key)
+	}
+	return ns.tw.DeleteBatch(full)
+}
+
+func (ns *Namespace) MoveBatch(moves map[string]time.Duration) int {
+	full := make(map[string]time.Duration, len(moves))
+	for key, expiration := range moves {
+		full[ns.Key(key)] = expiration
+	}
+	return ns.tw.MoveBatch(full)
+}
+
+// DeleteBatch splits keys by shard and locks each shard once.
+func (s *ShardedTimeWheel) DeleteBatch(keys []string) int {
+	byShard := make(map[*TimeWheel][]string)
+	for _, key := range keys {
+		shard := s.Shard(key)
+		byShard[shard] = append(byShard[shard], key)
+	}
+	n := 0
+	for shard, keys := range byShard {
+		n += shard.DeleteBatch(keys)
+	}
+	return n
+}
+
+// MoveBatch splits moves by shard and locks each shard once.
+func (s *ShardedTimeWheel) MoveBatch(moves map[string]time.Duration) int {
+	byShard := make(map[*TimeWheel]map[string]time.Duration)
+	for key, expiration := range moves {
+		shard := s.Shard(key)
+		if byShard[shard] == nil {
+			byShard[shard] = make(map[string]time.Duration)
+		}
+		byShard[shard][key] = expiration
+	}
+	n := 0
+	for shard, moves := range byShard {
+		n += shard.MoveBatch(moves)
+	}
+	return n
+}
@@ -0,0 +1,63 @@
+package timewheel
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestBulkDeleteAndMove(t *testing.T) {
+	var fired []string
+	tw := NewTimeWheel(0, 10, func(k string, v any) {
+		fired = append(fired, k)
+	}, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	for _, key := range []string{"a", "b", "c", "d"} {
+		tw.Set(key, nil, 5*ManualInterval)
+	}
+	if n := tw.DeleteBatch([]string{"a", "b", "missing"}); n != 2 {
+		t.Errorf("Expected DeleteBatch to delete 2 tasks, deleted %d", n)
+	}
+	if n := tw.MoveBatch(map[string]time.Duration{"c": 20 * ManualInterval, "a": time.Hour}); n != 1 {
+		t.Errorf("Expected MoveBatch to move 1 task, moved %d", n)
+	}
+
+	tw.Advance(10 * ManualInterval)
+	if len(fired) != 1 || fired[0] != "d" {
+		t.Errorf("Expected only d to fire on time, got %v", fired)
+	}
+	tw.Advance(10 * ManualInterval)
+	if len(fired) != 2 || fired[1] != "c" {
+		t.Errorf("Expected c to fire at its moved deadline, got %v", fired)
+	}
+}
+
+func TestShardedBulkDeleteAndMove(t *testing.T) {
+	s := NewShardedTimeWheel(4, time.Second, 60, nil)
+	defer s.Stop()
+
+	keys := []string{"a", "b", "c", "d", "e", "f"}
+	for _, key := range keys {
+		s.Set(key, nil, time.Minute)
+	}
+	if n := s.MoveBatch(map[string]time.Duration{"a": time.Hour, "b": time.Hour}); n != 2 {
+		t.Errorf("Expected 2 tasks moved, got %d", n)
+	}
+	if n := s.DeleteBatch(keys[2:]); n != 4 {
+		t.Errorf("Expected 4 tasks deleted, got %d", n)
+	}
+
+	var left []string
+	s.Range(func(key string, _ any, expireAt time.Time) bool {
+		left = append(left, key)
+		if time.Until(expireAt) < 59*time.Minute {
+			t.Errorf("Expected %s to be moved an hour out", key)
+		}
+		return true
+	})
+	sort.Strings(left)
+	if len(left) != 2 || left[0] != "a" || left[1] != "b" {
+		t.Errorf("Expected a and b to remain, got %v", left)
+	}
+}
@@ -0,0 +1,74 @@
+package timewheel
+
+// EvictionPolicy decides what Set does when the wheel is at capacity.
+type EvictionPolicy int
+
+const (
+	// EvictRejectNew keeps the pending tasks and makes SetWith return
+	// ErrOverCapacity (default).
+	EvictRejectNew EvictionPolicy = iota
+	// EvictSoonest cancels the pending task closest to its deadline.
+	EvictSoonest
+	// EvictLatest cancels the pending task furthest from its deadline.
+	EvictLatest
+)
+
+// evictionSamples is how many pending tasks an eviction compares. Like
+// Redis' volatile-ttl policy the choice is approximate beyond that many
+// tasks, which keeps a full wheel's Set independent of its size.
+const evictionSamples = 16
+
+// WithCapacity bounds the number of pending tasks to max. Set on a new key
+// beyond it is rejected or makes room per policy; replacing a pending key
+// is always allowed.
+func WithCapacity(max int, policy EvictionPolicy) Option {
+	return func(tw *TimeWheel) {
+		tw.capacity = max
+		tw.eviction = policy
+	}
+}
+
+// makeRoom enforces the capacity before a new key is stored.
+func (tw *TimeWheel) makeRoom() error {
+	if tw.capacity <= 0 || len(tw.keyMap) < tw.capacity {
+		return nil
+	}
+	if tw.eviction == EvictRejectNew {
+		return ErrOverCapacity
+	}
+
+	// Map iteration starts at a random point, which makes this a sample
+	var victim *taskEntry
+	n := 0
+	for _, entry := range tw.keyMap {
+		if victim == nil ||
+			tw.eviction == EvictSoonest && entry.expiration.Before(victim.expiration) ||
+			tw.eviction == EvictLatest && entry.expiration.After(victim.expiration) {
+			victim = entry
+		}
+		if n++; n == evictionSamples {
+			break
+		}
+	}
+
+	tw.untrack(victim)
+	tw.unlink(victim)
+	tw.counters.evicted.Add(1)
+	tw.record(hookCancel, victim)
+	tw.removed(victim, ReasonEvicted)
+	releaseEntry(victim)
+	return nil
+}
+
+func (p EvictionPolicy) String() string {
+	switch p {
+	case EvictRejectNew:
+		return "EvictRejectNew"
+	case EvictSoonest:
+		return "EvictSoonest"
+	case EvictLatest:
+		return "EvictLatest"
+	default:
+		return "EvictionPolicy(unknown)"
+	}
+}
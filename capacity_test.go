@@ -0,0 +1,42 @@
+package timewheel
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCapacity(t *testing.T) {
+	fill := func(tw *TimeWheel) {
+		tw.Set("soon", "data", 2*ManualInterval)
+		tw.Set("mid", "data", 5*ManualInterval)
+		tw.Set("late", "data", 9*ManualInterval)
+	}
+
+	reject := NewTimeWheel(0, 10, nil, WithCapacity(3, EvictRejectNew))
+	defer reject.Stop()
+	fill(reject)
+	if err := reject.SetWith("new", "data", 3*ManualInterval); !errors.Is(err, ErrOverCapacity) {
+		t.Errorf("Expected ErrOverCapacity, got %v", err)
+	}
+	if err := reject.SetWith("mid", "replaced", 3*ManualInterval); err != nil {
+		t.Errorf("Expected replacing a pending key to succeed, got %v", err)
+	}
+
+	for policy, victim := range map[EvictionPolicy]string{EvictSoonest: "soon", EvictLatest: "late"} {
+		var cancelled []string
+		tw := NewTimeWheel(0, 10, nil, WithCapacity(3, policy), WithOnCancel(func(task TaskInfo) {
+			cancelled = append(cancelled, task.Key)
+		}))
+		fill(tw)
+		if err := tw.SetWith("new", "data", 3*ManualInterval); err != nil {
+			t.Errorf("%s: expected room to be made, got %v", policy, err)
+		}
+		if len(cancelled) != 1 || cancelled[0] != victim {
+			t.Errorf("%s: expected %s to be evicted, got %v", policy, victim, cancelled)
+		}
+		if s := tw.Stats(); s.Pending != 3 || s.Evicted != 1 {
+			t.Errorf("%s: expected 3 pending and 1 evicted, got %d and %d", policy, s.Pending, s.Evicted)
+		}
+		tw.Stop()
+	}
+}
@@ -0,0 +1,82 @@
+package timewheel
+
+import (
+	"log/slog"
+	"time"
+)
+
+// WithCatchUp detects ticks that arrive more than threshold after the
+// previous one, as after a host suspend or a clock jump. Instead of stepping
+// through the gap slot by slot, the wheel fires everything that became due
+// during it at once and re-places the rest relative to the new time. onCatchUp,
+// if not nil, is told the gap and how many tasks fired late.
+func WithCatchUp(threshold time.Duration, onCatchUp func(gap time.Duration, late int)) Option {
+	return func(tw *TimeWheel) {
+		tw.catchUpThreshold = threshold
+		tw.onCatchUp = onCatchUp
+	}
+}
+
+// tickGap measures the time since the previous tick. The monotonic clock may
+// stand still while the host is suspended, so the wall clock is consulted too.
+func tickGap(prev, now time.Time) time.Duration {
+	gap := now.Sub(prev)
+	if wall := now.Round(0).Sub(prev.Round(0)); wall > gap {
+		gap = wall
+	}
+	return gap
+}
+
+func (tw *TimeWheel) catchUp(now time.Time, gap time.Duration) {
+	tw.mu.Lock()
+	if tw.stopped() {
+		tw.unlock()
+		return
+	}
+	late := tw.fastForward(now)
+	n := len(late)
+	late = tw.applyBudget(late)
+	tw.startedAt = now
+	tw.ticksDone = 0
+	tw.unlock()
+
+	tw.counters.catchUps.Add(1)
+	tw.counters.late.Add(uint64(n))
+	tw.log(slog.LevelWarn, "timewheel: caught up after a tick gap", "gap", gap, "late", n)
+	tw.dispatch(late)
+	if tw.onCatchUp != nil {
+		tw.onCatchUp(gap, n)
+	}
+}
+
+// fastForward returns the entries due by now and re-places the others.
+// Deadlines are compared on the wall clock for the same reason as tickGap,
+// then re-stamped from now so later monotonic comparisons hold.
+func (tw *TimeWheel) fastForward(now time.Time) []*taskEntry {
+	var late []*taskEntry
+	for _, entry := range tw.keyMap {
+		if entry.layerIndex < 0 {
+			continue
+		}
+
+		remaining := entry.expiration.Round(0).Sub(now.Round(0))
+		tw.unlink(entry)
+		entry.expiration = now.Add(remaining)
+
+		targetLayer, targetPos, rounds := tw.findPosition(remaining)
+		if targetLayer != nil {
+			tw.place(entry, targetLayer, targetPos, rounds)
+			continue
+		}
+
+		if tw.holding(entry) {
+			tw.park(entry)
+			continue
+		}
+		tw.untrack(entry)
+		tw.board(entry)
+		late = append(late, entry)
+	}
+	sortExpired(late)
+	return late
+}
@@ -0,0 +1,48 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCatchUp(t *testing.T) {
+	fired := make(chan string, 2)
+	caughtUp := make(chan int, 1)
+	clock := newFakeClock()
+	tw := NewTimeWheel(time.Second, 10, func(k string, v any) {
+		fired <- k
+	}, WithClock(clock), WithCatchUp(5*time.Second, func(gap time.Duration, late int) {
+		caughtUp <- late
+	}))
+	defer tw.Stop()
+
+	tw.Set("due", "data", 3*time.Second)
+	tw.Set("later", "data", 30*time.Second)
+
+	clock.tick(20 * time.Second)
+	if late := <-caughtUp; late != 1 {
+		t.Errorf("Expected 1 late task, got %d", late)
+	}
+	if k := <-fired; k != "due" {
+		t.Errorf("Expected due to fire during catch-up, got %s", k)
+	}
+
+	for i := 0; i < 9; i++ {
+		clock.tick(time.Second)
+	}
+	select {
+	case k := <-fired:
+		t.Fatalf("%s fired before its deadline", k)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	clock.tick(time.Second)
+	select {
+	case k := <-fired:
+		if k != "later" {
+			t.Errorf("Expected later, got %s", k)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Remaining task did not fire at its deadline after catch-up")
+	}
+}
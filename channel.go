@@ -0,0 +1,157 @@
+package timewheel
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ExpiredTask is a task delivered on the Expired channel.
+type ExpiredTask struct {
+	Key         string
+	Value       any
+	Expiration  time.Time
+	Annotations map[string]string
+	Tags        []string
+	// FiredAt is when the wheel fired the task, at or after Expiration.
+	FiredAt time.Time
+}
+
+// OverflowPolicy decides what happens when the Expired channel is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock makes the tick wait for the consumer (default).
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest discards the task that does not fit.
+	OverflowDropNewest
+	// OverflowDropOldest discards the oldest buffered task to make room.
+	OverflowDropOldest
+	// OverflowReject makes SetWith return ErrBackpressure while the channel
+	// is full, pushing back on producers; a task coming due meanwhile waits
+	// for the consumer as with OverflowBlock.
+	OverflowReject
+)
+
+type expiredChan struct {
+	mu     sync.RWMutex
+	ch     chan ExpiredTask
+	policy OverflowPolicy
+	closed bool
+}
+
+// WithExpiredChannel delivers every expiration on a channel of the given
+// buffer size, in addition to the callback. See Expired.
+func WithExpiredChannel(size int, policy OverflowPolicy) Option {
+	return func(tw *TimeWheel) {
+		tw.expired = &expiredChan{
+			ch:     make(chan ExpiredTask, size),
+			policy: policy,
+		}
+	}
+}
+
+// Expired returns the channel configured by WithExpiredChannel, or nil. It is
+// closed when the wheel stops; a restarted wheel delivers on a new one.
+func (tw *TimeWheel) Expired() <-chan ExpiredTask {
+	if tw.expired == nil {
+		return nil
+	}
+	tw.expired.mu.RLock()
+	defer tw.expired.mu.RUnlock()
+	return tw.expired.ch
+}
+
+func (tw *TimeWheel) emit(entry *taskEntry) {
+	ec := tw.expired
+	if ec == nil {
+		return
+	}
+
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+	if ec.closed {
+		return
+	}
+
+	task := entry.expiredTask()
+	switch ec.policy {
+	case OverflowDropNewest:
+		select {
+		case ec.ch <- task:
+		default:
+			tw.counters.dropped.Add(1)
+			tw.log(slog.LevelWarn, "timewheel: expired channel full, dropped newest", "key", task.Key)
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case ec.ch <- task:
+				return
+			default:
+			}
+			select {
+			case old := <-ec.ch:
+				tw.counters.dropped.Add(1)
+				tw.log(slog.LevelWarn, "timewheel: expired channel full, dropped oldest", "key", old.Key)
+			default:
+			}
+		}
+	default:
+		select {
+		case ec.ch <- task:
+		case <-tw.quit():
+		}
+	}
+}
+
+// backpressure reports whether Set must refuse tasks under OverflowReject.
+func (tw *TimeWheel) backpressure() bool {
+	ec := tw.expired
+	if ec == nil || ec.policy != OverflowReject {
+		return false
+	}
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+	return !ec.closed && len(ec.ch) == cap(ec.ch)
+}
+
+func (entry *taskEntry) expiredTask() ExpiredTask {
+	return ExpiredTask{
+		Key:         entry.key,
+		Value:       entry.value,
+		Expiration:  entry.expiration,
+		Annotations: entry.annotations,
+		Tags:        entry.tags,
+		FiredAt:     entry.firedAt,
+	}
+}
+
+// reopenExpired replaces the channel closed by Stop when the wheel restarts.
+func (tw *TimeWheel) reopenExpired() {
+	ec := tw.expired
+	if ec == nil {
+		return
+	}
+
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	if ec.closed {
+		ec.ch = make(chan ExpiredTask, cap(ec.ch))
+		ec.closed = false
+	}
+}
+
+func (tw *TimeWheel) closeExpired() {
+	ec := tw.expired
+	if ec == nil {
+		return
+	}
+
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	if !ec.closed {
+		ec.closed = true
+		close(ec.ch)
+	}
+}
@@ -0,0 +1,73 @@
+package timewheel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExpiredChannel(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil, WithExpiredChannel(0, OverflowBlock))
+
+	received := make(chan ExpiredTask, 2)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for task := range tw.Expired() {
+			received <- task
+		}
+	}()
+
+	tw.Set("a", 1, ManualInterval)
+	tw.Set("b", 2, 2*ManualInterval)
+	tw.Advance(2 * ManualInterval)
+
+	for _, want := range []string{"a", "b"} {
+		select {
+		case task := <-received:
+			if task.Key != want {
+				t.Errorf("Expected %s, got %s", want, task.Key)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Did not receive %s", want)
+		}
+	}
+
+	tw.Stop()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expired channel was not closed on Stop")
+	}
+}
+
+func TestExpiredChannelDropOldest(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil, WithExpiredChannel(1, OverflowDropOldest))
+	defer tw.Stop()
+
+	tw.Set("a", 1, ManualInterval)
+	tw.Set("b", 2, 2*ManualInterval)
+	tw.Advance(2 * ManualInterval)
+
+	if task := <-tw.Expired(); task.Key != "b" {
+		t.Errorf("Expected newest task b to survive, got %s", task.Key)
+	}
+	if n := tw.Metrics()[MetricDroppedTasks]; n != 1 {
+		t.Errorf("Expected 1 dropped task, got %v", n)
+	}
+}
+
+func TestExpiredChannelReject(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil, WithExpiredChannel(1, OverflowReject))
+	defer tw.Stop()
+
+	tw.Set("a", 1, ManualInterval)
+	tw.Advance(ManualInterval)
+	if err := tw.SetWith("b", 2, ManualInterval); !errors.Is(err, ErrBackpressure) {
+		t.Errorf("Expected ErrBackpressure while the channel is full, got %v", err)
+	}
+	<-tw.Expired()
+	if err := tw.SetWith("b", 2, ManualInterval); err != nil {
+		t.Errorf("Expected Set to succeed once the consumer caught up, got %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package timewheel
+
+import "runtime"
+
+// defaultTickChunk is how many entries of one slot a tick walks before it
+// lets waiting writers in.
+const defaultTickChunk = 1024
+
+// WithTickChunk makes a tick release the wheel lock after every n entries
+// of a slot, so a slot holding a burst of tasks does not block Set and
+// Delete for the whole of its walk. n <= 0 walks every slot in one go.
+func WithTickChunk(n int) Option {
+	return func(tw *TimeWheel) {
+		tw.tickChunk = n
+	}
+}
+
+// yield releases the lock halfway through b, with a marker in front of
+// entry holding the walk's place, and returns the entry to resume at. It
+// returns nil if the walk is over: writers removed everything after the
+// marker, or the wheel's tasks were cleared.
+func (tw *TimeWheel) yield(b *bucket, entry *taskEntry) *taskEntry {
+	marker := &taskEntry{}
+	b.mark(marker, entry)
+	clears := tw.clears
+	tw.unlock()
+	runtime.Gosched()
+	tw.mu.Lock()
+
+	if tw.clears != clears {
+		return nil
+	}
+	next := marker.next
+	b.detach(marker)
+	return next
+}
+
+// isMarker reports whether entry is a marker left by yield: tasks are
+// numbered and maintenance entries keyed, so only markers have neither.
+func (entry *taskEntry) isMarker() bool {
+	return entry.key == "" && entry.seq == 0
+}
@@ -0,0 +1,74 @@
+package timewheel
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestTickChunkWithConcurrentWriters(t *testing.T) {
+	const tasks = 5000
+	var fired atomic.Int64
+	tw := NewTimeWheel(0, 10, func(string, any) { fired.Add(1) },
+		WithSyncCallbacks(0), WithTickChunk(16))
+	defer tw.Stop()
+
+	for i := 0; i < tasks; i++ {
+		tw.Set(fmt.Sprint("key", i), nil, 2*ManualInterval)
+	}
+
+	// Deletes race the walk of the slot; each task either fires or is deleted
+	var deleted atomic.Int64
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			<-start
+			for i := w; i < tasks; i += 4 {
+				if _, existed := tw.Delete(fmt.Sprint("key", i)); existed {
+					deleted.Add(1)
+				}
+				tw.Set(fmt.Sprint("late", w, i), nil, 5*ManualInterval)
+			}
+		}(w)
+	}
+	tw.Advance(ManualInterval)
+	close(start)
+	tw.Advance(ManualInterval)
+	wg.Wait()
+
+	if got := fired.Load() + deleted.Load(); got != tasks {
+		t.Errorf("Expected %d tasks to fire or be deleted, got %d fired and %d deleted", tasks, fired.Load(), deleted.Load())
+	}
+	if n := tw.Stats().Pending; n != tasks {
+		t.Errorf("Expected the %d tasks set meanwhile to be pending, got %d", tasks, n)
+	}
+}
+
+func TestTickChunkFlushedMidWalk(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil, WithTickChunk(1))
+	defer tw.Stop()
+
+	for i := 0; i < 100; i++ {
+		tw.Set(fmt.Sprint("key", i), nil, ManualInterval)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		tw.FlushAll()
+	}()
+	tw.Advance(ManualInterval)
+	<-done
+
+	if n := tw.Stats().Pending; n != 0 {
+		t.Errorf("Expected no pending tasks, got %d", n)
+	}
+	tw.Set("after", nil, ManualInterval)
+	tw.Advance(ManualInterval)
+	if n := tw.Stats().Pending; n != 0 {
+		t.Errorf("Expected the wheel to keep working after the flush, got %d pending", n)
+	}
+}
@@ -0,0 +1,69 @@
+package timewheel
+
+import "time"
+
+// Clock is the wheel's source of time and ticks.
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks like time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// WithClock replaces the platform clock driving a ticker-based wheel.
+func WithClock(c Clock) Option {
+	return func(tw *TimeWheel) {
+		tw.clock = c
+	}
+}
+
+// WithNow replaces only the wheel's reading of the current time, keeping the
+// clock's tickers: deadlines are computed from now and ticks compare against
+// it. It suits tests faking time on a ticker-driven wheel; WithClock or
+// WithRuntime replace the ticks as well. It applies whatever the order of
+// the options.
+func WithNow(now func() time.Time) Option {
+	return func(tw *TimeWheel) {
+		tw.nowFunc = now
+	}
+}
+
+type nowClock struct {
+	Clock
+	now func() time.Time
+}
+
+func (c nowClock) Now() time.Time {
+	return c.now()
+}
+
+// SystemClock returns the Clock backed by the time package.
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+type systemTicker struct {
+	t *time.Ticker
+}
+
+func (st systemTicker) C() <-chan time.Time {
+	return st.t.C
+}
+
+func (st systemTicker) Stop() {
+	st.t.Stop()
+}
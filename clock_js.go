@@ -0,0 +1,82 @@
+//go:build js && wasm
+
+package timewheel
+
+import (
+	"sync"
+	"syscall/js"
+	"time"
+)
+
+func defaultClock() Clock {
+	return SetTimeoutClock()
+}
+
+// SetTimeoutClock returns a Clock whose tickers are driven by the host's
+// setTimeout, so the wheel ticks from the JavaScript event loop.
+func SetTimeoutClock() Clock {
+	return jsClock{}
+}
+
+type jsClock struct{}
+
+func (jsClock) Now() time.Time {
+	return time.Now()
+}
+
+func (jsClock) NewTicker(d time.Duration) Ticker {
+	t := &jsTicker{
+		c:    make(chan time.Time, 1),
+		stop: make(chan struct{}),
+		next: time.Now().Add(d),
+	}
+
+	t.fn = js.FuncOf(func(this js.Value, args []js.Value) any {
+		select {
+		case <-t.stop:
+			return nil
+		default:
+		}
+
+		now := time.Now()
+		// Drop ticks for a slow receiver, as time.Ticker does
+		select {
+		case t.c <- now:
+		default:
+		}
+
+		// Aim at the next multiple of d so setTimeout latency does not accumulate
+		for !t.next.After(now) {
+			t.next = t.next.Add(d)
+		}
+		t.schedule(t.next.Sub(now))
+		return nil
+	})
+	t.schedule(d)
+	return t
+}
+
+type jsTicker struct {
+	c    chan time.Time
+	stop chan struct{}
+	once sync.Once
+	fn   js.Func
+	id   js.Value
+	next time.Time
+}
+
+func (t *jsTicker) schedule(d time.Duration) {
+	t.id = js.Global().Call("setTimeout", t.fn, float64(d)/float64(time.Millisecond))
+}
+
+func (t *jsTicker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *jsTicker) Stop() {
+	t.once.Do(func() {
+		close(t.stop)
+		js.Global().Call("clearTimeout", t.id)
+		t.fn.Release()
+	})
+}
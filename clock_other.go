@@ -0,0 +1,7 @@
+//go:build !(js && wasm)
+
+package timewheel
+
+func defaultClock() Clock {
+	return systemClock{}
+}
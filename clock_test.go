@@ -0,0 +1,108 @@
+package timewheel
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+	c   chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(0, 0), c: make(chan time.Time)}
+}
+
+func (fc *fakeClock) Now() time.Time {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.now
+}
+
+func (fc *fakeClock) NewTicker(d time.Duration) Ticker {
+	return fakeTicker{fc.c}
+}
+
+func (fc *fakeClock) tick(d time.Duration) {
+	fc.mu.Lock()
+	fc.now = fc.now.Add(d)
+	now := fc.now
+	fc.mu.Unlock()
+	fc.c <- now
+}
+
+type fakeTicker struct {
+	c chan time.Time
+}
+
+func (ft fakeTicker) C() <-chan time.Time {
+	return ft.c
+}
+
+func (ft fakeTicker) Stop() {}
+
+func TestCustomClock(t *testing.T) {
+	fired := make(chan string, 1)
+	clock := newFakeClock()
+	tw := NewTimeWheel(time.Second, 10, func(k string, v any) {
+		fired <- k
+	}, WithClock(clock))
+	defer tw.Stop()
+
+	tw.Set("test", "data", 2*time.Second)
+	clock.tick(time.Second)
+	select {
+	case <-fired:
+		t.Fatal("Callback fired before its deadline")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	clock.tick(time.Second)
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("Callback did not fire on the clock's ticks")
+	}
+}
+
+func TestWithNow(t *testing.T) {
+	var mu sync.Mutex
+	now := time.Unix(1000, 0)
+	fired := make(chan string, 1)
+	tw := NewTimeWheel(5*time.Millisecond, 10, func(k string, v any) {
+		fired <- k
+	}, WithCatchUp(time.Minute, nil), WithNow(func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}))
+	defer tw.Stop()
+
+	tw.Set("hour", "data", time.Hour)
+	if got := tw.Stats().Pending; got != 1 {
+		t.Fatalf("Expected the task pending, got %d", got)
+	}
+
+	// The real ticker keeps running; only the wheel's reading of time moves
+	mu.Lock()
+	now = now.Add(time.Hour)
+	mu.Unlock()
+	select {
+	case k := <-fired:
+		if k != "hour" {
+			t.Errorf("Expected hour to fire, got %s", k)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the faked hour to pass on the next tick")
+	}
+}
+
+// sleep moves the clock without delivering a tick, as while nothing listens.
+func (fc *fakeClock) sleep(d time.Duration) {
+	fc.mu.Lock()
+	fc.now = fc.now.Add(d)
+	fc.mu.Unlock()
+}
@@ -0,0 +1,162 @@
+package timewheel
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Coordinator is a distributed lock used to elect the one node whose wheel
+// fires callbacks for a schedule shared by a cluster. Implementations wrap
+// etcd leases, Redis locks and the like; see the redis subpackage.
+type Coordinator interface {
+	// TryLead takes or renews leadership for id for ttl and reports whether
+	// id leads.
+	TryLead(ctx context.Context, id string, ttl time.Duration) (bool, error)
+	// Resign gives up leadership held by id.
+	Resign(ctx context.Context, id string) error
+}
+
+type cluster struct {
+	coordinator Coordinator
+	id          string
+	ttl         time.Duration
+	leader      atomic.Bool
+	onChange    func(leader bool)
+
+	mu sync.Mutex
+	// standby holds tasks that expired while this node followed, for one
+	// ttl, so a node promoted after the leader died can fire what the dead
+	// leader may have missed.
+	standby []standbyTask
+	done    chan struct{}
+}
+
+type standbyTask struct {
+	entry     *taskEntry
+	expiredAt time.Time
+}
+
+// WithCoordinator makes the wheel fire only while it holds leadership under
+// id. Every node schedules the same tasks; followers keep their expirations
+// for ttl and fire them if promoted in that window, so around a failover a
+// task can fire twice but is not lost. Leadership is renewed every ttl/3.
+func WithCoordinator(c Coordinator, id string, ttl time.Duration) Option {
+	return func(tw *TimeWheel) {
+		tw.cluster = &cluster{
+			coordinator: c,
+			id:          id,
+			ttl:         ttl,
+			done:        make(chan struct{}),
+		}
+	}
+}
+
+// WithLeadershipHandler is told whenever the node gains or loses leadership.
+func WithLeadershipHandler(h func(leader bool)) Option {
+	return func(tw *TimeWheel) {
+		if tw.cluster != nil {
+			tw.cluster.onChange = h
+		}
+	}
+}
+
+// IsLeader reports whether this wheel fires callbacks. Wheels without a
+// coordinator always do.
+func (tw *TimeWheel) IsLeader() bool {
+	return tw.cluster == nil || tw.cluster.leader.Load()
+}
+
+func (tw *TimeWheel) startCluster() {
+	if tw.cluster == nil {
+		return
+	}
+	tw.campaign()
+	tw.runtime.Go(tw.runCluster)
+}
+
+func (tw *TimeWheel) runCluster() {
+	c := tw.cluster
+	defer close(c.done)
+
+	quit := tw.quit()
+	ticker := tw.clock.NewTicker(c.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C():
+			tw.campaign()
+		case <-quit:
+			if c.leader.Load() {
+				ctx, cancel := context.WithTimeout(context.Background(), c.ttl)
+				c.coordinator.Resign(ctx, c.id)
+				cancel()
+			}
+			return
+		}
+	}
+}
+
+// campaign renews or seeks leadership; an error counts as not leading.
+func (tw *TimeWheel) campaign() {
+	c := tw.cluster
+	ctx, cancel := context.WithTimeout(context.Background(), c.ttl)
+	leader, err := c.coordinator.TryLead(ctx, c.id, c.ttl)
+	cancel()
+	if err != nil {
+		tw.log(slog.LevelWarn, "timewheel: leadership campaign failed", "id", c.id, "err", err)
+	}
+	leader = leader && err == nil
+
+	if c.leader.Swap(leader) == leader {
+		return
+	}
+	if c.onChange != nil {
+		c.onChange(leader)
+	}
+	if leader {
+		tw.fireStandby()
+	}
+}
+
+// follow keeps entry on standby when another node leads and reports whether
+// it did.
+func (tw *TimeWheel) follow(entry *taskEntry) bool {
+	c := tw.cluster
+	if c == nil || c.leader.Load() {
+		return false
+	}
+
+	now := tw.clock.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	kept := c.standby[:0]
+	for _, t := range c.standby {
+		if now.Sub(t.expiredAt) < c.ttl {
+			kept = append(kept, t)
+		}
+	}
+	c.standby = append(kept, standbyTask{entry: entry, expiredAt: now})
+	return true
+}
+
+func (tw *TimeWheel) fireStandby() {
+	c := tw.cluster
+	now := tw.clock.Now()
+
+	c.mu.Lock()
+	standby := c.standby
+	c.standby = nil
+	c.mu.Unlock()
+
+	var due []*taskEntry
+	for _, t := range standby {
+		if now.Sub(t.expiredAt) < c.ttl {
+			due = append(due, t.entry)
+		}
+	}
+	tw.dispatch(due)
+}
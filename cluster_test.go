@@ -0,0 +1,90 @@
+package timewheel
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lockCoordinator is an in-process Coordinator; expire simulates a dead
+// leader's lock lapsing.
+type lockCoordinator struct {
+	mu    sync.Mutex
+	owner string
+}
+
+func (c *lockCoordinator) TryLead(ctx context.Context, id string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.owner == "" {
+		c.owner = id
+	}
+	return c.owner == id, nil
+}
+
+func (c *lockCoordinator) Resign(ctx context.Context, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.owner == id {
+		c.owner = ""
+	}
+	return nil
+}
+
+func (c *lockCoordinator) expire() {
+	c.mu.Lock()
+	c.owner = ""
+	c.mu.Unlock()
+}
+
+func TestLeaderElection(t *testing.T) {
+	coord := &lockCoordinator{}
+	fired := make(chan string, 4)
+	newNode := func(id string) *TimeWheel {
+		return NewTimeWheel(0, 10, func(k string, v any) {
+			fired <- id + ":" + k
+		}, WithCoordinator(coord, id, 30*time.Millisecond))
+	}
+
+	a := newNode("a")
+	b := newNode("b")
+	defer b.Stop()
+	if !a.IsLeader() || b.IsLeader() {
+		t.Fatalf("Expected a to lead and b to follow, got %v and %v", a.IsLeader(), b.IsLeader())
+	}
+
+	for _, tw := range []*TimeWheel{a, b} {
+		tw.Set("first", "data", ManualInterval)
+		tw.Tick()
+	}
+	select {
+	case got := <-fired:
+		if got != "a:first" {
+			t.Fatalf("Expected only the leader to fire, got %s", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Leader did not fire")
+	}
+
+	// a stops and resigns: once promoted, b fires every task it holds on
+	// standby, which includes "first" from the tick above
+	b.Set("second", "data", ManualInterval)
+	b.Tick()
+	a.Stop()
+	coord.expire()
+
+	seen := make(map[string]bool)
+	timeout := time.After(time.Second)
+	for !seen["b:second"] {
+		select {
+		case got := <-fired:
+			seen[got] = true
+		case <-timeout:
+			t.Fatalf("Expected the promoted node to fire the missed task, got %v", seen)
+		}
+	}
+	if !b.IsLeader() {
+		t.Error("Expected b to lead after failover")
+	}
+}
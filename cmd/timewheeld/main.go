@@ -0,0 +1,69 @@
+// Command timewheeld serves a time wheel over gRPC, so services in any
+// language can use it as a lightweight delay or TTL service: they Set keys
+// with an opaque payload and get them back on a Subscribe stream when they
+// expire. The service is defined in timewheelpb/timewheel.proto.
+//
+// Expirations go to the streams open at the time; with none open they are
+// dropped.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/nzai/timewheel"
+	"github.com/nzai/timewheel/cmd/timewheeld/timewheelpb"
+)
+
+func main() {
+	var (
+		addr     = flag.String("addr", ":7070", "listen address")
+		interval = flag.Duration("interval", 10*time.Millisecond, "base interval")
+		slots    = flag.Int("slots", 60, "slots per layer")
+		buffer   = flag.Int("buffer", 1024, "expirations buffered per subscriber before it misses some")
+		walPath  = flag.String("wal", "", "write-ahead log, so pending tasks survive a restart")
+	)
+	flag.Parse()
+
+	var opts []timewheel.Option
+	if *walPath != "" {
+		w, err := timewheel.OpenWAL(*walPath, timewheel.WALCodec(timewheel.GobCodec()))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "timewheeld:", err)
+			os.Exit(1)
+		}
+		defer w.Close()
+		opts = append(opts, timewheel.WithWAL(w))
+	}
+
+	lis, err := net.Listen("tcp", *addr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "timewheeld:", err)
+		os.Exit(1)
+	}
+	s := newServer(*interval, *slots, *buffer, opts...)
+	gs := grpc.NewServer()
+	timewheelpb.RegisterTimeWheelServer(gs, s)
+
+	// Subscribe streams only end with the wheel, so stop it before
+	// draining the server
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		s.stop()
+		gs.GracefulStop()
+	}()
+
+	if err := gs.Serve(lis); err != nil {
+		fmt.Fprintln(os.Stderr, "timewheeld:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/nzai/timewheel"
+	"github.com/nzai/timewheel/cmd/timewheeld/timewheelpb"
+)
+
+// server implements the TimeWheel service on one wheel, fanning its
+// expirations out to every subscriber whose prefix matches.
+type server struct {
+	timewheelpb.UnimplementedTimeWheelServer
+	tw     *timewheel.TimeWheel
+	buffer int
+
+	mu sync.Mutex
+	// subs is nil once the wheel has stopped.
+	subs map[*subscriber]struct{}
+	done chan struct{}
+}
+
+type subscriber struct {
+	prefix string
+	ch     chan *timewheelpb.Expiration
+}
+
+func newServer(interval time.Duration, slots, buffer int, opts ...timewheel.Option) *server {
+	s := &server{
+		buffer: buffer,
+		subs:   make(map[*subscriber]struct{}),
+		done:   make(chan struct{}),
+	}
+	opts = append(opts, timewheel.WithExpiredChannel(buffer, timewheel.OverflowBlock))
+	s.tw = timewheel.NewTimeWheel(interval, slots, nil, opts...)
+	go s.fanOut()
+	return s
+}
+
+// stop stops the wheel and ends every Subscribe stream.
+func (s *server) stop() {
+	s.tw.Stop()
+	<-s.done
+}
+
+func (s *server) Set(ctx context.Context, req *timewheelpb.SetRequest) (*timewheelpb.SetResponse, error) {
+	if err := s.check(req.Key); err != nil {
+		return nil, err
+	}
+	// Swap reports a wheel stopped since check, which Set would hide
+	prev, replaced, err := s.tw.Swap(req.Key, req.Value, time.Duration(req.TtlMillis)*time.Millisecond)
+	if err != nil {
+		return nil, setStatus(err)
+	}
+	return &timewheelpb.SetResponse{Replaced: replaced, PreviousTtlMillis: prev.Milliseconds()}, nil
+}
+
+func (s *server) Delete(ctx context.Context, req *timewheelpb.DeleteRequest) (*timewheelpb.DeleteResponse, error) {
+	if err := s.check(req.Key); err != nil {
+		return nil, err
+	}
+	remaining, existed := s.tw.Delete(req.Key)
+	return &timewheelpb.DeleteResponse{Existed: existed, RemainingMillis: remaining.Milliseconds()}, nil
+}
+
+func (s *server) Move(ctx context.Context, req *timewheelpb.MoveRequest) (*timewheelpb.MoveResponse, error) {
+	if err := s.check(req.Key); err != nil {
+		return nil, err
+	}
+	prev, existed := s.tw.Move(req.Key, time.Duration(req.TtlMillis)*time.Millisecond)
+	return &timewheelpb.MoveResponse{Existed: existed, PreviousTtlMillis: prev.Milliseconds()}, nil
+}
+
+// Subscribe streams matching expirations from the moment it is called. A
+// subscriber more than the buffer size behind misses the overflow.
+func (s *server) Subscribe(req *timewheelpb.SubscribeRequest, stream timewheelpb.TimeWheel_SubscribeServer) error {
+	sub := &subscriber{prefix: req.Prefix, ch: make(chan *timewheelpb.Expiration, s.buffer)}
+	s.mu.Lock()
+	if s.subs == nil {
+		s.mu.Unlock()
+		return status.Error(codes.Unavailable, timewheel.ErrStopped.Error())
+	}
+	s.subs[sub] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.subs, sub)
+		s.mu.Unlock()
+	}()
+	for {
+		select {
+		case e, ok := <-sub.ch:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(e); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+func (s *server) check(key string) error {
+	if key == "" {
+		return status.Error(codes.InvalidArgument, "timewheeld: empty key")
+	}
+	if !s.tw.Running() {
+		return status.Error(codes.Unavailable, timewheel.ErrStopped.Error())
+	}
+	return nil
+}
+
+// setStatus maps an error from SetWith to a gRPC status.
+func setStatus(err error) error {
+	switch {
+	case errors.Is(err, timewheel.ErrStopped):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, timewheel.ErrOverCapacity):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// fanOut copies each expiration to the matching subscribers until the
+// wheel's channel closes, then closes theirs.
+func (s *server) fanOut() {
+	for task := range s.tw.Expired() {
+		e := &timewheelpb.Expiration{Key: task.Key, ExpiredAtUnixNano: task.Expiration.UnixNano()}
+		e.Value, _ = task.Value.([]byte)
+
+		s.mu.Lock()
+		for sub := range s.subs {
+			if !strings.HasPrefix(task.Key, sub.prefix) {
+				continue
+			}
+			select {
+			case sub.ch <- e:
+			default:
+				slog.Warn("timewheeld: subscriber falling behind, dropping expiration", "key", task.Key)
+			}
+		}
+		s.mu.Unlock()
+	}
+
+	s.mu.Lock()
+	for sub := range s.subs {
+		close(sub.ch)
+	}
+	s.subs = nil
+	s.mu.Unlock()
+	close(s.done)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/nzai/timewheel"
+	"github.com/nzai/timewheel/cmd/timewheeld/timewheelpb"
+)
+
+// dial serves s in memory and returns a client for it.
+func dial(t *testing.T, s *server) timewheelpb.TimeWheelClient {
+	lis := bufconn.Listen(1 << 20)
+	gs := grpc.NewServer()
+	timewheelpb.RegisterTimeWheelServer(gs, s)
+	go gs.Serve(lis)
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return timewheelpb.NewTimeWheelClient(conn)
+}
+
+// subscribed waits until the server has n subscribers.
+func subscribed(t *testing.T, s *server, n int) {
+	for i := 0; i < 100; i++ {
+		s.mu.Lock()
+		got := len(s.subs)
+		s.mu.Unlock()
+		if got == n {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("Expected %d subscribers", n)
+}
+
+func TestServer(t *testing.T) {
+	s := newServer(10*time.Millisecond, 60, 16)
+	client := dial(t, s)
+	ctx := context.Background()
+
+	stream, err := client.Subscribe(ctx, &timewheelpb.SubscribeRequest{Prefix: "job:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	subscribed(t, s, 1)
+
+	client.Set(ctx, &timewheelpb.SetRequest{Key: "job:a", Value: []byte("payload"), TtlMillis: 30})
+	client.Set(ctx, &timewheelpb.SetRequest{Key: "other", TtlMillis: 10})
+	client.Set(ctx, &timewheelpb.SetRequest{Key: "job:deleted", TtlMillis: 20})
+	if resp, err := client.Delete(ctx, &timewheelpb.DeleteRequest{Key: "job:deleted"}); err != nil || !resp.Existed {
+		t.Errorf("Expected Delete to cancel the task, got %v, %v", resp, err)
+	}
+	client.Set(ctx, &timewheelpb.SetRequest{Key: "job:moved", TtlMillis: 5000})
+	if resp, err := client.Move(ctx, &timewheelpb.MoveRequest{Key: "job:moved", TtlMillis: 60}); err != nil || !resp.Existed {
+		t.Errorf("Expected Move to reschedule the task, got %v, %v", resp, err)
+	}
+
+	for _, want := range []string{"job:a", "job:moved"} {
+		e, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Key != want {
+			t.Errorf("Expected %s to expire, got %s", want, e.Key)
+		}
+		if want == "job:a" && string(e.Value) != "payload" {
+			t.Errorf("Expected the payload back, got %q", e.Value)
+		}
+	}
+
+	_, err = client.Set(ctx, &timewheelpb.SetRequest{TtlMillis: 10})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected an empty key to be rejected, got %v", err)
+	}
+
+	// Stopping ends the stream and refuses further calls
+	s.stop()
+	if _, err := stream.Recv(); err == nil {
+		t.Error("Expected the stream to end with the wheel")
+	}
+	_, err = client.Set(ctx, &timewheelpb.SetRequest{Key: "late", TtlMillis: 10})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable after stop, got %v", err)
+	}
+}
+
+func TestServerSetErrors(t *testing.T) {
+	s := newServer(10*time.Millisecond, 60, 16, timewheel.WithCapacity(1, timewheel.EvictRejectNew))
+	defer s.stop()
+	client := dial(t, s)
+	ctx := context.Background()
+
+	if _, err := client.Set(ctx, &timewheelpb.SetRequest{Key: "a", TtlMillis: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.Set(ctx, &timewheelpb.SetRequest{Key: "b", TtlMillis: 1000})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted beyond the capacity, got %v", err)
+	}
+	resp, err := client.Set(ctx, &timewheelpb.SetRequest{Key: "a", TtlMillis: 1000})
+	if err != nil || !resp.Replaced {
+		t.Errorf("Expected the pending task to be replaced, got %v %v", resp, err)
+	}
+
+	if err := setStatus(timewheel.ErrStopped); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected ErrStopped to map to Unavailable, got %v", err)
+	}
+}
@@ -0,0 +1,5 @@
+// Package timewheelpb holds the gRPC bindings of timewheeld, generated from
+// timewheel.proto.
+package timewheelpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative timewheel.proto
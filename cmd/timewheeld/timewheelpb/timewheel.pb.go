@@ -0,0 +1,547 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: timewheel.proto
+
+// A time wheel served over gRPC: keys scheduled with Set come back on
+// Subscribe streams when they expire.
+
+package timewheelpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SetRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Opaque payload handed back on expiration.
+	Value         []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	TtlMillis     int64  `protobuf:"varint,3,opt,name=ttl_millis,json=ttlMillis,proto3" json:"ttl_millis,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRequest) Reset() {
+	*x = SetRequest{}
+	mi := &file_timewheel_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRequest) ProtoMessage() {}
+
+func (x *SetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_timewheel_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRequest.ProtoReflect.Descriptor instead.
+func (*SetRequest) Descriptor() ([]byte, []int) {
+	return file_timewheel_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SetRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *SetRequest) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *SetRequest) GetTtlMillis() int64 {
+	if x != nil {
+		return x.TtlMillis
+	}
+	return 0
+}
+
+type SetResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether a pending task was replaced, and the time it had left.
+	Replaced          bool  `protobuf:"varint,1,opt,name=replaced,proto3" json:"replaced,omitempty"`
+	PreviousTtlMillis int64 `protobuf:"varint,2,opt,name=previous_ttl_millis,json=previousTtlMillis,proto3" json:"previous_ttl_millis,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SetResponse) Reset() {
+	*x = SetResponse{}
+	mi := &file_timewheel_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetResponse) ProtoMessage() {}
+
+func (x *SetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_timewheel_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetResponse.ProtoReflect.Descriptor instead.
+func (*SetResponse) Descriptor() ([]byte, []int) {
+	return file_timewheel_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SetResponse) GetReplaced() bool {
+	if x != nil {
+		return x.Replaced
+	}
+	return false
+}
+
+func (x *SetResponse) GetPreviousTtlMillis() int64 {
+	if x != nil {
+		return x.PreviousTtlMillis
+	}
+	return 0
+}
+
+type DeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	mi := &file_timewheel_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_timewheel_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_timewheel_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DeleteRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type DeleteResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Existed         bool                   `protobuf:"varint,1,opt,name=existed,proto3" json:"existed,omitempty"`
+	RemainingMillis int64                  `protobuf:"varint,2,opt,name=remaining_millis,json=remainingMillis,proto3" json:"remaining_millis,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	mi := &file_timewheel_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_timewheel_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_timewheel_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DeleteResponse) GetExisted() bool {
+	if x != nil {
+		return x.Existed
+	}
+	return false
+}
+
+func (x *DeleteResponse) GetRemainingMillis() int64 {
+	if x != nil {
+		return x.RemainingMillis
+	}
+	return 0
+}
+
+type MoveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	TtlMillis     int64                  `protobuf:"varint,2,opt,name=ttl_millis,json=ttlMillis,proto3" json:"ttl_millis,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveRequest) Reset() {
+	*x = MoveRequest{}
+	mi := &file_timewheel_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveRequest) ProtoMessage() {}
+
+func (x *MoveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_timewheel_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveRequest.ProtoReflect.Descriptor instead.
+func (*MoveRequest) Descriptor() ([]byte, []int) {
+	return file_timewheel_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MoveRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *MoveRequest) GetTtlMillis() int64 {
+	if x != nil {
+		return x.TtlMillis
+	}
+	return 0
+}
+
+type MoveResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Existed           bool                   `protobuf:"varint,1,opt,name=existed,proto3" json:"existed,omitempty"`
+	PreviousTtlMillis int64                  `protobuf:"varint,2,opt,name=previous_ttl_millis,json=previousTtlMillis,proto3" json:"previous_ttl_millis,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *MoveResponse) Reset() {
+	*x = MoveResponse{}
+	mi := &file_timewheel_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveResponse) ProtoMessage() {}
+
+func (x *MoveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_timewheel_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveResponse.ProtoReflect.Descriptor instead.
+func (*MoveResponse) Descriptor() ([]byte, []int) {
+	return file_timewheel_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *MoveResponse) GetExisted() bool {
+	if x != nil {
+		return x.Existed
+	}
+	return false
+}
+
+func (x *MoveResponse) GetPreviousTtlMillis() int64 {
+	if x != nil {
+		return x.PreviousTtlMillis
+	}
+	return 0
+}
+
+type SubscribeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only keys with this prefix are streamed; empty streams every key.
+	Prefix        string `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_timewheel_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_timewheel_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_timewheel_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SubscribeRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+type Expiration struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value []byte                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// When the task was due, in Unix nanoseconds.
+	ExpiredAtUnixNano int64 `protobuf:"varint,3,opt,name=expired_at_unix_nano,json=expiredAtUnixNano,proto3" json:"expired_at_unix_nano,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Expiration) Reset() {
+	*x = Expiration{}
+	mi := &file_timewheel_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Expiration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Expiration) ProtoMessage() {}
+
+func (x *Expiration) ProtoReflect() protoreflect.Message {
+	mi := &file_timewheel_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Expiration.ProtoReflect.Descriptor instead.
+func (*Expiration) Descriptor() ([]byte, []int) {
+	return file_timewheel_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Expiration) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Expiration) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *Expiration) GetExpiredAtUnixNano() int64 {
+	if x != nil {
+		return x.ExpiredAtUnixNano
+	}
+	return 0
+}
+
+var File_timewheel_proto protoreflect.FileDescriptor
+
+const file_timewheel_proto_rawDesc = "" +
+	"\n" +
+	"\x0ftimewheel.proto\x12\ftimewheel.v1\"S\n" +
+	"\n" +
+	"SetRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value\x12\x1d\n" +
+	"\n" +
+	"ttl_millis\x18\x03 \x01(\x03R\tttlMillis\"Y\n" +
+	"\vSetResponse\x12\x1a\n" +
+	"\breplaced\x18\x01 \x01(\bR\breplaced\x12.\n" +
+	"\x13previous_ttl_millis\x18\x02 \x01(\x03R\x11previousTtlMillis\"!\n" +
+	"\rDeleteRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\"U\n" +
+	"\x0eDeleteResponse\x12\x18\n" +
+	"\aexisted\x18\x01 \x01(\bR\aexisted\x12)\n" +
+	"\x10remaining_millis\x18\x02 \x01(\x03R\x0fremainingMillis\">\n" +
+	"\vMoveRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x1d\n" +
+	"\n" +
+	"ttl_millis\x18\x02 \x01(\x03R\tttlMillis\"X\n" +
+	"\fMoveResponse\x12\x18\n" +
+	"\aexisted\x18\x01 \x01(\bR\aexisted\x12.\n" +
+	"\x13previous_ttl_millis\x18\x02 \x01(\x03R\x11previousTtlMillis\"*\n" +
+	"\x10SubscribeRequest\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\"e\n" +
+	"\n" +
+	"Expiration\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value\x12/\n" +
+	"\x14expired_at_unix_nano\x18\x03 \x01(\x03R\x11expiredAtUnixNano2\x94\x02\n" +
+	"\tTimeWheel\x12:\n" +
+	"\x03Set\x12\x18.timewheel.v1.SetRequest\x1a\x19.timewheel.v1.SetResponse\x12C\n" +
+	"\x06Delete\x12\x1b.timewheel.v1.DeleteRequest\x1a\x1c.timewheel.v1.DeleteResponse\x12=\n" +
+	"\x04Move\x12\x19.timewheel.v1.MoveRequest\x1a\x1a.timewheel.v1.MoveResponse\x12G\n" +
+	"\tSubscribe\x12\x1e.timewheel.v1.SubscribeRequest\x1a\x18.timewheel.v1.Expiration0\x01B6Z4github.com/nzai/timewheel/cmd/timewheeld/timewheelpbb\x06proto3"
+
+var (
+	file_timewheel_proto_rawDescOnce sync.Once
+	file_timewheel_proto_rawDescData []byte
+)
+
+func file_timewheel_proto_rawDescGZIP() []byte {
+	file_timewheel_proto_rawDescOnce.Do(func() {
+		file_timewheel_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_timewheel_proto_rawDesc), len(file_timewheel_proto_rawDesc)))
+	})
+	return file_timewheel_proto_rawDescData
+}
+
+var file_timewheel_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_timewheel_proto_goTypes = []any{
+	(*SetRequest)(nil),       // 0: timewheel.v1.SetRequest
+	(*SetResponse)(nil),      // 1: timewheel.v1.SetResponse
+	(*DeleteRequest)(nil),    // 2: timewheel.v1.DeleteRequest
+	(*DeleteResponse)(nil),   // 3: timewheel.v1.DeleteResponse
+	(*MoveRequest)(nil),      // 4: timewheel.v1.MoveRequest
+	(*MoveResponse)(nil),     // 5: timewheel.v1.MoveResponse
+	(*SubscribeRequest)(nil), // 6: timewheel.v1.SubscribeRequest
+	(*Expiration)(nil),       // 7: timewheel.v1.Expiration
+}
+var file_timewheel_proto_depIdxs = []int32{
+	0, // 0: timewheel.v1.TimeWheel.Set:input_type -> timewheel.v1.SetRequest
+	2, // 1: timewheel.v1.TimeWheel.Delete:input_type -> timewheel.v1.DeleteRequest
+	4, // 2: timewheel.v1.TimeWheel.Move:input_type -> timewheel.v1.MoveRequest
+	6, // 3: timewheel.v1.TimeWheel.Subscribe:input_type -> timewheel.v1.SubscribeRequest
+	1, // 4: timewheel.v1.TimeWheel.Set:output_type -> timewheel.v1.SetResponse
+	3, // 5: timewheel.v1.TimeWheel.Delete:output_type -> timewheel.v1.DeleteResponse
+	5, // 6: timewheel.v1.TimeWheel.Move:output_type -> timewheel.v1.MoveResponse
+	7, // 7: timewheel.v1.TimeWheel.Subscribe:output_type -> timewheel.v1.Expiration
+	4, // [4:8] is the sub-list for method output_type
+	0, // [0:4] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_timewheel_proto_init() }
+func file_timewheel_proto_init() {
+	if File_timewheel_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_timewheel_proto_rawDesc), len(file_timewheel_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_timewheel_proto_goTypes,
+		DependencyIndexes: file_timewheel_proto_depIdxs,
+		MessageInfos:      file_timewheel_proto_msgTypes,
+	}.Build()
+	File_timewheel_proto = out.File
+	file_timewheel_proto_goTypes = nil
+	file_timewheel_proto_depIdxs = nil
+}
@@ -0,0 +1,250 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: timewheel.proto
+
+// A time wheel served over gRPC: keys scheduled with Set come back on
+// Subscribe streams when they expire.
+
+package timewheelpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TimeWheel_Set_FullMethodName       = "/timewheel.v1.TimeWheel/Set"
+	TimeWheel_Delete_FullMethodName    = "/timewheel.v1.TimeWheel/Delete"
+	TimeWheel_Move_FullMethodName      = "/timewheel.v1.TimeWheel/Move"
+	TimeWheel_Subscribe_FullMethodName = "/timewheel.v1.TimeWheel/Subscribe"
+)
+
+// TimeWheelClient is the client API for TimeWheel service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TimeWheelClient interface {
+	// Set schedules value under key, replacing any pending task.
+	Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*SetResponse, error)
+	// Delete cancels a pending task.
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// Move reschedules a pending task.
+	Move(ctx context.Context, in *MoveRequest, opts ...grpc.CallOption) (*MoveResponse, error)
+	// Subscribe streams expirations until the client goes away.
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Expiration], error)
+}
+
+type timeWheelClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTimeWheelClient(cc grpc.ClientConnInterface) TimeWheelClient {
+	return &timeWheelClient{cc}
+}
+
+func (c *timeWheelClient) Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*SetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetResponse)
+	err := c.cc.Invoke(ctx, TimeWheel_Set_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timeWheelClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, TimeWheel_Delete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timeWheelClient) Move(ctx context.Context, in *MoveRequest, opts ...grpc.CallOption) (*MoveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MoveResponse)
+	err := c.cc.Invoke(ctx, TimeWheel_Move_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timeWheelClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Expiration], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TimeWheel_ServiceDesc.Streams[0], TimeWheel_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, Expiration]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TimeWheel_SubscribeClient = grpc.ServerStreamingClient[Expiration]
+
+// TimeWheelServer is the server API for TimeWheel service.
+// All implementations must embed UnimplementedTimeWheelServer
+// for forward compatibility.
+type TimeWheelServer interface {
+	// Set schedules value under key, replacing any pending task.
+	Set(context.Context, *SetRequest) (*SetResponse, error)
+	// Delete cancels a pending task.
+	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	// Move reschedules a pending task.
+	Move(context.Context, *MoveRequest) (*MoveResponse, error)
+	// Subscribe streams expirations until the client goes away.
+	Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[Expiration]) error
+	mustEmbedUnimplementedTimeWheelServer()
+}
+
+// UnimplementedTimeWheelServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTimeWheelServer struct{}
+
+func (UnimplementedTimeWheelServer) Set(context.Context, *SetRequest) (*SetResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Set not implemented")
+}
+func (UnimplementedTimeWheelServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedTimeWheelServer) Move(context.Context, *MoveRequest) (*MoveResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Move not implemented")
+}
+func (UnimplementedTimeWheelServer) Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[Expiration]) error {
+	return status.Error(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedTimeWheelServer) mustEmbedUnimplementedTimeWheelServer() {}
+func (UnimplementedTimeWheelServer) testEmbeddedByValue()                   {}
+
+// UnsafeTimeWheelServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TimeWheelServer will
+// result in compilation errors.
+type UnsafeTimeWheelServer interface {
+	mustEmbedUnimplementedTimeWheelServer()
+}
+
+func RegisterTimeWheelServer(s grpc.ServiceRegistrar, srv TimeWheelServer) {
+	// If the following call panics, it indicates UnimplementedTimeWheelServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TimeWheel_ServiceDesc, srv)
+}
+
+func _TimeWheel_Set_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimeWheelServer).Set(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimeWheel_Set_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimeWheelServer).Set(ctx, req.(*SetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimeWheel_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimeWheelServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimeWheel_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimeWheelServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimeWheel_Move_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimeWheelServer).Move(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TimeWheel_Move_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimeWheelServer).Move(ctx, req.(*MoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TimeWheel_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TimeWheelServer).Subscribe(m, &grpc.GenericServerStream[SubscribeRequest, Expiration]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TimeWheel_SubscribeServer = grpc.ServerStreamingServer[Expiration]
+
+// TimeWheel_ServiceDesc is the grpc.ServiceDesc for TimeWheel service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TimeWheel_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "timewheel.v1.TimeWheel",
+	HandlerType: (*TimeWheelServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Set",
+			Handler:    _TimeWheel_Set_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _TimeWheel_Delete_Handler,
+		},
+		{
+			MethodName: "Move",
+			Handler:    _TimeWheel_Move_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _TimeWheel_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "timewheel.proto",
+}
@@ -0,0 +1,134 @@
+// Command twbench drives a wheel with a configurable load and reports
+// throughput, firing lateness and the wheel's own stats, for comparing
+// tunables and spotting regressions outside the microbenchmarks.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nzai/timewheel"
+)
+
+// task is the value scheduled for each key; the callback measures lateness
+// against its deadline, which Move updates.
+type task struct {
+	deadline atomic.Int64
+}
+
+func main() {
+	var (
+		interval = flag.Duration("interval", 10*time.Millisecond, "base interval")
+		slots    = flag.Int("slots", 60, "slots per layer")
+		shards   = flag.Int("shards", 1, "shards; more than one uses a ShardedTimeWheel")
+		workers  = flag.Int("workers", 8, "concurrent schedulers")
+		keys     = flag.Int("keys", 100_000, "distinct keys per worker")
+		minTTL   = flag.Duration("min-ttl", 100*time.Millisecond, "shortest TTL")
+		maxTTL   = flag.Duration("max-ttl", 5*time.Second, "longest TTL")
+		deletes  = flag.Float64("delete", 0.1, "fraction of operations that delete")
+		moves    = flag.Float64("move", 0.1, "fraction of operations that move")
+		duration = flag.Duration("duration", 10*time.Second, "load duration")
+	)
+	flag.Parse()
+	if *maxTTL < *minTTL {
+		fmt.Fprintln(os.Stderr, "twbench: -max-ttl below -min-ttl")
+		os.Exit(2)
+	}
+
+	var (
+		fired     atomic.Uint64
+		lateTotal atomic.Int64
+		lateMax   atomic.Int64
+	)
+	callback := func(key string, value any) {
+		late := time.Since(time.Unix(0, value.(*task).deadline.Load()))
+		fired.Add(1)
+		lateTotal.Add(int64(late))
+		for {
+			m := lateMax.Load()
+			if int64(late) <= m || lateMax.CompareAndSwap(m, int64(late)) {
+				break
+			}
+		}
+	}
+
+	type wheel interface {
+		Set(key string, value any, expiration time.Duration) (time.Duration, bool)
+		Delete(key string) (time.Duration, bool)
+		Move(key string, expiration time.Duration) (time.Duration, bool)
+		Stop()
+		Stats() timewheel.Stats
+	}
+	var tw wheel
+	if *shards > 1 {
+		tw = timewheel.NewShardedTimeWheel(*shards, *interval, *slots, callback)
+	} else {
+		tw = timewheel.NewTimeWheel(*interval, *slots, callback)
+	}
+
+	var ops atomic.Uint64
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for w := 0; w < *workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			prefix := "w" + strconv.Itoa(w) + ":"
+			// Keys are private to a worker, so this needs no lock
+			tasks := make(map[string]*task)
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				key := prefix + strconv.Itoa(rand.IntN(*keys))
+				ttl := *minTTL + rand.N(*maxTTL-*minTTL+1)
+				switch r := rand.Float64(); {
+				case r < *deletes:
+					tw.Delete(key)
+				case r < *deletes+*moves:
+					if t := tasks[key]; t != nil {
+						t.deadline.Store(time.Now().Add(ttl).UnixNano())
+						tw.Move(key, ttl)
+					}
+				default:
+					t := &task{}
+					t.deadline.Store(time.Now().Add(ttl).UnixNano())
+					tasks[key] = t
+					tw.Set(key, t, ttl)
+				}
+				ops.Add(1)
+			}
+		}(w)
+	}
+
+	start := time.Now()
+	time.Sleep(*duration)
+	close(stop)
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	// Let the longest TTLs come due before reporting
+	time.Sleep(*maxTTL + 2**interval)
+	s := tw.Stats()
+	tw.Stop()
+
+	n := fired.Load()
+	fmt.Printf("ops:        %d (%.0f/s)\n", ops.Load(), float64(ops.Load())/elapsed.Seconds())
+	fmt.Printf("fired:      %d\n", n)
+	if n > 0 {
+		fmt.Printf("late avg:   %s\n", time.Duration(lateTotal.Load()/int64(n)))
+		fmt.Printf("late max:   %s\n", time.Duration(lateMax.Load()))
+	}
+	fmt.Printf("pending:    %d\n", s.Pending)
+	fmt.Printf("set p50/99: %s / %s (lock wait p99 %s)\n",
+		s.SetLatency.Total.Quantile(0.5), s.SetLatency.Total.Quantile(0.99), s.SetLatency.LockWait.Quantile(0.99))
+	fmt.Printf("tick lag:   %s\n", s.TickLag)
+}
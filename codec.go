@@ -0,0 +1,55 @@
+package timewheel
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+)
+
+// Codec turns task values into bytes and back wherever they leave the
+// process: the WAL and the distributed backends.
+type Codec interface {
+	Encode(value any) ([]byte, error)
+	Decode(data []byte) (any, error)
+}
+
+// JSONCodec stores values as JSON. They decode into the types encoding/json
+// produces (map[string]any, float64, ...), not the ones that were stored.
+func JSONCodec() Codec {
+	return jsonCodec{}
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Encode(value any) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (jsonCodec) Decode(data []byte) (any, error) {
+	var value any
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// GobCodec stores values with encoding/gob, so they decode into the types
+// that were stored. Types other than the basic ones must be passed to
+// gob.Register by every process that encodes or decodes them.
+func GobCodec() Codec {
+	return gobCodec{}
+}
+
+type gobCodec struct{}
+
+func (gobCodec) Encode(value any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Decode(data []byte) (any, error) {
+	var value any
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&value)
+	return value, err
+}
@@ -0,0 +1,81 @@
+package timewheel
+
+import (
+	"encoding/gob"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type invoice struct {
+	ID     int
+	Amount float64
+}
+
+func init() {
+	gob.Register(invoice{})
+}
+
+func TestCodecs(t *testing.T) {
+	for name, c := range map[string]Codec{"json": JSONCodec(), "gob": GobCodec()} {
+		data, err := c.Encode("data")
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if v, err := c.Decode(data); err != nil || v != "data" {
+			t.Errorf("%s: expected data to round-trip, got %v, %v", name, v, err)
+		}
+	}
+
+	c := GobCodec()
+	data, err := c.Encode(invoice{ID: 42, Amount: 9.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := c.Decode(data); err != nil || v != (invoice{ID: 42, Amount: 9.5}) {
+		t.Errorf("Expected gob to restore the stored type, got %#v, %v", v, err)
+	}
+	if _, err := c.Encode(struct{ X int }{1}); err == nil {
+		t.Error("Expected an unregistered type to fail to encode")
+	}
+}
+
+func TestWALCodec(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wheel.wal")
+	w, err := OpenWAL(path, WALCodec(GobCodec()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw := NewTimeWheel(0, 10, nil, WithWAL(w))
+	tw.Set("inv", invoice{ID: 42, Amount: 9.5}, 5*ManualInterval)
+	w.file.Close()
+
+	w, err = OpenWAL(path, WALCodec(GobCodec()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	tw = NewTimeWheel(0, 10, nil, WithWAL(w))
+	defer tw.Stop()
+	var got any
+	tw.Range(func(key string, value any, expireAt time.Time) bool {
+		got = value
+		return true
+	})
+	if got != (invoice{ID: 42, Amount: 9.5}) {
+		t.Errorf("Expected the value to replay as an invoice, got %#v", got)
+	}
+
+	// Reopened without the codec, encoded records are dropped
+	w.Close()
+	w, err = OpenWAL(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	tw = NewTimeWheel(0, 10, nil, WithWAL(w))
+	defer tw.Stop()
+	if n := tw.Stats().Pending; n != 0 {
+		t.Error("Expected an encoded record to need its codec")
+	}
+}
@@ -0,0 +1,136 @@
+package timewheel
+
+import "time"
+
+// Config is the effective configuration of a wheel, as resolved from the
+// constructor arguments and options.
+type Config struct {
+	BaseInterval      time.Duration
+	RequestedInterval time.Duration
+	SlotsPerLayer     int
+	// LayerIntervals holds the tick interval of every current layer, lowest first.
+	LayerIntervals    []time.Duration
+	MaxLayers         int
+	Manual            bool
+	TimerResolution   time.Duration
+	ResolutionAdjust  bool
+	DriftCompensation bool
+	CatchUpThreshold  time.Duration
+	ZeroTTL           ZeroTTLPolicy
+	Duplicate         DuplicatePolicy
+	ImmediateDispatch bool
+	HybridTimers      bool
+	SyncCallbacks     bool
+	SyncTimeout       time.Duration
+	// ExpiredBuffer is the Expired channel's capacity, or -1 without one.
+	ExpiredBuffer  int
+	OverflowPolicy OverflowPolicy
+	Realtime       bool
+	Nice           int
+	Tracing        bool
+	PanicHandler   bool
+	BatchCallback  bool
+	MaxBatch       int
+	// Capacity is the pending task limit, or 0 for none.
+	Capacity int
+	Eviction EvictionPolicy
+	// RetryInitial, RetryMax and RetryAttempts are the WithRetryBackoff
+	// settings, zero without it.
+	RetryInitial  time.Duration
+	RetryMax      time.Duration
+	RetryAttempts int
+	// HealthThreshold is the lag Healthy tolerates, defaulted if not set.
+	HealthThreshold time.Duration
+}
+
+// Options returns the effective configuration of the wheel.
+func (tw *TimeWheel) Options() Config {
+	tw.mu.RLock()
+	intervals := make([]time.Duration, len(tw.layers))
+	for i, l := range tw.layers {
+		intervals[i] = l.interval
+	}
+	tw.mu.RUnlock()
+
+	c := Config{
+		BaseInterval:      tw.baseInterval,
+		RequestedInterval: tw.requestedInterval,
+		SlotsPerLayer:     tw.slotsPerLayer,
+		LayerIntervals:    intervals,
+		MaxLayers:         tw.maxLayers,
+		Manual:            tw.manual,
+		TimerResolution:   tw.timerResolution(),
+		ResolutionAdjust:  tw.adjustResolution,
+		DriftCompensation: tw.driftCompensation,
+		CatchUpThreshold:  tw.catchUpThreshold,
+		ZeroTTL:           tw.zeroTTL,
+		Duplicate:         tw.duplicate,
+		ImmediateDispatch: tw.immediate,
+		HybridTimers:      tw.hybrid,
+		SyncCallbacks:     tw.syncMode,
+		SyncTimeout:       tw.syncTimeout,
+		ExpiredBuffer:     -1,
+		Realtime:          tw.realtime,
+		Nice:              tw.nice,
+		Tracing:           tw.tracer != nil,
+		PanicHandler:      tw.panicHandler != nil,
+		BatchCallback:     tw.batchCallback != nil,
+		MaxBatch:          tw.maxBatch,
+		Capacity:          tw.capacity,
+		Eviction:          tw.eviction,
+		HealthThreshold:   tw.healthLimit(),
+	}
+	if b := tw.backoff; b != nil {
+		c.RetryInitial, c.RetryMax, c.RetryAttempts = b.initial, b.max, b.maxAttempts
+	}
+	if tw.expired != nil {
+		c.ExpiredBuffer = cap(tw.expired.ch)
+		c.OverflowPolicy = tw.expired.policy
+	}
+	return c
+}
+
+func (p ZeroTTLPolicy) String() string {
+	switch p {
+	case FireAsync:
+		return "FireAsync"
+	case FireSync:
+		return "FireSync"
+	case Reject:
+		return "Reject"
+	case Ignore:
+		return "Ignore"
+	default:
+		return "ZeroTTLPolicy(unknown)"
+	}
+}
+
+func (p DuplicatePolicy) String() string {
+	switch p {
+	case DuplicateOverwrite:
+		return "DuplicateOverwrite"
+	case DuplicateReject:
+		return "DuplicateReject"
+	case DuplicateKeepEarliest:
+		return "DuplicateKeepEarliest"
+	case DuplicateKeepLatest:
+		return "DuplicateKeepLatest"
+	default:
+		return "DuplicatePolicy(unknown)"
+	}
+}
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowBlock:
+		return "OverflowBlock"
+	case OverflowDropNewest:
+		return "OverflowDropNewest"
+	case OverflowDropOldest:
+		return "OverflowDropOldest"
+	case OverflowReject:
+		return "OverflowReject"
+	default:
+		return "OverflowPolicy(unknown)"
+	}
+}
@@ -0,0 +1,29 @@
+package timewheel
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOptions(t *testing.T) {
+	tw := NewTimeWheel(100*time.Millisecond, 10, nil,
+		WithManualMode(),
+		WithZeroTTLPolicy(Reject),
+		WithExpiredChannel(16, OverflowDropOldest),
+		WithMaxLayers(4),
+	)
+	defer tw.Stop()
+
+	c := tw.Options()
+	expected := []time.Duration{100 * time.Millisecond, time.Second, 10 * time.Second}
+	if !reflect.DeepEqual(c.LayerIntervals, expected) {
+		t.Errorf("Expected layers %v, got %v", expected, c.LayerIntervals)
+	}
+	if !c.Manual || c.ZeroTTL != Reject || c.ExpiredBuffer != 16 || c.OverflowPolicy != OverflowDropOldest || c.MaxLayers != 4 {
+		t.Errorf("Unexpected config %+v", c)
+	}
+	if c.ZeroTTL.String() != "Reject" {
+		t.Errorf("Expected Reject, got %s", c.ZeroTTL)
+	}
+}
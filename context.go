@@ -0,0 +1,90 @@
+package timewheel
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Deadlines hands out contexts cancelled by a wheel rather than a runtime
+// timer each, for services tracking very many request deadlines. Like any
+// wheel task, a deadline fires up to one base interval late.
+type Deadlines struct {
+	tw *TimeWheel
+}
+
+// NewDeadlines builds a Deadlines on a wheel of its own, configured by opts.
+func NewDeadlines(baseInterval time.Duration, slotsPerLayer int, opts ...Option) *Deadlines {
+	opts = append(opts[:len(opts):len(opts)], WithSyncCallbacks(0))
+	return &Deadlines{
+		tw: NewTimeWheel(baseInterval, slotsPerLayer, func(_ string, value any) {
+			value.(context.CancelCauseFunc)(context.DeadlineExceeded)
+		}, opts...),
+	}
+}
+
+// Context returns a copy of parent that is done after timeout, like
+// context.WithTimeout. Call cancel once the work is done to release the
+// deadline's wheel task early.
+func (dl *Deadlines) Context(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	inner, cancelCause := context.WithCancelCause(parent)
+	ctx := &wheelContext{Context: inner, deadline: dl.tw.clock.Now().Add(timeout)}
+	if timeout <= 0 {
+		cancelCause(context.DeadlineExceeded)
+		return ctx, func() {}
+	}
+
+	key := dl.tw.Schedule(cancelCause, timeout)
+	stop := context.AfterFunc(parent, func() { dl.tw.Delete(key) })
+	return ctx, func() {
+		stop()
+		dl.tw.Delete(key)
+		cancelCause(context.Canceled)
+	}
+}
+
+// Wheel returns the wheel behind the deadlines, for its stats.
+func (dl *Deadlines) Wheel() *TimeWheel {
+	return dl.tw
+}
+
+// Stop stops the wheel; pending deadlines no longer fire.
+func (dl *Deadlines) Stop() {
+	dl.tw.Stop()
+}
+
+var (
+	defaultDeadlinesOnce sync.Once
+	defaultDeadlines     *Deadlines
+)
+
+// Context is Deadlines.Context on a process-wide wheel with a 10ms base
+// interval, started on first use.
+func Context(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	defaultDeadlinesOnce.Do(func() {
+		defaultDeadlines = NewDeadlines(10*time.Millisecond, 100)
+	})
+	return defaultDeadlines.Context(parent, timeout)
+}
+
+// wheelContext reports its deadline and, once it passes, DeadlineExceeded,
+// like a context made by context.WithDeadline.
+type wheelContext struct {
+	context.Context
+	deadline time.Time
+}
+
+func (c *wheelContext) Deadline() (time.Time, bool) {
+	if parent, ok := c.Context.Deadline(); ok && parent.Before(c.deadline) {
+		return parent, true
+	}
+	return c.deadline, true
+}
+
+func (c *wheelContext) Err() error {
+	err := c.Context.Err()
+	if err != nil && context.Cause(c.Context) == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+	return err
+}
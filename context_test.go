@@ -0,0 +1,60 @@
+package timewheel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeadlines(t *testing.T) {
+	dl := NewDeadlines(0, 10)
+	defer dl.Stop()
+
+	ctx, cancel := dl.Context(context.Background(), 2*ManualInterval)
+	defer cancel()
+	if _, ok := ctx.Deadline(); !ok {
+		t.Error("Expected the context to report a deadline")
+	}
+	dl.Wheel().Advance(ManualInterval)
+	if ctx.Err() != nil {
+		t.Fatalf("Expected the context to be live before its deadline, got %v", ctx.Err())
+	}
+	dl.Wheel().Advance(ManualInterval)
+	<-ctx.Done()
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", ctx.Err())
+	}
+
+	ctx, cancel = dl.Context(context.Background(), time.Hour)
+	cancel()
+	if ctx.Err() != context.Canceled {
+		t.Errorf("Expected Canceled after cancel, got %v", ctx.Err())
+	}
+	if n := dl.Wheel().Stats().Pending; n != 0 {
+		t.Errorf("Expected cancel to release the wheel task, %d pending", n)
+	}
+
+	parent, cancelParent := context.WithCancel(context.Background())
+	ctx, cancel = dl.Context(parent, time.Hour)
+	defer cancel()
+	cancelParent()
+	<-ctx.Done()
+	if ctx.Err() != context.Canceled {
+		t.Errorf("Expected the parent's cancellation, got %v", ctx.Err())
+	}
+}
+
+func TestContext(t *testing.T) {
+	ctx, cancel := Context(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected the default wheel to cancel the context")
+	}
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", ctx.Err())
+	}
+}
@@ -0,0 +1,196 @@
+package timewheel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SetCron schedules a recurring task from a standard five-field cron spec
+// (minute hour day-of-month month day-of-week, evaluated in the local time
+// zone) or one of @yearly, @monthly, @weekly, @daily and @hourly. After each
+// firing the task is moved to its next occurrence; Delete or Set on the key
+// ends the series.
+func (tw *TimeWheel) SetCron(key string, value any, spec string, opts ...SetOption) error {
+	sched, err := parseCron(spec)
+	if err != nil {
+		return err
+	}
+	if tw.stopped() {
+		return ErrStopped
+	}
+	so := tw.newSetOptions(opts)
+	so.cron = sched
+
+	done := tw.lockFor(&tw.latency.set)
+	now := tw.now()
+	_, err = tw.set(key, value, sched.next(now).Sub(now), so)
+	done()
+	tw.unlock()
+	return err
+}
+
+// recur schedules the occurrence after a fired cron entry, unless the key
+// was set anew meanwhile. Missed occurrences, e.g. after a stall, are skipped.
+func (tw *TimeWheel) recur(entry *taskEntry) {
+	tw.mu.Lock()
+	defer tw.unlock()
+
+	if _, exists := tw.keyMap[entry.key]; exists || tw.stopped() {
+		return
+	}
+	now := tw.now()
+	from := entry.expiration
+	if now.After(from) {
+		from = now
+	}
+
+	// The fired entry may still be in use by its callback
+	next := *entry
+	next.scheduledAt = now
+	next.seq = tw.nextSeq()
+	tw.track(&next)
+	tw.reschedule(&next, addJitter(entry.cron.next(from).Sub(now), tw.jitterFor(entry.jitter)))
+}
+
+type cronSchedule struct {
+	spec                          string
+	minute, hour, dom, month, dow uint64
+	// A restricted day-of-month and day-of-week match either, as in cron(8)
+	domStar, dowStar bool
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	monthNames = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	dowNames   = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+func parseCron(spec string) (*cronSchedule, error) {
+	expr := strings.TrimSpace(spec)
+	if d, ok := cronDescriptors[strings.ToLower(expr)]; ok {
+		expr = d
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w %q: expected 5 fields, got %d", ErrInvalidCron, spec, len(fields))
+	}
+
+	s := &cronSchedule{spec: spec, domStar: fields[2] == "*", dowStar: fields[4] == "*"}
+	var err error
+	parse := func(field string, min, max int, names []string, bits *uint64) {
+		if err == nil {
+			*bits, err = parseCronField(field, min, max, names)
+			if err != nil {
+				err = fmt.Errorf("%w %q: %v", ErrInvalidCron, spec, err)
+			}
+		}
+	}
+	parse(fields[0], 0, 59, nil, &s.minute)
+	parse(fields[1], 0, 23, nil, &s.hour)
+	parse(fields[2], 1, 31, nil, &s.dom)
+	parse(fields[3], 1, 12, monthNames, &s.month)
+	parse(fields[4], 0, 7, dowNames, &s.dow)
+	if err != nil {
+		return nil, err
+	}
+	// 7 is an alias for Sunday
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+func parseCronField(field string, min, max int, names []string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("bad step in %q", part)
+			}
+			rng, step = part[:i], n
+		}
+
+		lo, hi := min, max
+		if rng != "*" {
+			bounds := strings.SplitN(rng, "-", 2)
+			var err error
+			if lo, err = cronValue(bounds[0], min, names); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = cronValue(bounds[1], min, names); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				// "a/n" runs from a to the end of the range
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func cronValue(s string, min int, names []string) (int, error) {
+	for i, name := range names {
+		if strings.EqualFold(s, name) {
+			return i + min, nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("bad value %q", s)
+	}
+	return n, nil
+}
+
+// next returns the first matching minute strictly after t.
+func (s *cronSchedule) next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	// Any valid spec matches within four years (Feb 29)
+	for limit := t.AddDate(5, 0, 0); t.Before(limit); {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return t
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
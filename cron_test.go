@@ -0,0 +1,73 @@
+package timewheel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	from := time.Date(2024, 2, 28, 12, 30, 15, 0, time.UTC) // a Wednesday
+	cases := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, 2, 28, 12, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 2, 28, 12, 45, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2024, 2, 29, 2, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * mon-fri", time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Restricted day-of-month and day-of-week match either
+		{"0 0 15 * fri", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		s, err := parseCron(c.spec)
+		if err != nil {
+			t.Errorf("%s: %v", c.spec, err)
+			continue
+		}
+		if got := s.next(from); !got.Equal(c.want) {
+			t.Errorf("%s: expected %s, got %s", c.spec, c.want, got)
+		}
+	}
+
+	for _, spec := range []string{"* * * *", "60 * * * *", "* * * * 8", "*/0 * * * *", "x * * * *"} {
+		if _, err := parseCron(spec); !errors.Is(err, ErrInvalidCron) {
+			t.Errorf("%s: expected ErrInvalidCron, got %v", spec, err)
+		}
+	}
+}
+
+func TestSetCron(t *testing.T) {
+	fired := make(chan string, 4)
+	tw := NewTimeWheel(time.Second, 60, func(k string, v any) {
+		fired <- k
+	}, WithManualMode(), WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	if err := tw.SetCron("job", "data", "* * * * *"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		tw.Advance(time.Minute)
+		select {
+		case <-fired:
+		default:
+			t.Fatalf("Expected run %d to fire", i+1)
+		}
+	}
+	if n := tw.Stats().Pending; n != 1 {
+		t.Errorf("Expected the next occurrence to be pending, got %d", n)
+	}
+
+	tw.Delete("job")
+	tw.Advance(2 * time.Minute)
+	select {
+	case <-fired:
+		t.Error("Expected Delete to end the series")
+	default:
+	}
+}
@@ -0,0 +1,40 @@
+package timewheel
+
+import "fmt"
+
+// WithDeadLetter hands h every task whose delivery failed for good, with the
+// last error: an error callback failed and the task will not be retried, or
+// WithRetryBackoff ran out of attempts. A panicking callback counts as
+// failing with a *PanicError and, under WithRetryBackoff, is retried like an
+// error. h runs on the delivering goroutine, so it should persist or forward
+// the task and return.
+func WithDeadLetter(h func(task TaskInfo, err error)) Option {
+	return func(tw *TimeWheel) {
+		tw.onDeadLetter = h
+	}
+}
+
+// PanicError is the failure of a callback that panicked.
+type PanicError struct {
+	Value any
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("timewheel: callback panicked: %v", e.Value)
+}
+
+// panicked treats a recovered callback panic as a failed delivery when
+// retries or dead letters are configured, and reports whether the task was
+// scheduled again.
+func (tw *TimeWheel) panicked(entry *taskEntry, r any) bool {
+	if tw.backoff == nil && tw.onDeadLetter == nil {
+		return false
+	}
+	return tw.failed(entry, &PanicError{Value: r})
+}
+
+func (tw *TimeWheel) deadLetter(entry *taskEntry, err error) {
+	if tw.onDeadLetter != nil {
+		tw.onDeadLetter(entry.info(), err)
+	}
+}
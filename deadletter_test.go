@@ -0,0 +1,69 @@
+package timewheel
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDeadLetter(t *testing.T) {
+	type letter struct {
+		task TaskInfo
+		err  error
+	}
+	var dead []letter
+	boom := errors.New("boom")
+	tw := NewTimeWheel(0, 10, nil, WithSyncCallbacks(0),
+		WithRetryBackoff(ManualInterval, ManualInterval, 2),
+		WithErrCallback(func(k string, v any) error {
+			if k == "panics" {
+				panic("bad payload")
+			}
+			if k == "fails" {
+				return boom
+			}
+			return nil
+		}),
+		WithDeadLetter(func(task TaskInfo, err error) {
+			dead = append(dead, letter{task, err})
+		}))
+	defer tw.Stop()
+
+	tw.Set("fails", nil, ManualInterval)
+	tw.Set("panics", nil, ManualInterval)
+	tw.Set("works", nil, ManualInterval)
+	tw.Advance(5 * ManualInterval)
+
+	if len(dead) != 2 {
+		t.Fatalf("Expected 2 dead letters, got %v", dead)
+	}
+	for _, d := range dead {
+		if d.task.Attempt != 2 {
+			t.Errorf("Expected %s to be given up on its second attempt, got %d", d.task.Key, d.task.Attempt)
+		}
+		var pe *PanicError
+		switch d.task.Key {
+		case "fails":
+			if !errors.Is(d.err, boom) {
+				t.Errorf("Expected the callback's error, got %v", d.err)
+			}
+		case "panics":
+			if !errors.As(d.err, &pe) || pe.Value != "bad payload" {
+				t.Errorf("Expected a PanicError, got %v", d.err)
+			}
+		}
+	}
+}
+
+func TestDeadLetterWithoutRetries(t *testing.T) {
+	var dead []string
+	tw := NewTimeWheel(0, 10, nil, WithSyncCallbacks(0),
+		WithErrCallback(func(k string, v any) error { return errors.New("boom") }),
+		WithDeadLetter(func(task TaskInfo, err error) { dead = append(dead, task.Key) }))
+	defer tw.Stop()
+
+	tw.Set("once", nil, ManualInterval)
+	tw.Tick()
+	if len(dead) != 1 || dead[0] != "once" {
+		t.Errorf("Expected an unretried failure to be dead-lettered, got %v", dead)
+	}
+}
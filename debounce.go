@@ -0,0 +1,55 @@
+package timewheel
+
+import "time"
+
+// Debounce fires the callback for key once calls for it have stopped for
+// quiet: each call replaces the value and pushes the deadline back, so a
+// burst fires once, with its last value. The wheel's duplicate policy does
+// not apply.
+func (tw *TimeWheel) Debounce(key string, value any, quiet time.Duration) error {
+	return tw.SetWith(key, value, quiet, TaskDuplicate(DuplicateOverwrite))
+}
+
+// Throttle fires the callback for key at most once per window. A call with
+// nothing pending for key schedules it window from now; calls before it
+// fires only replace the value, so it delivers the latest one.
+func (tw *TimeWheel) Throttle(key string, value any, window time.Duration) error {
+	if tw.stopped() {
+		return ErrStopped
+	}
+	so := tw.newSetOptions(nil)
+	window = ttlOf(value, window)
+
+	done := tw.lockFor(&tw.latency.set)
+	if entry, exists := tw.keyMap[key]; exists {
+		entry.value = value
+		tw.journal(hookReschedule, entry)
+		done()
+		tw.unlock()
+		return nil
+	}
+	fireNow, err := tw.set(key, value, window, so)
+	done()
+	tw.unlock()
+
+	if fireNow != nil {
+		tw.fireSync(fireNow)
+	}
+	return err
+}
+
+func (ns *Namespace) Debounce(key string, value any, quiet time.Duration) error {
+	return ns.tw.Debounce(ns.Key(key), value, quiet)
+}
+
+func (ns *Namespace) Throttle(key string, value any, window time.Duration) error {
+	return ns.tw.Throttle(ns.Key(key), value, window)
+}
+
+func (s *ShardedTimeWheel) Debounce(key string, value any, quiet time.Duration) error {
+	return s.Shard(key).Debounce(key, value, quiet)
+}
+
+func (s *ShardedTimeWheel) Throttle(key string, value any, window time.Duration) error {
+	return s.Shard(key).Throttle(key, value, window)
+}
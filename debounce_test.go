@@ -0,0 +1,48 @@
+package timewheel
+
+import "testing"
+
+func TestDebounce(t *testing.T) {
+	var fired []any
+	tw := NewTimeWheel(0, 10, func(k string, v any) {
+		fired = append(fired, v)
+	}, WithSyncCallbacks(0), WithDuplicatePolicy(DuplicateReject))
+	defer tw.Stop()
+
+	for i := 1; i <= 3; i++ {
+		if err := tw.Debounce("save", i, 3*ManualInterval); err != nil {
+			t.Fatalf("Debounce failed: %v", err)
+		}
+		tw.Advance(2 * ManualInterval)
+	}
+	if len(fired) != 0 {
+		t.Fatalf("Expected nothing to fire during the burst, got %v", fired)
+	}
+	tw.Advance(ManualInterval)
+	if len(fired) != 1 || fired[0] != 3 {
+		t.Errorf("Expected the last value once the burst went quiet, got %v", fired)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	var fired []any
+	tw := NewTimeWheel(0, 10, func(k string, v any) {
+		fired = append(fired, v)
+	}, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	// One call per tick for 10 ticks against a 4 tick window
+	for i := 1; i <= 10; i++ {
+		if err := tw.Throttle("progress", i, 4*ManualInterval); err != nil {
+			t.Fatalf("Throttle failed: %v", err)
+		}
+		tw.Advance(ManualInterval)
+	}
+	if len(fired) != 2 || fired[0] != 4 || fired[1] != 8 {
+		t.Errorf("Expected the latest value once per window, got %v", fired)
+	}
+	tw.Advance(4 * ManualInterval)
+	if len(fired) != 3 || fired[2] != 10 {
+		t.Errorf("Expected the trailing call to fire, got %v", fired)
+	}
+}
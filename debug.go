@@ -0,0 +1,115 @@
+package timewheel
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// debugTaskLimit is how many pending tasks DebugHandler lists by default.
+const debugTaskLimit = 100
+
+// DebugHandler serves a live view of the wheel for debugging, in the manner
+// of net/http/pprof: GET returns JSON with the stats, the layer layout and
+// the soonest pending tasks (?limit=n, default 100), and DELETE ?key=k
+// cancels a task. Values are rendered with fmt, so any type can be shown.
+// It exposes and mutates the wheel, so mount it only on an internal listener.
+func DebugHandler(tw *TimeWheel) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			tw.serveDebug(w, r)
+		case http.MethodDelete:
+			key := r.URL.Query().Get("key")
+			if key == "" {
+				http.Error(w, "missing key", http.StatusBadRequest)
+				return
+			}
+			if _, existed := tw.Delete(key); !existed {
+				http.Error(w, ErrNotFound.Error(), http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Allow", "GET, DELETE")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+type debugTask struct {
+	Key         string            `json:"key"`
+	Value       string            `json:"value"`
+	Expiration  time.Time         `json:"expiration"`
+	Remaining   string            `json:"remaining"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+}
+
+type debugLayer struct {
+	Interval string `json:"interval"`
+	Position int    `json:"position"`
+	Entries  int    `json:"entries"`
+	Busiest  int    `json:"busiest"`
+	Slots    []int  `json:"slots"`
+}
+
+type debugView struct {
+	Stats  Stats        `json:"stats"`
+	Cursor uint64       `json:"cursor"`
+	Layers []debugLayer `json:"layers"`
+	Parked int          `json:"parked"`
+	Timed  int          `json:"timed"`
+	Tasks  []debugTask  `json:"tasks"`
+}
+
+func (tw *TimeWheel) serveDebug(w http.ResponseWriter, r *http.Request) {
+	limit := debugTaskLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	in := tw.Inspect(limit)
+	tw.mu.RLock()
+	now := tw.now()
+	tw.mu.RUnlock()
+	view := debugView{
+		Stats:  tw.Stats(),
+		Cursor: in.Cursor,
+		Parked: in.Parked,
+		Timed:  in.Timed,
+		Layers: make([]debugLayer, len(in.Layers)),
+		Tasks:  make([]debugTask, len(in.Upcoming)),
+	}
+	for i, l := range in.Layers {
+		view.Layers[i] = debugLayer{
+			Interval: l.Interval.String(),
+			Position: l.Position,
+			Entries:  l.Entries,
+			Busiest:  l.Busiest,
+			Slots:    l.Slots,
+		}
+	}
+	for i, task := range in.Upcoming {
+		view.Tasks[i] = debugTask{
+			Key:         task.Key,
+			Value:       fmt.Sprint(task.Value),
+			Expiration:  task.Expiration,
+			Remaining:   clampDuration(task.Expiration.Sub(now)).String(),
+			Annotations: task.Annotations,
+			Tags:        task.Tags,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(view)
+}
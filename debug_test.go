@@ -0,0 +1,51 @@
+package timewheel
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDebugHandler(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil)
+	defer tw.Stop()
+	tw.SetWith("key", 42, time.Second, TaskAnnotations(map[string]string{"owner": "billing"}))
+	tw.Set("other", "data", time.Minute)
+
+	srv := httptest.NewServer(DebugHandler(tw))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "?limit=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var view debugView
+	err = json.NewDecoder(resp.Body).Decode(&view)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatalf("Expected JSON, got %v", err)
+	}
+	if view.Stats.Pending != 2 || len(view.Layers) != 3 {
+		t.Errorf("Expected 2 pending tasks over 3 layers, got %d and %d", view.Stats.Pending, len(view.Layers))
+	}
+	if len(view.Tasks) != 1 || view.Tasks[0].Key != "key" || view.Tasks[0].Value != "42" || view.Tasks[0].Annotations["owner"] != "billing" {
+		t.Errorf("Expected the soonest task with its annotations, got %+v", view.Tasks)
+	}
+
+	for _, want := range []int{http.StatusNoContent, http.StatusNotFound} {
+		req, _ := http.NewRequest(http.MethodDelete, srv.URL+"?key=key", nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("Expected DELETE to answer %d, got %d", want, resp.StatusCode)
+		}
+	}
+	if _, _, ok := tw.Remaining("key"); ok {
+		t.Error("Expected DELETE to cancel the task")
+	}
+}
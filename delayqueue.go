@@ -0,0 +1,108 @@
+package timewheel
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DelayQueue is a pull-based front end to a wheel: values pushed with a
+// delay become ready when it elapses and are taken, in the order they came
+// due, by Pop.
+type DelayQueue struct {
+	tw     *TimeWheel
+	mu     sync.Mutex
+	ready  []any
+	signal chan struct{}
+}
+
+// NewDelayQueue builds a queue on a wheel of its own, configured by opts.
+// The wheel runs callbacks synchronously so ready values keep their order.
+func NewDelayQueue(baseInterval time.Duration, slotsPerLayer int, opts ...Option) *DelayQueue {
+	q := &DelayQueue{signal: make(chan struct{}, 1)}
+	opts = append(opts[:len(opts):len(opts)], WithSyncCallbacks(0))
+	q.tw = NewTimeWheel(baseInterval, slotsPerLayer, q.expire, opts...)
+	return q
+}
+
+// Push schedules value to become ready after delay and returns the key it
+// waits under, which Remove accepts.
+func (q *DelayQueue) Push(value any, delay time.Duration) string {
+	return q.tw.Schedule(value, delay)
+}
+
+// Remove withdraws a value that is not ready yet.
+func (q *DelayQueue) Remove(key string) bool {
+	_, ok := q.tw.Take(key)
+	return ok
+}
+
+// Pop blocks until a value is ready and returns it. It returns ctx's error
+// if ctx is done first, and ErrStopped once the queue is stopped and no
+// ready value is left.
+func (q *DelayQueue) Pop(ctx context.Context) (any, error) {
+	for {
+		if value, ok := q.TryPop(); ok {
+			return value, nil
+		}
+		if q.tw.stopped() {
+			return nil, ErrStopped
+		}
+		select {
+		case <-q.signal:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-q.tw.quit():
+		}
+	}
+}
+
+// TryPop returns a ready value without blocking.
+func (q *DelayQueue) TryPop() (any, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.ready) == 0 {
+		return nil, false
+	}
+	value := q.ready[0]
+	q.ready[0] = nil
+	q.ready = q.ready[1:]
+	if len(q.ready) > 0 {
+		q.wake()
+	}
+	return value, true
+}
+
+// Len returns how many values are ready to be popped.
+func (q *DelayQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.ready)
+}
+
+// Wheel returns the wheel behind the queue, for its stats and options.
+func (q *DelayQueue) Wheel() *TimeWheel {
+	return q.tw
+}
+
+// Stop stops the wheel. Values already ready can still be popped.
+func (q *DelayQueue) Stop() {
+	q.tw.Stop()
+}
+
+func (q *DelayQueue) expire(_ string, value any) {
+	q.mu.Lock()
+	q.ready = append(q.ready, value)
+	q.wake()
+	q.mu.Unlock()
+}
+
+// wake lets one waiting Pop through; it passes the signal on if more values
+// remain.
+func (q *DelayQueue) wake() {
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,57 @@
+package timewheel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDelayQueue(t *testing.T) {
+	q := NewDelayQueue(0, 10)
+	defer q.Stop()
+
+	q.Push("second", 2*ManualInterval)
+	q.Push("first", ManualInterval)
+	gone := q.Push("withdrawn", ManualInterval)
+	if !q.Remove(gone) {
+		t.Error("Expected Remove to withdraw a pending value")
+	}
+	if _, ok := q.TryPop(); ok {
+		t.Fatal("Expected nothing ready before the delay")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.Pop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected Pop to give up with the context, got %v", err)
+	}
+
+	q.Wheel().Advance(2 * ManualInterval)
+	for _, want := range []string{"first", "second"} {
+		got, err := q.Pop(context.Background())
+		if err != nil || got != want {
+			t.Errorf("Expected %s, got %v %v", want, got, err)
+		}
+	}
+
+	q.Stop()
+	if _, err := q.Pop(context.Background()); !errors.Is(err, ErrStopped) {
+		t.Errorf("Expected ErrStopped once stopped and empty, got %v", err)
+	}
+}
+
+func TestDelayQueueBlockingPop(t *testing.T) {
+	q := NewDelayQueue(10*time.Millisecond, 10)
+	defer q.Stop()
+
+	start := time.Now()
+	q.Push("job", 30*time.Millisecond)
+	got, err := q.Pop(context.Background())
+	if err != nil || got != "job" {
+		t.Fatalf("Expected job, got %v %v", got, err)
+	}
+	if waited := time.Since(start); waited < 20*time.Millisecond {
+		t.Errorf("Expected Pop to block until the delay, returned after %v", waited)
+	}
+}
@@ -0,0 +1,230 @@
+package timewheel
+
+import (
+	"context"
+	"log/slog"
+	"runtime/pprof"
+	"time"
+)
+
+// WithPanicHandler routes panics raised by the callback to h instead of
+// letting them crash the process. Panics are recovered even without a handler.
+func WithPanicHandler(h func(key string, value any, recovered any)) Option {
+	return func(tw *TimeWheel) {
+		tw.panicHandler = h
+	}
+}
+
+// fireAsync delivers an entry from under the wheel lock, so nothing it does
+// may block: channel delivery and the callback both run on a new goroutine.
+func (tw *TimeWheel) fireAsync(entry *taskEntry) {
+	if entry.cron != nil {
+		tw.runtime.Go(func() { tw.recur(entry) })
+	}
+	if tw.follow(entry) {
+		return
+	}
+	if !tw.hasCallback() && tw.expired == nil && tw.hooks.onFire == nil && tw.hooks.onRemove == nil && !tw.listening() {
+		tw.countFired(entry)
+		entry.markFired()
+		tw.journal(hookFire, entry)
+		return
+	}
+	tw.board(entry)
+	tw.runtime.Go(func() {
+		if !tw.claim(entry) {
+			tw.superseded(entry, false)
+			return
+		}
+		tw.countFired(entry)
+		entry.markFired()
+		tw.load(entry)
+		tw.awaitAck(entry)
+		tw.fireHook(entry)
+		tw.emit(entry)
+		tw.notify(entry)
+		tw.invoke(entry)
+	})
+}
+
+// fireSync delivers an entry on the caller's goroutine.
+func (tw *TimeWheel) fireSync(entry *taskEntry) {
+	if entry.cron != nil {
+		tw.recur(entry)
+	}
+	if tw.follow(entry) {
+		return
+	}
+	tw.countFired(entry)
+	entry.markFired()
+	tw.load(entry)
+	tw.awaitAck(entry)
+	tw.fireHook(entry)
+	tw.emit(entry)
+	tw.notify(entry)
+	tw.invoke(entry)
+}
+
+func (tw *TimeWheel) invoke(entry *taskEntry) {
+	if tw.batchCallback != nil {
+		tw.invokeBatch([]*taskEntry{entry})
+		return
+	}
+	tw.board(entry)
+	if !tw.claim(entry) {
+		tw.superseded(entry, true)
+		return
+	}
+	retried := false
+	defer func() { tw.finish(entry, retried) }()
+	if !tw.hasCallback() {
+		return
+	}
+
+	ctx := entry.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = withTags(ctx, entry.tags)
+	ctx = withAttempt(ctx, entry.retries)
+	ctx = withFireTimes(ctx, entry)
+	if entry.execTimeout > 0 || tw.cancelOnDelete {
+		var stop func()
+		ctx, stop = tw.takeOff(ctx, entry)
+		defer stop()
+	}
+	if tw.tracer != nil {
+		var end func()
+		ctx, end = tw.tracer.Start(ctx, entry.info(), entry.scheduledAt, tw.now())
+		defer end()
+	}
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		tw.counters.panics.Add(1)
+		tw.log(slog.LevelError, "timewheel: callback panicked", "key", entry.key, "panic", r)
+		if tw.panicHandler != nil {
+			tw.panicHandler(entry.key, entry.value, r)
+		}
+		retried = tw.panicked(entry, r)
+	}()
+	if tw.labels != nil {
+		pprof.Do(ctx, tw.labels(entry.info()), func(ctx context.Context) {
+			retried = tw.callbacks(ctx, entry)
+		})
+		return
+	}
+	retried = tw.callbacks(ctx, entry)
+}
+
+// callbacks runs the configured callbacks for one entry and reports whether
+// it was scheduled again.
+func (tw *TimeWheel) callbacks(ctx context.Context, entry *taskEntry) (retried bool) {
+	if tw.ctxCallback != nil {
+		tw.ctxCallback(ctx, entry.key, entry.value)
+	}
+	if tw.callback != nil {
+		tw.callback(entry.key, entry.value)
+	}
+	if tw.errCallback != nil {
+		if err := tw.errCallback(entry.key, entry.value); err != nil {
+			retried = tw.handleCallbackError(entry, err)
+		}
+	}
+	if tw.ctxErrCallback != nil {
+		if err := tw.ctxErrCallback(ctx, entry.key, entry.value); err != nil {
+			retried = tw.handleCallbackError(entry, err) || retried
+		}
+	}
+	return retried
+}
+
+func (tw *TimeWheel) hasCallback() bool {
+	return tw.callback != nil || tw.ctxCallback != nil || tw.errCallback != nil || tw.ctxErrCallback != nil || tw.tracer != nil || tw.batchCallback != nil
+}
+
+// WithSyncCallbacks runs expiration callbacks one after another on the tick
+// goroutine. A callback still running after timeout is reported to the
+// timeout handler and left to finish in the background while the wheel moves
+// on; a zero timeout waits indefinitely.
+func WithSyncCallbacks(timeout time.Duration) Option {
+	return func(tw *TimeWheel) {
+		tw.syncMode = true
+		tw.syncTimeout = timeout
+	}
+}
+
+func WithTimeoutHandler(h func(key string, value any)) Option {
+	return func(tw *TimeWheel) {
+		tw.onTimeout = h
+	}
+}
+
+// dispatch delivers the entries expired by a tick, outside the wheel lock.
+// Channel delivery happens inline so a blocking overflow policy pushes back
+// on the tick.
+func (tw *TimeWheel) dispatch(expired []*taskEntry) {
+	var batch []*taskEntry
+	for _, entry := range expired {
+		if entry.maint != nil {
+			tw.runMaintenance(entry)
+			continue
+		}
+		if entry.cron != nil {
+			tw.recur(entry)
+		}
+		if tw.follow(entry) {
+			tw.land(entry)
+			continue
+		}
+		if !tw.claim(entry) {
+			tw.superseded(entry, false)
+			continue
+		}
+		tw.countFired(entry)
+		entry.markFired()
+		tw.load(entry)
+		tw.awaitAck(entry)
+		tw.fireHook(entry)
+		tw.emit(entry)
+		tw.notify(entry)
+		if tw.batchCallback != nil {
+			batch = append(batch, entry)
+		} else if tw.syncMode {
+			tw.invokeTimeout(entry)
+		} else if tw.hasCallback() {
+			tw.runtime.Go(func() { tw.invoke(entry) })
+		} else {
+			tw.finish(entry, false)
+		}
+	}
+	tw.dispatchBatches(batch)
+}
+
+func (tw *TimeWheel) invokeTimeout(entry *taskEntry) {
+	if tw.syncTimeout <= 0 {
+		tw.invoke(entry)
+		return
+	}
+
+	done := make(chan struct{})
+	tw.runtime.Go(func() {
+		defer close(done)
+		tw.invoke(entry)
+	})
+
+	timer := tw.clock.NewTicker(tw.syncTimeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C():
+		tw.counters.timeouts.Add(1)
+		tw.log(slog.LevelWarn, "timewheel: callback timed out", "key", entry.key, "timeout", tw.syncTimeout)
+		if tw.onTimeout != nil {
+			tw.onTimeout(entry.key, entry.value)
+		}
+	}
+}
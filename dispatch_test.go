@@ -0,0 +1,74 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPanicHandler(t *testing.T) {
+	recovered := make(chan any, 1)
+	tw := NewTimeWheel(0, 10, func(k string, v any) {
+		panic("boom")
+	}, WithPanicHandler(func(k string, v any, r any) {
+		recovered <- r
+	}))
+	defer tw.Stop()
+
+	tw.Set("test", "data", ManualInterval)
+	tw.Tick()
+
+	select {
+	case r := <-recovered:
+		if r != "boom" {
+			t.Errorf("Expected recovered value boom, got %v", r)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Panic handler was not called")
+	}
+
+	// FireSync runs on the caller's goroutine and must not propagate the panic either
+	tw.SetWith("sync", "data", 0, TaskZeroTTL(FireSync))
+	if r := <-recovered; r != "boom" {
+		t.Errorf("Expected recovered value boom, got %v", r)
+	}
+}
+
+func TestSyncCallbacks(t *testing.T) {
+	var fired []string
+	tw := NewTimeWheel(0, 10, func(k string, v any) {
+		fired = append(fired, k)
+	}, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	tw.Set("a", "data", ManualInterval)
+	tw.Set("b", "data", ManualInterval)
+	tw.Tick()
+
+	if len(fired) != 2 {
+		t.Errorf("Expected both callbacks to finish before Tick returns, got %v", fired)
+	}
+}
+
+func TestSyncCallbackTimeout(t *testing.T) {
+	release := make(chan struct{})
+	timedOut := make(chan string, 1)
+	tw := NewTimeWheel(0, 10, func(k string, v any) {
+		<-release
+	}, WithSyncCallbacks(20*time.Millisecond), WithTimeoutHandler(func(k string, v any) {
+		timedOut <- k
+	}))
+	defer tw.Stop()
+	defer close(release)
+
+	tw.Set("slow", "data", ManualInterval)
+	tw.Tick()
+
+	select {
+	case k := <-timedOut:
+		if k != "slow" {
+			t.Errorf("Expected key slow, got %s", k)
+		}
+	default:
+		t.Fatal("Expected timeout handler to run before Tick returns")
+	}
+}
@@ -0,0 +1,35 @@
+package timewheel
+
+import "time"
+
+// WithDriftCompensation makes each tick measure how far the wall clock has
+// moved since the wheel started and advance as many slots as are due, so a
+// stalled process (GC pause, laptop sleep) catches up instead of firing every
+// remaining task late by the length of the stall.
+func WithDriftCompensation() Option {
+	return func(tw *TimeWheel) {
+		tw.driftCompensation = true
+	}
+}
+
+// dueSteps returns how many slots the tick at now should advance and records
+// the observed lag behind the ideal tick schedule.
+func (tw *TimeWheel) dueSteps(now time.Time) int {
+	stride := int64(tw.stride)
+	due := int64(now.Sub(tw.startedAt) / tw.baseInterval)
+	steps := due - tw.ticksDone
+	lag := now.Sub(tw.startedAt.Add(time.Duration(tw.ticksDone+stride) * tw.baseInterval))
+	tw.tickLag.Store(int64(lag))
+	tw.logLag(int64(lag))
+
+	if !tw.driftCompensation {
+		steps = stride
+	} else if steps > stride {
+		tw.counters.compensated.Add(uint64(steps - stride))
+	}
+	if steps < 0 {
+		steps = 0
+	}
+	tw.ticksDone += steps
+	return int(steps)
+}
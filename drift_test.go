@@ -0,0 +1,46 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDriftCompensation(t *testing.T) {
+	fired := make(chan string, 1)
+	clock := newFakeClock()
+	tw := NewTimeWheel(time.Second, 10, func(k string, v any) {
+		fired <- k
+	}, WithClock(clock), WithDriftCompensation())
+	defer tw.Stop()
+
+	tw.Set("test", "data", 3*time.Second)
+
+	// A five second stall delivers a single tick
+	clock.tick(5 * time.Second)
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the stalled wheel to catch up and fire")
+	}
+
+	if s := tw.Stats(); s.CompensatedTicks != 4 || s.TickLag != 4*time.Second {
+		t.Errorf("Expected 4 compensated ticks and 4s lag, got %d and %s", s.CompensatedTicks, s.TickLag)
+	}
+}
+
+func TestWithoutDriftCompensation(t *testing.T) {
+	fired := make(chan string, 1)
+	clock := newFakeClock()
+	tw := NewTimeWheel(time.Second, 10, func(k string, v any) {
+		fired <- k
+	}, WithClock(clock))
+	defer tw.Stop()
+
+	tw.Set("test", "data", 3*time.Second)
+	clock.tick(5 * time.Second)
+	select {
+	case <-fired:
+		t.Fatal("Expected a single slot per tick without compensation")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
@@ -0,0 +1,28 @@
+package timewheel
+
+// WithOnEmpty calls h whenever the wheel goes from having pending tasks to
+// having none, so an idle wheel can be stopped or decommissioned. A task
+// counts as pending until it fires, so h may run while the callback of the
+// last task is still going, and again if that callback schedules more work.
+// Cron series and tasks awaiting an ack keep the wheel from going empty.
+func WithOnEmpty(h func()) Option {
+	return func(tw *TimeWheel) {
+		tw.hooks.onEmpty = h
+	}
+}
+
+// noteEmpty flags, under the lock, that the wheel may have gone empty; unlock
+// checks again, since a removal is often followed by a new task.
+func (tw *TimeWheel) noteEmpty() {
+	if tw.hooks.onEmpty != nil && len(tw.keyMap) == 0 {
+		tw.emptied = true
+	}
+}
+
+// takeEmpty reports, under the lock, whether the wheel went empty since the
+// last unlock.
+func (tw *TimeWheel) takeEmpty() bool {
+	empty := tw.emptied && len(tw.keyMap) == 0
+	tw.emptied = false
+	return empty
+}
@@ -0,0 +1,47 @@
+package timewheel
+
+import "testing"
+
+func TestOnEmpty(t *testing.T) {
+	empties := 0
+	tw := NewTimeWheel(0, 10, func(k string, v any) {}, WithSyncCallbacks(0), WithOnEmpty(func() {
+		empties++
+	}))
+	defer tw.Stop()
+
+	tw.Set("a", nil, ManualInterval)
+	tw.Set("b", nil, 2*ManualInterval)
+	tw.Set("a", nil, 3*ManualInterval)
+	if empties != 0 {
+		t.Fatalf("Expected no empty while tasks are pending, got %d", empties)
+	}
+	tw.Delete("b")
+	if empties != 0 {
+		t.Fatalf("Expected no empty with a still pending, got %d", empties)
+	}
+	tw.Advance(3 * ManualInterval)
+	if empties != 1 {
+		t.Fatalf("Expected the last firing to empty the wheel, got %d", empties)
+	}
+
+	tw.Set("c", nil, ManualInterval)
+	tw.Set("d", nil, ManualInterval)
+	tw.FlushAll()
+	if empties != 2 {
+		t.Errorf("Expected FlushAll to empty the wheel, got %d", empties)
+	}
+	tw.FlushAll()
+	if empties != 2 {
+		t.Errorf("Expected an already empty wheel not to report again, got %d", empties)
+	}
+
+	tw.SetCron("job", nil, "* * * * *")
+	tw.Advance(2 * 60 * 1000 * ManualInterval)
+	if empties != 2 {
+		t.Errorf("Expected a cron series to keep the wheel busy, got %d", empties)
+	}
+	tw.Delete("job")
+	if empties != 3 {
+		t.Errorf("Expected deleting the series to empty the wheel, got %d", empties)
+	}
+}
@@ -0,0 +1,20 @@
+package timewheel
+
+import "errors"
+
+var (
+	ErrStopped         = errors.New("timewheel: wheel stopped")
+	ErrNotStarted      = errors.New("timewheel: wheel not started")
+	ErrLagging         = errors.New("timewheel: tick loop falling behind")
+	ErrNotFound        = errors.New("timewheel: key not found")
+	ErrDuplicate       = errors.New("timewheel: key already exists")
+	ErrOverCapacity    = errors.New("timewheel: capacity exceeded")
+	ErrBackpressure    = errors.New("timewheel: consumer not keeping up")
+	ErrOutOfRange      = errors.New("timewheel: value out of range")
+	ErrZeroTTL         = errors.New("timewheel: non-positive expiration rejected")
+	ErrInvalidCron     = errors.New("timewheel: invalid cron spec")
+	ErrInvalidKey      = errors.New("timewheel: invalid composite key")
+	ErrValueGone       = errors.New("timewheel: task value no longer available")
+	ErrDeleted         = errors.New("timewheel: task deleted")
+	ErrDurationTooLong = errors.New("timewheel: expiration beyond the maximum duration")
+)
@@ -0,0 +1,16 @@
+package timewheel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSetAfterStop(t *testing.T) {
+	tw := NewTimeWheel(100*time.Millisecond, 10, nil)
+	tw.Stop()
+
+	if err := tw.SetWith("test", "data", time.Second); !errors.Is(err, ErrStopped) {
+		t.Errorf("Expected ErrStopped, got %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+package timewheel
+
+import (
+	"context"
+	"time"
+)
+
+// WithExecTimeout gives every callback context a deadline d after the task
+// fires and cancels it, with cause ErrDeleted, when the task's key is deleted
+// while the callback is still running. Zero leaves callbacks unbounded.
+func WithExecTimeout(d time.Duration) Option {
+	return func(tw *TimeWheel) {
+		tw.execTimeout = d
+	}
+}
+
+// TaskExecTimeout overrides the wheel's execution timeout for one task; zero
+// disables it. A cron task applies it to every occurrence.
+func TaskExecTimeout(d time.Duration) SetOption {
+	return func(so *setOptions) {
+		so.execTimeout = d
+	}
+}
+
+// WithCancelOnDelete cancels the context of a running callback, with cause
+// ErrDeleted, when its key is deleted, whether or not it has an execution
+// timeout.
+func WithCancelOnDelete() Option {
+	return func(tw *TimeWheel) {
+		tw.cancelOnDelete = true
+	}
+}
+
+// flight is one delivery of a fired task to the callbacks, from the moment
+// it leaves the wheel until the callbacks return.
+type flight struct {
+	gen     uint64
+	cancel  context.CancelCauseFunc
+	aborted bool
+	done    chan struct{}
+}
+
+// flights are the deliveries in the air for one key. gen counts the Sets
+// since the first of them boarded; a delivery boarded under an older
+// generation has been superseded and must not start.
+type flights struct {
+	gen uint64
+	all map[*taskEntry]*flight
+}
+
+// of returns the delivery of entry, or nil.
+func (group *flights) of(entry *taskEntry) *flight {
+	if group == nil {
+		return nil
+	}
+	return group.all[entry]
+}
+
+// board records that entry is on its way to the callbacks. The tick boards
+// entries under the wheel lock, so a Delete or Set that no longer finds the
+// key still sees the delivery.
+func (tw *TimeWheel) board(entry *taskEntry) {
+	if !tw.hasCallback() {
+		return
+	}
+	tw.flightMu.Lock()
+	defer tw.flightMu.Unlock()
+	if tw.inflight == nil {
+		tw.inflight = make(map[string]*flights)
+	}
+	group := tw.inflight[entry.key]
+	if group == nil {
+		group = &flights{all: make(map[*taskEntry]*flight)}
+		tw.inflight[entry.key] = group
+	}
+	if group.all[entry] == nil {
+		group.all[entry] = &flight{gen: group.gen}
+	}
+}
+
+// supersede bumps the generation of key under the wheel lock, so deliveries
+// of its earlier schedules that have not started yet are dropped.
+func (tw *TimeWheel) supersede(key string) {
+	tw.flightMu.Lock()
+	defer tw.flightMu.Unlock()
+	if group := tw.inflight[key]; group != nil {
+		group.gen++
+	}
+}
+
+// claim starts the delivery of entry, reporting false if a later Set has
+// superseded it; the caller then drops it with superseded.
+func (tw *TimeWheel) claim(entry *taskEntry) bool {
+	tw.flightMu.Lock()
+	defer tw.flightMu.Unlock()
+	group := tw.inflight[entry.key]
+	f := group.of(entry)
+	if f == nil {
+		return true
+	}
+	return f.gen == group.gen
+}
+
+// superseded ends a delivery dropped by claim, outside the lock. fired says
+// the fire was already reported and only the callbacks are skipped.
+func (tw *TimeWheel) superseded(entry *taskEntry, fired bool) {
+	if !fired {
+		tw.remember(entry, ReasonReplaced, tw.now())
+	}
+	if tw.hooks.onRemove != nil {
+		tw.hooks.onRemove(entry.key, entry.value, ReasonReplaced)
+	}
+	tw.land(entry)
+}
+
+// land ends the delivery of entry and releases anyone waiting on it.
+func (tw *TimeWheel) land(entry *taskEntry) {
+	tw.flightMu.Lock()
+	defer tw.flightMu.Unlock()
+	group := tw.inflight[entry.key]
+	f := group.of(entry)
+	if f == nil {
+		return
+	}
+	delete(group.all, entry)
+	if len(group.all) == 0 {
+		delete(tw.inflight, entry.key)
+	}
+	if f.done != nil {
+		close(f.done)
+	}
+}
+
+// takeOff derives the context of a callback that Delete may cancel; stop
+// must be called once the callbacks return.
+func (tw *TimeWheel) takeOff(parent context.Context, entry *taskEntry) (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancelCause(parent)
+	stop = func() { cancel(context.Canceled) }
+	if entry.execTimeout > 0 {
+		var release context.CancelFunc
+		ctx, release = context.WithTimeout(ctx, entry.execTimeout)
+		stop = func() {
+			release()
+			cancel(context.Canceled)
+		}
+	}
+
+	tw.flightMu.Lock()
+	if f := tw.inflight[entry.key].of(entry); f != nil {
+		f.cancel = cancel
+		if f.aborted {
+			cancel(ErrDeleted)
+		}
+	}
+	tw.flightMu.Unlock()
+	return ctx, stop
+}
+
+// abort cancels the callbacks under way for key, where enabled.
+func (tw *TimeWheel) abort(key string) {
+	tw.flightMu.Lock()
+	defer tw.flightMu.Unlock()
+	group := tw.inflight[key]
+	if group == nil {
+		return
+	}
+	for _, f := range group.all {
+		f.aborted = true
+		if f.cancel != nil {
+			f.cancel(ErrDeleted)
+		}
+	}
+}
+
+// DeleteAndWait deletes the task like Delete, then blocks until every
+// callback already under way for key has returned, so per-key state can be
+// torn down safely. It must not be called from the key's own callback.
+func (tw *TimeWheel) DeleteAndWait(key string) (remaining time.Duration, existed bool) {
+	remaining, existed = tw.Delete(key)
+	tw.await(key)
+	return remaining, existed
+}
+
+// await blocks until the deliveries under way for key have landed.
+func (tw *TimeWheel) await(key string) {
+	tw.flightMu.Lock()
+	var waits []chan struct{}
+	if group := tw.inflight[key]; group != nil {
+		for _, f := range group.all {
+			if f.done == nil {
+				f.done = make(chan struct{})
+			}
+			waits = append(waits, f.done)
+		}
+	}
+	tw.flightMu.Unlock()
+
+	for _, done := range waits {
+		<-done
+	}
+}
+
+func (ns *Namespace) DeleteAndWait(key string) (time.Duration, bool) {
+	return ns.tw.DeleteAndWait(ns.Key(key))
+}
+
+func (s *ShardedTimeWheel) DeleteAndWait(key string) (time.Duration, bool) {
+	return s.Shard(key).DeleteAndWait(key)
+}
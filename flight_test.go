@@ -0,0 +1,269 @@
+package timewheel
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExecTimeoutDelete(t *testing.T) {
+	started := make(chan struct{})
+	causes := make(chan error, 1)
+	tw := NewTimeWheel(0, 10, nil, WithExecTimeout(time.Minute),
+		WithContextCallback(func(ctx context.Context, key string, value any) {
+			close(started)
+			<-ctx.Done()
+			causes <- context.Cause(ctx)
+		}))
+	defer tw.Stop()
+
+	tw.Set("job", "data", 2*ManualInterval)
+	tw.Advance(2 * ManualInterval)
+	<-started
+	if _, existed := tw.Delete("job"); existed {
+		t.Error("Expected Delete to report the fired task as gone")
+	}
+	select {
+	case err := <-causes:
+		if err != ErrDeleted {
+			t.Errorf("Expected cause ErrDeleted, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Delete did not cancel the running callback")
+	}
+}
+
+func TestExecTimeoutDeadline(t *testing.T) {
+	causes := make(chan error, 2)
+	tw := NewTimeWheel(0, 10, nil, WithSyncCallbacks(0), WithExecTimeout(10*time.Millisecond),
+		WithContextCallback(func(ctx context.Context, key string, value any) {
+			if _, ok := ctx.Deadline(); !ok {
+				causes <- nil
+				return
+			}
+			<-ctx.Done()
+			causes <- ctx.Err()
+		}))
+	defer tw.Stop()
+
+	tw.Set("bounded", "data", 2*ManualInterval)
+	tw.SetWith("unbounded", "data", 4*ManualInterval, TaskExecTimeout(0))
+
+	tw.Advance(2 * ManualInterval)
+	if err := <-causes; err != context.DeadlineExceeded {
+		t.Errorf("Expected the callback to hit its deadline, got %v", err)
+	}
+	tw.Advance(2 * ManualInterval)
+	if err := <-causes; err != nil {
+		t.Errorf("Expected TaskExecTimeout(0) to leave the callback unbounded, got %v", err)
+	}
+}
+
+func TestDeleteAndWait(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var finished atomic.Bool
+	tw := NewTimeWheel(0, 10, func(key string, value any) {
+		close(started)
+		<-release
+		finished.Store(true)
+	})
+	defer tw.Stop()
+
+	tw.Set("conn", "state", 2*ManualInterval)
+	tw.Advance(2 * ManualInterval)
+	<-started
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+	if _, existed := tw.DeleteAndWait("conn"); existed {
+		t.Error("Expected DeleteAndWait to report the fired task as gone")
+	}
+	if !finished.Load() {
+		t.Error("Expected DeleteAndWait to return after the callback")
+	}
+	if _, existed := tw.DeleteAndWait("idle"); existed {
+		t.Error("Expected nothing to delete for an unknown key")
+	}
+}
+
+func TestCancelOnDelete(t *testing.T) {
+	started := make(chan struct{})
+	var cause error
+	tw := NewTimeWheel(0, 10, nil, WithCancelOnDelete(),
+		WithContextCallback(func(ctx context.Context, key string, value any) {
+			if _, ok := ctx.Deadline(); ok {
+				t.Error("Expected no deadline without an execution timeout")
+			}
+			close(started)
+			<-ctx.Done()
+			cause = context.Cause(ctx)
+		}))
+	defer tw.Stop()
+
+	tw.Set("conn", "state", 2*ManualInterval)
+	tw.Advance(2 * ManualInterval)
+	<-started
+	tw.DeleteAndWait("conn")
+	if cause != ErrDeleted {
+		t.Errorf("Expected cause ErrDeleted, got %v", cause)
+	}
+	tw.flightMu.Lock()
+	defer tw.flightMu.Unlock()
+	if n := len(tw.inflight); n != 0 {
+		t.Errorf("Expected no callbacks in flight, got %d", n)
+	}
+}
+
+func TestSetSupersedesDispatched(t *testing.T) {
+	var fired []string
+	removed := make(map[string]Reason)
+	var tw *TimeWheel
+	tw = NewTimeWheel(0, 10, func(key string, value any) {
+		fired = append(fired, key+"="+value.(string))
+		if key == "a" {
+			// b was handed off by the same tick but has not started
+			tw.Set("b", "new", 3*ManualInterval)
+		}
+	}, WithSyncCallbacks(0), WithOnRemove(func(key string, value any, reason Reason) {
+		removed[key+"="+value.(string)] = reason
+	}))
+	defer tw.Stop()
+
+	tw.Set("a", "old", 2*ManualInterval)
+	tw.Set("b", "old", 2*ManualInterval)
+	tw.Advance(2 * ManualInterval)
+	if len(fired) != 1 || fired[0] != "a=old" {
+		t.Fatalf("Expected only a to fire, got %v", fired)
+	}
+	if removed["b=old"] != ReasonReplaced {
+		t.Errorf("Expected the superseded fire reported as replaced, got %v", removed["b=old"])
+	}
+
+	tw.Advance(3 * ManualInterval)
+	if len(fired) != 2 || fired[1] != "b=new" {
+		t.Errorf("Expected the new schedule of b to fire, got %v", fired)
+	}
+}
+
+func TestSetSupersedesLaunched(t *testing.T) {
+	rt := &simRuntime{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	var fired []string
+	tw := NewTimeWheel(0, 10, func(key string, value any) {
+		fired = append(fired, value.(string))
+	}, WithRuntime(rt))
+	defer tw.Stop()
+
+	tw.Set("k", "old", 2*ManualInterval)
+	tw.Advance(2 * ManualInterval) // the callback goroutine is launched but queued
+	tw.Set("k", "new", 2*ManualInterval)
+	rt.drain()
+	if len(fired) != 0 {
+		t.Fatalf("Expected the old schedule not to fire after Set returned, got %v", fired)
+	}
+
+	tw.Advance(2 * ManualInterval)
+	rt.drain()
+	if len(fired) != 1 || fired[0] != "new" {
+		t.Errorf("Expected only the new schedule to fire, got %v", fired)
+	}
+	tw.flightMu.Lock()
+	defer tw.flightMu.Unlock()
+	if n := len(tw.inflight); n != 0 {
+		t.Errorf("Expected no deliveries in flight, got %d", n)
+	}
+}
+
+func TestSetSupersedesUngated(t *testing.T) {
+	var fired []string
+	var tw *TimeWheel
+	tw = NewTimeWheel(0, 10, func(key string, value any) {
+		fired = append(fired, key+"="+value.(string))
+		if key == "a" {
+			// b came due behind the gate too and is next in line
+			tw.Set("b", "new", 3*ManualInterval)
+		}
+	}, WithStartGate(), WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	tw.Set("a", "old", ManualInterval)
+	tw.Set("b", "old", 2*ManualInterval)
+	tw.Advance(2 * ManualInterval)
+	tw.Start(context.Background())
+	if len(fired) != 1 || fired[0] != "a=old" {
+		t.Fatalf("Expected only a to fire at Start, got %v", fired)
+	}
+
+	tw.Advance(3 * ManualInterval)
+	if len(fired) != 2 || fired[1] != "b=new" {
+		t.Errorf("Expected the new schedule of b to fire, got %v", fired)
+	}
+}
+
+// heldRuntime is the system runtime, except that goroutines launched while
+// it is held wait for release.
+type heldRuntime struct {
+	Runtime
+	mu     sync.Mutex
+	held   bool
+	queued []func()
+}
+
+func (rt *heldRuntime) Go(f func()) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if rt.held {
+		rt.queued = append(rt.queued, f)
+		return
+	}
+	go f()
+}
+
+func (rt *heldRuntime) hold() {
+	rt.mu.Lock()
+	rt.held = true
+	rt.mu.Unlock()
+}
+
+func (rt *heldRuntime) waiting() int {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return len(rt.queued)
+}
+
+func (rt *heldRuntime) release() {
+	rt.mu.Lock()
+	queued := rt.queued
+	rt.held, rt.queued = false, nil
+	rt.mu.Unlock()
+	for _, f := range queued {
+		f()
+	}
+}
+
+func TestSetSupersedesTimed(t *testing.T) {
+	rt := &heldRuntime{Runtime: SystemRuntime()}
+	fired := make(chan string, 2)
+	tw := NewTimeWheel(100*time.Millisecond, 10, func(key string, value any) {
+		fired <- value.(string)
+	}, WithHybridTimers(), WithRuntime(rt))
+	defer tw.Stop()
+
+	tw.Set("k", "old", 5*time.Millisecond)
+	rt.hold() // the timer is armed; the callback goroutine it launches waits
+	for deadline := time.Now().Add(time.Second); rt.waiting() == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("Timer did not go off")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	tw.Set("k", "new", time.Hour)
+	rt.release()
+	select {
+	case v := <-fired:
+		t.Errorf("Expected the old schedule not to fire after Set returned, got %s", v)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
@@ -0,0 +1,33 @@
+package timewheel
+
+import "strings"
+
+// FlushWhere removes every task for which match reports true, without firing
+// it, and returns how many were removed. match runs under the wheel lock and
+// must not call back into the wheel.
+func (tw *TimeWheel) FlushWhere(match func(key string, value any) bool) int {
+	tw.mu.Lock()
+	defer tw.unlock()
+
+	n := 0
+	for key, entry := range tw.keyMap {
+		if !match(key, entry.value) {
+			continue
+		}
+		tw.untrack(entry)
+		tw.unlink(entry)
+		tw.record(hookCancel, entry)
+		tw.removed(entry, ReasonFlushed)
+		releaseEntry(entry)
+		n++
+	}
+	return n
+}
+
+// FlushPrefix removes every task whose key starts with prefix, without
+// firing it, and returns how many were removed.
+func (tw *TimeWheel) FlushPrefix(prefix string) int {
+	return tw.FlushWhere(func(key string, _ any) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
@@ -0,0 +1,29 @@
+package timewheel
+
+import "testing"
+
+func TestFlushWhere(t *testing.T) {
+	fired := 0
+	tw := NewTimeWheel(0, 10, func(string, any) { fired++ }, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	tw.Set("cache:a", 1, ManualInterval)
+	tw.Set("cache:b", 2, ManualInterval)
+	tw.Set("session:a", 3, ManualInterval)
+	tw.Set("session:b", 4, ManualInterval)
+
+	if n := tw.FlushPrefix("cache:"); n != 2 {
+		t.Errorf("Expected FlushPrefix to remove 2 tasks, removed %d", n)
+	}
+	if n := tw.FlushWhere(func(_ string, v any) bool { return v.(int) > 3 }); n != 1 {
+		t.Errorf("Expected FlushWhere to remove 1 task, removed %d", n)
+	}
+	if _, _, ok := tw.Remaining("session:a"); !ok {
+		t.Error("Expected the unmatched task to stay pending")
+	}
+
+	tw.Advance(ManualInterval)
+	if fired != 1 {
+		t.Errorf("Expected only the remaining task to fire, got %d", fired)
+	}
+}
@@ -0,0 +1,125 @@
+// Package fsclean removes temporary files and directories once their TTL
+// passes. Pending removals are kept in a write-ahead log, so a removal
+// scheduled before a crash or restart still happens: overdue paths are
+// removed as soon as the log is reopened.
+package fsclean
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nzai/timewheel"
+)
+
+type Cleaner struct {
+	tw       *timewheel.TimeWheel
+	wal      *timewheel.WAL
+	interval time.Duration
+	onError  func(path string, err error)
+}
+
+type Option func(*Cleaner)
+
+// WithInterval sets how precisely removals are timed. Defaults to a second.
+func WithInterval(d time.Duration) Option {
+	return func(c *Cleaner) {
+		c.interval = d
+	}
+}
+
+// WithErrorHandler receives removals that failed. Failed removals are not
+// retried.
+func WithErrorHandler(h func(path string, err error)) Option {
+	return func(c *Cleaner) {
+		c.onError = h
+	}
+}
+
+// New starts a cleaner recording pending removals at walPath and performs
+// those left from a previous run. An empty walPath keeps them in memory only.
+func New(walPath string, opts ...Option) (*Cleaner, error) {
+	c := &Cleaner{interval: time.Second}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	var wheelOpts []timewheel.Option
+	if walPath != "" {
+		w, err := timewheel.OpenWAL(walPath)
+		if err != nil {
+			return nil, err
+		}
+		c.wal = w
+		wheelOpts = append(wheelOpts, timewheel.WithWAL(w))
+	}
+	c.tw = timewheel.NewTimeWheel(c.interval, 60, c.expire, wheelOpts...)
+	return c, nil
+}
+
+// ScheduleRemove removes path, and everything under it if it is a directory,
+// once ttl has passed. Scheduling a path again replaces its TTL. The removal
+// is on disk, fsynced, when ScheduleRemove returns.
+func (c *Cleaner) ScheduleRemove(path string, ttl time.Duration) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if err := c.tw.SetWith(abs, abs, ttl, timewheel.TaskZeroTTL(timewheel.FireAsync)); err != nil {
+		return err
+	}
+	return c.sync()
+}
+
+// Cancel keeps path from being removed and reports whether a removal was
+// pending.
+func (c *Cleaner) Cancel(path string) (bool, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false, err
+	}
+	_, existed := c.tw.Delete(abs)
+	return existed, c.sync()
+}
+
+// Pending returns the paths waiting to be removed, with their deadlines.
+func (c *Cleaner) Pending() map[string]time.Time {
+	pending := make(map[string]time.Time)
+	c.tw.Range(func(key string, _ any, expireAt time.Time) bool {
+		pending[key] = expireAt
+		return true
+	})
+	return pending
+}
+
+// Close stops the cleaner. Pending removals stay in the log for the next New.
+func (c *Cleaner) Close() error {
+	c.tw.Stop()
+	if c.wal == nil {
+		return nil
+	}
+	if err := c.wal.Sync(); err != nil {
+		c.wal.Close()
+		return err
+	}
+	return c.wal.Close()
+}
+
+func (c *Cleaner) sync() error {
+	if c.wal == nil {
+		return nil
+	}
+	if err := c.wal.Err(); err != nil {
+		return err
+	}
+	return c.wal.Sync()
+}
+
+// expire removes a path whose TTL passed. A path already gone, say because a
+// crash came after the removal but before the log recorded it, is no error.
+func (c *Cleaner) expire(_ string, value any) {
+	path, _ := value.(string)
+	if err := os.RemoveAll(path); err != nil && c.onError != nil {
+		c.onError(path, err)
+	}
+}
@@ -0,0 +1,78 @@
+package fsclean
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// gone waits for path to disappear.
+func gone(t *testing.T, path string) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Expected %s to be removed", path)
+}
+
+func TestScheduleRemove(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "upload.tmp")
+	tree := filepath.Join(dir, "extract")
+	kept := filepath.Join(dir, "kept")
+	os.WriteFile(file, []byte("data"), 0o644)
+	os.MkdirAll(filepath.Join(tree, "nested"), 0o755)
+	os.WriteFile(kept, nil, 0o644)
+
+	c, err := New("", WithInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.ScheduleRemove(file, 20*time.Millisecond)
+	c.ScheduleRemove(tree, 20*time.Millisecond)
+	c.ScheduleRemove(kept, 20*time.Millisecond)
+	if ok, _ := c.Cancel(kept); !ok {
+		t.Error("Expected Cancel to find the pending removal")
+	}
+	if n := len(c.Pending()); n != 2 {
+		t.Errorf("Expected 2 pending removals, got %d", n)
+	}
+
+	gone(t, file)
+	gone(t, tree)
+	if _, err := os.Stat(kept); err != nil {
+		t.Errorf("Expected the cancelled path to stay, got %v", err)
+	}
+}
+
+func TestScheduleRemoveSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "cleaner.wal")
+	file := filepath.Join(dir, "cache.tmp")
+	os.WriteFile(file, nil, 0o644)
+
+	c, err := New(log, WithInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ScheduleRemove(file, 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+
+	time.Sleep(80 * time.Millisecond)
+	if _, err := os.Stat(file); err != nil {
+		t.Fatalf("Expected the file to outlive the stopped cleaner, got %v", err)
+	}
+	c, err = New(log, WithInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	gone(t, file)
+}
@@ -0,0 +1,144 @@
+package timewheel
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// WheelGroup drives many independent wheels from a single ticker goroutine,
+// for applications that need dozens of schedulers with their own callbacks
+// but not dozens of tickers. Members share the group's base interval, clock
+// and runtime, which the first member takes from the group's options; they
+// tick in turn, so a member with slow synchronous callbacks delays the rest.
+//
+// A member joining between ticks takes the group's phase, so like a task set
+// between ticks its first tasks may fire up to one base interval early.
+type WheelGroup struct {
+	interval time.Duration
+	opts     []Option
+
+	mu       sync.Mutex
+	lead     *TimeWheel
+	wheels   []*TimeWheel
+	lastTick time.Time
+	// quit stops the ticker goroutine; nil while none runs.
+	quit chan struct{}
+}
+
+// NewWheelGroup returns an empty group ticking every baseInterval once its
+// first wheel joins. opts apply to every member before its own.
+func NewWheelGroup(baseInterval time.Duration, opts ...Option) *WheelGroup {
+	return &WheelGroup{
+		interval: baseInterval,
+		opts:     opts,
+	}
+}
+
+// NewTimeWheel adds a running wheel to the group, like the package-level
+// NewTimeWheel. WithRealtime has no effect on members.
+func (g *WheelGroup) NewTimeWheel(slotsPerLayer int, callback func(key string, value any), opts ...Option) *TimeWheel {
+	all := make([]Option, 0, len(g.opts)+len(opts)+1)
+	all = append(append(append(all, g.opts...), opts...), func(tw *TimeWheel) { tw.group = g })
+
+	g.mu.Lock()
+	tw := newTimeWheel(g.interval, slotsPerLayer, callback, all)
+	if g.lead == nil {
+		g.lead = tw
+	}
+	g.mu.Unlock()
+
+	tw.started = true
+	tw.run()
+	return tw
+}
+
+// Wheels returns the members not yet stopped.
+func (g *WheelGroup) Wheels() []*TimeWheel {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.live()
+}
+
+// Stop stops the ticker goroutine and every member. A wheel added or
+// restarted afterwards starts the ticker again.
+func (g *WheelGroup) Stop() {
+	g.mu.Lock()
+	if g.quit != nil {
+		close(g.quit)
+		g.quit = nil
+	}
+	wheels := g.live()
+	g.mu.Unlock()
+
+	for _, tw := range wheels {
+		tw.Stop()
+	}
+}
+
+// join starts ticking tw with the group, launching the ticker goroutine
+// for the first member and for the first after a Stop.
+func (g *WheelGroup) join(tw *TimeWheel) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.quit == nil {
+		g.lastTick = tw.clock.Now()
+		quit := make(chan struct{})
+		g.quit = quit
+		ticker := tw.clock.NewTicker(tw.baseInterval)
+		tw.runtime.Go(func() { g.loop(ticker, quit) })
+	}
+	tw.startedAt = g.lastTick
+	tw.prevTickAt = g.lastTick
+	tw.beat(g.lastTick)
+	// A member restarted before the loop noticed it stop is still listed
+	if !slices.Contains(g.wheels, tw) {
+		g.wheels = append(g.wheels, tw)
+	}
+}
+
+func (g *WheelGroup) loop(ticker Ticker, quit <-chan struct{}) {
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C():
+			for _, tw := range g.beat() {
+				tw.tick()
+			}
+		case <-quit:
+			return
+		}
+	}
+}
+
+// beat records a tick and returns the members to step through it.
+func (g *WheelGroup) beat() []*TimeWheel {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.lastTick = g.lead.clock.Now()
+	return g.live()
+}
+
+// groupLead returns the member whose clock and interval a new member of the
+// same group shares, or nil. The group lock is held while wheels are built.
+func (tw *TimeWheel) groupLead() *TimeWheel {
+	if tw.group == nil {
+		return nil
+	}
+	return tw.group.lead
+}
+
+// live drops stopped members and returns a copy of the rest.
+func (g *WheelGroup) live() []*TimeWheel {
+	n := 0
+	for _, tw := range g.wheels {
+		if !tw.stopped() {
+			g.wheels[n] = tw
+			n++
+		}
+	}
+	clear(g.wheels[n:])
+	g.wheels = g.wheels[:n]
+	return append([]*TimeWheel(nil), g.wheels...)
+}
@@ -0,0 +1,71 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWheelGroup(t *testing.T) {
+	clock := newFakeClock()
+	g := NewWheelGroup(time.Second, WithClock(clock))
+	defer g.Stop()
+
+	fired := make(chan string, 3)
+	a := g.NewTimeWheel(10, func(k string, _ any) { fired <- "a:" + k })
+	b := g.NewTimeWheel(10, func(k string, _ any) { fired <- "b:" + k })
+	a.Set("x", nil, 2*time.Second)
+	b.Set("y", nil, 2*time.Second)
+
+	// One ticker feeds both wheels, so each tick steps every member
+	clock.tick(time.Second)
+	clock.tick(time.Second)
+	got := map[string]bool{}
+	for range 2 {
+		select {
+		case k := <-fired:
+			got[k] = true
+		case <-time.After(time.Second):
+			t.Fatalf("Expected both members to fire, got %v", got)
+		}
+	}
+	if !got["a:x"] || !got["b:y"] {
+		t.Errorf("Expected each wheel's own callback, got %v", got)
+	}
+
+	a.Stop()
+	if n := len(g.Wheels()); n != 1 {
+		t.Errorf("Expected the stopped member to leave the group, %d remain", n)
+	}
+	b.Set("z", nil, time.Second)
+	clock.tick(time.Second)
+	select {
+	case k := <-fired:
+		if k != "b:z" {
+			t.Errorf("Expected b:z, got %s", k)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the remaining member to keep ticking")
+	}
+}
+
+func TestWheelGroupAfterStop(t *testing.T) {
+	clock := newFakeClock()
+	g := NewWheelGroup(time.Second, WithClock(clock))
+	g.NewTimeWheel(10, nil)
+	g.Stop()
+
+	fired := make(chan string, 1)
+	tw := g.NewTimeWheel(10, func(k string, _ any) { fired <- k })
+	defer g.Stop()
+	tw.Set("x", nil, time.Second)
+
+	clock.tick(time.Second)
+	select {
+	case k := <-fired:
+		if k != "x" {
+			t.Errorf("Expected x, got %s", k)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected a wheel added after Stop to tick")
+	}
+}
@@ -0,0 +1,32 @@
+package timewheel
+
+import (
+	"math"
+	"time"
+)
+
+const defaultLayers = 3
+
+// WithMaxLayers lets the wheel add layers on demand, up to n, when a task
+// does not fit in the top layer's span. Beyond that depth long tasks wait on
+// the top layer for extra rounds. The default is the initial three layers.
+func WithMaxLayers(n int) Option {
+	return func(tw *TimeWheel) {
+		tw.maxLayers = n
+	}
+}
+
+// grow adds layers until the top one spans d or the depth limit is reached.
+func (tw *TimeWheel) grow(d time.Duration) {
+	for len(tw.layers) < tw.maxLayers {
+		top := tw.layers[len(tw.layers)-1]
+		if top.interval > math.MaxInt64/time.Duration(top.slots) {
+			return
+		}
+		span := top.interval * time.Duration(top.slots)
+		if d < span {
+			return
+		}
+		tw.addLayer(span)
+	}
+}
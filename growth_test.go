@@ -0,0 +1,44 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDynamicLayerGrowth(t *testing.T) {
+	fired := make(chan string, 1)
+	tw := NewTimeWheel(0, 4, func(k string, v any) {
+		fired <- k
+	}, WithMaxLayers(5))
+	defer tw.Stop()
+
+	// Three layers of 4 slots span 64 ticks; 200 ticks needs a fourth
+	tw.Set("long", "data", 200*ManualInterval)
+	if n := tw.Stats().Layers; n != 4 {
+		t.Fatalf("Expected 4 layers, got %d", n)
+	}
+
+	tw.Advance(199 * ManualInterval)
+	select {
+	case <-fired:
+		t.Fatal("Long task fired early")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	tw.Tick()
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("Long task did not fire")
+	}
+}
+
+func TestLayerGrowthLimit(t *testing.T) {
+	tw := NewTimeWheel(0, 4, nil)
+	defer tw.Stop()
+
+	tw.Set("long", "data", 10000*ManualInterval)
+	if n := tw.Stats().Layers; n != defaultLayers {
+		t.Errorf("Expected growth to stay at %d layers by default, got %d", defaultLayers, n)
+	}
+}
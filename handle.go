@@ -0,0 +1,118 @@
+package timewheel
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Timer is a handle on one scheduled task, in the manner of time.Timer. It
+// stays bound to the task it created: once the key is reused by a Set
+// elsewhere, the handle no longer affects it.
+type Timer struct {
+	tw    *TimeWheel
+	key   string
+	value any
+	// gen counts activations; fired holds the last one that fired.
+	gen   atomic.Uint64
+	fired atomic.Uint64
+}
+
+// NewTimer schedules value under key like SetWith and returns a handle on
+// the task.
+func (tw *TimeWheel) NewTimer(key string, value any, d time.Duration, opts ...SetOption) (*Timer, error) {
+	t := &Timer{tw: tw, key: key, value: value}
+	t.gen.Store(1)
+	opts = append(opts[:len(opts):len(opts)], func(so *setOptions) { so.handle = t })
+	if err := tw.SetWith(key, value, d, opts...); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Timer) Key() string {
+	return t.key
+}
+
+// Cancel stops the task from firing. It reports whether it did so, false
+// meaning the task already fired or was removed.
+func (t *Timer) Cancel() bool {
+	tw := t.tw
+	done := tw.lockFor(&tw.latency.delete)
+	defer tw.unlock()
+	defer done()
+
+	entry := tw.owned(t)
+	if entry == nil {
+		return false
+	}
+	tw.cancel(entry, ReasonDeleted)
+	return true
+}
+
+// Reset makes the task fire d from now and reports whether it was still
+// pending. A task that already fired or was cancelled is scheduled again
+// with its original value, unless its key has meanwhile been taken.
+func (t *Timer) Reset(d time.Duration) bool {
+	tw := t.tw
+	if tw.stopped() {
+		return false
+	}
+	done := tw.lockFor(&tw.latency.move)
+	if entry := tw.owned(t); entry != nil {
+		tw.reschedule(entry, d)
+		done()
+		tw.unlock()
+		return true
+	}
+	if _, taken := tw.keyMap[t.key]; taken {
+		done()
+		tw.unlock()
+		return false
+	}
+	t.gen.Add(1)
+	so := tw.newSetOptions(nil)
+	so.handle = t
+	fireNow, _ := tw.set(t.key, t.value, d, so)
+	done()
+	tw.unlock()
+
+	if fireNow != nil {
+		tw.fireSync(fireNow)
+	}
+	return false
+}
+
+// Remaining reports the time left until the task's deadline, and false if
+// it is no longer pending.
+func (t *Timer) Remaining() (time.Duration, bool) {
+	tw := t.tw
+	tw.mu.RLock()
+	defer tw.mu.RUnlock()
+
+	entry := tw.owned(t)
+	if entry == nil {
+		return 0, false
+	}
+	return clampDuration(entry.expiration.Sub(tw.now())), true
+}
+
+// Fired reports whether the task fired since it was created or last Reset.
+func (t *Timer) Fired() bool {
+	return t.fired.Load() == t.gen.Load()
+}
+
+// owned returns the pending entry t refers to, or nil.
+func (tw *TimeWheel) owned(t *Timer) *taskEntry {
+	entry, exists := tw.keyMap[t.key]
+	if !exists || entry.handle != t {
+		return nil
+	}
+	return entry
+}
+
+// markFired records a firing on the entry's handle, if it has one.
+func (entry *taskEntry) markFired() {
+	if entry.handle != nil {
+		entry.handle.fired.Store(entry.handleGen)
+	}
+}
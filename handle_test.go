@@ -0,0 +1,51 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimerHandle(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	timer, err := tw.NewTimer("key", "data", 5*ManualInterval)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if left, ok := timer.Remaining(); !ok || left != 5*ManualInterval {
+		t.Errorf("Expected 5ms remaining, got %s %v", left, ok)
+	}
+	if !timer.Reset(10 * ManualInterval) {
+		t.Error("Expected Reset of a pending timer to report it active")
+	}
+	tw.Advance(9 * ManualInterval)
+	if timer.Fired() {
+		t.Fatal("Expected the reset timer not to fire at its old deadline")
+	}
+	tw.Tick()
+	if !timer.Fired() {
+		t.Fatal("Expected the timer to fire at its new deadline")
+	}
+	if timer.Cancel() {
+		t.Error("Expected Cancel after the fire to report nothing stopped")
+	}
+
+	// A fired timer can be armed again; the handle ignores a reused key
+	if timer.Reset(ManualInterval) {
+		t.Error("Expected Reset of a fired timer to report it inactive")
+	}
+	if timer.Fired() {
+		t.Error("Expected Reset to clear Fired")
+	}
+	if !timer.Cancel() {
+		t.Error("Expected Cancel to stop the re-armed timer")
+	}
+	tw.Set("key", "other", time.Second)
+	if timer.Cancel() || timer.Reset(ManualInterval) {
+		t.Error("Expected the handle not to touch a task that reused its key")
+	}
+	if _, _, ok := tw.Remaining("key"); !ok {
+		t.Error("Expected the other task to stay pending")
+	}
+}
@@ -0,0 +1,88 @@
+package timewheel
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultHealthTicks is the health threshold in base intervals when
+// WithHealthThreshold is not given.
+const defaultHealthTicks = 10
+
+// WithHealthThreshold sets how far behind schedule the tick loop may run, or
+// how long it may go without ticking, before Healthy reports an error. It
+// defaults to ten base intervals.
+func WithHealthThreshold(d time.Duration) Option {
+	return func(tw *TimeWheel) {
+		tw.healthThreshold = d
+	}
+}
+
+// Running reports whether the wheel has been started and not stopped since.
+func (tw *TimeWheel) Running() bool {
+	tw.mu.RLock()
+	started := tw.started
+	tw.mu.RUnlock()
+	return started && !tw.stopped()
+}
+
+// Healthy returns nil while the wheel runs and its ticks keep up, for
+// wiring into a readiness probe. A tick loop blocked by a slow consumer or
+// starved of CPU fails the check once it falls behind the health threshold.
+// Manual wheels have no loop and, like hibernating ones, only need to be
+// running.
+func (tw *TimeWheel) Healthy() error {
+	switch {
+	case tw.stopped():
+		return ErrStopped
+	case !tw.Running():
+		return ErrNotStarted
+	case tw.manual, tw.asleep():
+		return nil
+	}
+
+	limit := tw.healthLimit()
+	if lag := time.Duration(tw.tickLag.Load()); lag > limit {
+		return fmt.Errorf("%w: latest tick ran %v late", ErrLagging, lag)
+	}
+	tw.mu.RLock()
+	stride := time.Duration(tw.stride)
+	tw.mu.RUnlock()
+	since := tw.clock.Now().Sub(time.Unix(0, tw.lastBeat.Load()))
+	if since > limit+stride*tw.baseInterval {
+		return fmt.Errorf("%w: no tick for %v", ErrLagging, since)
+	}
+	return nil
+}
+
+func (tw *TimeWheel) healthLimit() time.Duration {
+	if tw.healthThreshold > 0 {
+		return tw.healthThreshold
+	}
+	return defaultHealthTicks * tw.baseInterval
+}
+
+// beat records that the tick loop is alive at now.
+func (tw *TimeWheel) beat(now time.Time) {
+	tw.lastBeat.Store(now.UnixNano())
+}
+
+// Running reports whether every shard is running.
+func (s *ShardedTimeWheel) Running() bool {
+	for _, shard := range s.shards {
+		if !shard.Running() {
+			return false
+		}
+	}
+	return true
+}
+
+// Healthy returns the first shard's error, if any.
+func (s *ShardedTimeWheel) Healthy() error {
+	for _, shard := range s.shards {
+		if err := shard.Healthy(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package timewheel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHealthy(t *testing.T) {
+	clock := newFakeClock()
+	tw := NewTimeWheel(time.Second, 10, func(string, any) {}, WithClock(clock), WithHealthThreshold(3*time.Second))
+	defer tw.Stop()
+
+	if !tw.Running() || tw.Healthy() != nil {
+		t.Fatalf("Expected a fresh wheel to be healthy, got %v", tw.Healthy())
+	}
+	clock.tick(time.Second)
+	if err := tw.Healthy(); err != nil {
+		t.Errorf("Expected a ticking wheel to be healthy, got %v", err)
+	}
+
+	// A stalled loop fails the check, and so does the late tick that follows
+	clock.sleep(5 * time.Second)
+	if err := tw.Healthy(); !errors.Is(err, ErrLagging) {
+		t.Errorf("Expected ErrLagging without ticks, got %v", err)
+	}
+	clock.tick(time.Second)
+	time.Sleep(10 * time.Millisecond)
+	if err := tw.Healthy(); !errors.Is(err, ErrLagging) {
+		t.Errorf("Expected ErrLagging for a late tick, got %v", err)
+	}
+
+	tw.Stop()
+	if tw.Running() || !errors.Is(tw.Healthy(), ErrStopped) {
+		t.Errorf("Expected a stopped wheel to report ErrStopped, got %v", tw.Healthy())
+	}
+}
+
+func TestHealthyUnstarted(t *testing.T) {
+	tw := NewUnstartedTimeWheel(0, 10, func(string, any) {})
+	defer tw.Stop()
+
+	if tw.Running() || !errors.Is(tw.Healthy(), ErrNotStarted) {
+		t.Errorf("Expected ErrNotStarted, got %v", tw.Healthy())
+	}
+}
@@ -0,0 +1,69 @@
+package timewheel
+
+// WithHibernation stops the ticker while the wheel has nothing to do, so an
+// idle service does not wake up every base interval for nothing. The next
+// Set, or anything else that schedules a task, wakes the wheel, which then
+// counts ticks afresh from the wake-up as if it had just started. Manual and
+// grouped wheels have no ticker of their own and ignore it.
+func WithHibernation() Option {
+	return func(tw *TimeWheel) {
+		tw.hibernate = true
+		tw.wake = make(chan struct{}, 1)
+	}
+}
+
+// idle reports whether the wheel has nothing to tick for and, if so, marks
+// it sleeping under the same lock, so a Set right after wakes it.
+func (tw *TimeWheel) idle() bool {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	tw.sleeping = len(tw.keyMap) == 0 && len(tw.pinned) == 0 && len(tw.backlog) == 0 && len(tw.warnings) == 0
+	return tw.sleeping
+}
+
+// sleep parks the tick loop until the wheel is woken, or returns false if
+// the run ends first.
+func (tw *TimeWheel) sleep(life *lifetime) bool {
+	select {
+	case <-tw.wake:
+	case <-life.quit:
+		tw.mu.Lock()
+		tw.sleeping = false
+		tw.mu.Unlock()
+		return false
+	}
+
+	// Count ticks from now, as after a start, so the time asleep is neither
+	// caught up on nor reported as lag
+	tw.mu.Lock()
+	now := tw.clock.Now()
+	tw.startedAt = now
+	tw.prevTickAt = now
+	tw.ticksDone = 0
+	tw.beat(now)
+	tw.mu.Unlock()
+	return true
+}
+
+// rouse wakes a sleeping tick loop. Callers hold the lock.
+func (tw *TimeWheel) rouse() {
+	if !tw.sleeping {
+		return
+	}
+	tw.sleeping = false
+	select {
+	case tw.wake <- struct{}{}:
+	default:
+	}
+}
+
+// asleep reports whether the tick loop is hibernating.
+func (tw *TimeWheel) asleep() bool {
+	if !tw.hibernate {
+		return false
+	}
+	tw.mu.RLock()
+	defer tw.mu.RUnlock()
+	return tw.sleeping
+}
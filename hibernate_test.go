@@ -0,0 +1,68 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHibernation(t *testing.T) {
+	fired := make(chan time.Time, 1)
+	tw := NewTimeWheel(5*time.Millisecond, 10, func(string, any) {
+		fired <- time.Now()
+	}, WithHibernation())
+	defer tw.Stop()
+
+	waitAsleep := func() {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for !tw.asleep() {
+			if time.Now().After(deadline) {
+				t.Fatal("Expected the idle wheel to hibernate")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	waitAsleep()
+	ticks := tw.Stats().Ticks
+	time.Sleep(30 * time.Millisecond)
+	if got := tw.Stats().Ticks; got != ticks {
+		t.Errorf("Expected no ticks while hibernating, got %d more", got-ticks)
+	}
+	if err := tw.Healthy(); err != nil {
+		t.Errorf("Expected a hibernating wheel to be healthy, got %v", err)
+	}
+
+	set := time.Now()
+	tw.Set("task", nil, 20*time.Millisecond)
+	select {
+	case at := <-fired:
+		if d := at.Sub(set); d < 15*time.Millisecond {
+			t.Errorf("Expected the task to fire after its TTL counted from the Set, fired after %v", d)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Task did not fire after waking the wheel")
+	}
+	waitAsleep()
+}
+
+func TestHibernationMaintenance(t *testing.T) {
+	runs := make(chan struct{}, 1)
+	tw := NewTimeWheel(5*time.Millisecond, 10, nil, WithHibernation())
+	defer tw.Stop()
+
+	time.Sleep(20 * time.Millisecond)
+	tw.Maintain("job", 10*time.Millisecond, func() {
+		select {
+		case runs <- struct{}{}:
+		default:
+		}
+	})
+	select {
+	case <-runs:
+	case <-time.After(time.Second):
+		t.Fatal("Expected a maintenance task to wake the wheel")
+	}
+	if tw.asleep() {
+		t.Error("Expected the wheel to stay awake for its maintenance task")
+	}
+}
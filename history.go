@@ -0,0 +1,100 @@
+package timewheel
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// HistoryEvent is a task firing or leaving the wheel, as kept by
+// WithHistory.
+type HistoryEvent struct {
+	Key    string
+	Reason Reason
+	// ScheduledAt is when the task was set and Expiration its deadline.
+	ScheduledAt time.Time
+	Expiration  time.Time
+	// At is when the task fired or was removed.
+	At          time.Time
+	Annotations map[string]string
+	Tags        []string
+}
+
+// history is a ring of the latest events.
+type history struct {
+	mu     sync.Mutex
+	events []HistoryEvent
+	next   int
+	full   bool
+}
+
+// WithHistory keeps the last n firings and removals for History, to debug
+// a task that fired early, late or not at all.
+func WithHistory(n int) Option {
+	return func(tw *TimeWheel) {
+		if n > 0 {
+			tw.history = &history{events: make([]HistoryEvent, n)}
+		}
+	}
+}
+
+// History returns up to the n most recent events, oldest first. It is
+// empty unless the wheel was built WithHistory.
+func (tw *TimeWheel) History(n int) []HistoryEvent {
+	h := tw.history
+	if h == nil || n <= 0 {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	size := h.next
+	if h.full {
+		size = len(h.events)
+	}
+	n = min(n, size)
+	events := make([]HistoryEvent, n)
+	for i := range events {
+		events[i] = h.events[(h.next-n+i+len(h.events))%len(h.events)]
+	}
+	return events
+}
+
+// remember adds an event for entry to the history, if one is kept.
+func (tw *TimeWheel) remember(entry *taskEntry, reason Reason, at time.Time) {
+	h := tw.history
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	h.events[h.next] = HistoryEvent{
+		Key:         entry.key,
+		Reason:      reason,
+		ScheduledAt: entry.scheduledAt,
+		Expiration:  entry.expiration,
+		At:          at,
+		Annotations: entry.annotations,
+		Tags:        entry.tags,
+	}
+	h.next++
+	if h.next == len(h.events) {
+		h.next = 0
+		h.full = true
+	}
+	h.mu.Unlock()
+}
+
+// History merges the shards' histories by time.
+func (s *ShardedTimeWheel) History(n int) []HistoryEvent {
+	var events []HistoryEvent
+	for _, tw := range s.shards {
+		events = append(events, tw.History(n)...)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].At.Before(events[j].At)
+	})
+	if len(events) > n {
+		events = events[len(events)-n:]
+	}
+	return events
+}
@@ -0,0 +1,86 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	tw := NewTimeWheel(0, 10, func(string, any) {}, WithSyncCallbacks(0), WithHistory(3))
+	defer tw.Stop()
+
+	tw.Set("fired", nil, 2*ManualInterval)
+	tw.Set("deleted", nil, 2*ManualInterval)
+	tw.Set("replaced", nil, 2*ManualInterval)
+	tw.Delete("deleted")
+	tw.Set("replaced", nil, 4*ManualInterval)
+	tw.Advance(2 * ManualInterval)
+
+	events := tw.History(10)
+	want := []struct {
+		key    string
+		reason Reason
+	}{{"deleted", ReasonDeleted}, {"replaced", ReasonReplaced}, {"fired", ReasonExpired}}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %d events, got %+v", len(want), events)
+	}
+	for i, w := range want {
+		if events[i].Key != w.key || events[i].Reason != w.reason {
+			t.Errorf("Event %d: expected %s %v, got %s %v", i, w.key, w.reason, events[i].Key, events[i].Reason)
+		}
+	}
+	if late := events[2].At.Sub(events[2].Expiration); late < 0 || late > ManualInterval {
+		t.Errorf("Expected the firing within a tick of its deadline, got %v", late)
+	}
+
+	// The ring keeps only the newest events
+	if got := tw.History(1); len(got) != 1 || got[0].Key != "fired" {
+		t.Errorf("Expected the latest event, got %+v", got)
+	}
+	tw.Advance(2 * ManualInterval)
+	if got := tw.History(10); len(got) != 3 || got[0].Key != "replaced" || got[2].Key != "replaced" {
+		t.Errorf("Expected the oldest event to be overwritten, got %+v", got)
+	}
+}
+
+func TestHistoryAnnotations(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil, WithHistory(2))
+	defer tw.Stop()
+
+	tw.SetWith("a", nil, time.Hour, TaskAnnotations(map[string]string{"owner": "billing"}), TaskTags("invoice"))
+	tw.Delete("a")
+	events := tw.History(1)
+	if len(events) != 1 || events[0].Annotations["owner"] != "billing" || len(events[0].Tags) != 1 || events[0].Tags[0] != "invoice" {
+		t.Errorf("Expected the event to carry the task's annotations and tags, got %+v", events)
+	}
+}
+
+func TestHistoryDisabled(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil)
+	defer tw.Stop()
+
+	tw.Set("a", nil, time.Hour)
+	tw.Delete("a")
+	if events := tw.History(10); events != nil {
+		t.Errorf("Expected no history by default, got %+v", events)
+	}
+}
+
+func TestShardedHistory(t *testing.T) {
+	s := NewShardedTimeWheel(4, time.Second, 60, nil, WithHistory(10))
+	defer s.Stop()
+
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		s.Set(key, nil, time.Hour)
+		s.Delete(key)
+	}
+	events := s.History(3)
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %+v", events)
+	}
+	for i := 1; i < len(events); i++ {
+		if events[i].At.Before(events[i-1].At) {
+			t.Errorf("Expected events in time order, got %+v", events)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package timewheel
+
+// Hold keeps the task from firing until ReleaseHold is called. The deadline
+// keeps running; a held task that passes it waits to fire on release.
+func (tw *TimeWheel) Hold(key string) error {
+	tw.mu.Lock()
+	defer tw.unlock()
+
+	entry, exists := tw.keyMap[key]
+	if !exists {
+		return ErrNotFound
+	}
+	entry.held = true
+	return nil
+}
+
+// ReleaseHold lifts a hold, firing the task right away if its deadline
+// passed while it was held.
+func (tw *TimeWheel) ReleaseHold(key string) error {
+	tw.mu.Lock()
+	defer tw.unlock()
+
+	entry, exists := tw.keyMap[key]
+	if !exists {
+		return ErrNotFound
+	}
+	entry.held = false
+
+	if _, parked := tw.parked[key]; parked {
+		delete(tw.parked, key)
+		tw.untrack(entry)
+		tw.fireAsync(entry)
+	}
+	return nil
+}
+
+func (tw *TimeWheel) Held(key string) bool {
+	tw.mu.RLock()
+	defer tw.mu.RUnlock()
+
+	entry, exists := tw.keyMap[key]
+	return exists && entry.held
+}
+
+// park takes a due entry that is on hold out of the layers.
+func (tw *TimeWheel) park(entry *taskEntry) {
+	entry.layerIndex = -1
+	entry.bucketPos = 0
+	entry.rounds = 0
+	tw.parked[entry.key] = entry
+}
@@ -0,0 +1,69 @@
+package timewheel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHold(t *testing.T) {
+	fired := make(chan string, 1)
+	tw := NewTimeWheel(0, 10, func(k string, v any) {
+		fired <- k
+	})
+	defer tw.Stop()
+
+	if err := tw.Hold("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	tw.Set("test", "data", 2*ManualInterval)
+	if err := tw.Hold("test"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	tw.Advance(5 * ManualInterval)
+
+	select {
+	case <-fired:
+		t.Fatal("Held task should not fire")
+	case <-time.After(20 * time.Millisecond):
+	}
+	if !tw.Held("test") {
+		t.Error("Expected task to still be held")
+	}
+
+	if err := tw.ReleaseHold("test"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("Overdue task should fire on release")
+	}
+}
+
+func TestReleaseHoldBeforeDeadline(t *testing.T) {
+	fired := make(chan string, 1)
+	tw := NewTimeWheel(0, 10, func(k string, v any) {
+		fired <- k
+	})
+	defer tw.Stop()
+
+	tw.Set("test", "data", 5*ManualInterval)
+	tw.Hold("test")
+	tw.Advance(2 * ManualInterval)
+	tw.ReleaseHold("test")
+
+	select {
+	case <-fired:
+		t.Fatal("Task released before its deadline should not fire early")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	tw.Advance(3 * ManualInterval)
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("Task did not fire at its deadline")
+	}
+}
@@ -0,0 +1,136 @@
+package timewheel
+
+import "time"
+
+// TaskInfo describes a task passed to lifecycle hooks.
+type TaskInfo struct {
+	Key         string
+	Value       any
+	Expiration  time.Time
+	Annotations map[string]string
+	Tags        []string
+	// Attempt is the delivery the task is on, 1 until a callback fails.
+	Attempt int
+}
+
+type hookKind int
+
+const (
+	hookSchedule hookKind = iota
+	hookCancel
+	hookReschedule
+	hookFire
+	hookWarn
+	hookRemove
+)
+
+type hooks struct {
+	onSchedule   func(TaskInfo)
+	onCancel     func(TaskInfo)
+	onReschedule func(TaskInfo)
+	onFire       func(TaskInfo)
+	onRemove     func(key string, value any, reason Reason)
+	onEmpty      func()
+}
+
+type hookEvent struct {
+	kind   hookKind
+	info   TaskInfo
+	lead   time.Duration
+	reason Reason
+}
+
+// WithOnSchedule calls h whenever a new task is scheduled.
+func WithOnSchedule(h func(TaskInfo)) Option {
+	return func(tw *TimeWheel) {
+		tw.hooks.onSchedule = h
+	}
+}
+
+// WithOnCancel calls h whenever a pending task is removed without firing,
+// by Delete, a flush, or a Set that replaces it without rescheduling.
+func WithOnCancel(h func(TaskInfo)) Option {
+	return func(tw *TimeWheel) {
+		tw.hooks.onCancel = h
+	}
+}
+
+// WithOnReschedule calls h whenever a pending task gets a new deadline,
+// including a Set on an existing key.
+func WithOnReschedule(h func(TaskInfo)) Option {
+	return func(tw *TimeWheel) {
+		tw.hooks.onReschedule = h
+	}
+}
+
+// WithOnFire calls h whenever a task fires, before its callback runs.
+func WithOnFire(h func(TaskInfo)) Option {
+	return func(tw *TimeWheel) {
+		tw.hooks.onFire = h
+	}
+}
+
+func (h *hooks) get(kind hookKind) func(TaskInfo) {
+	switch kind {
+	case hookSchedule:
+		return h.onSchedule
+	case hookCancel:
+		return h.onCancel
+	case hookReschedule:
+		return h.onReschedule
+	default:
+		return h.onFire
+	}
+}
+
+func (entry *taskEntry) info() TaskInfo {
+	return TaskInfo{
+		Key:         entry.key,
+		Value:       entry.value,
+		Expiration:  entry.expiration,
+		Annotations: entry.annotations,
+		Tags:        entry.tags,
+		Attempt:     entry.retries + 1,
+	}
+}
+
+// record queues a hook call under the lock; unlock runs it once the lock is
+// released so hooks may call back into the wheel.
+func (tw *TimeWheel) record(kind hookKind, entry *taskEntry) {
+	tw.journal(kind, entry)
+	if kind == hookSchedule || kind == hookReschedule {
+		tw.planWarnings(entry)
+	}
+	if tw.hooks.get(kind) == nil {
+		return
+	}
+	tw.events = append(tw.events, hookEvent{kind: kind, info: entry.info()})
+}
+
+func (tw *TimeWheel) unlock() {
+	events := tw.events
+	tw.events = nil
+	empty := tw.takeEmpty()
+	tw.mu.Unlock()
+
+	for _, e := range events {
+		switch e.kind {
+		case hookWarn:
+			tw.onWarn(e.info, e.lead)
+			continue
+		case hookRemove:
+			tw.hooks.onRemove(e.info.Key, e.info.Value, e.reason)
+			continue
+		}
+		tw.hooks.get(e.kind)(e.info)
+	}
+	if empty {
+		tw.hooks.onEmpty()
+	}
+}
+
+func (tw *TimeWheel) fireHook(entry *taskEntry) {
+	if tw.hooks.onFire != nil {
+		tw.hooks.onFire(entry.info())
+	}
+}
@@ -0,0 +1,56 @@
+package timewheel
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLifecycleHooks(t *testing.T) {
+	var events []string
+	record := func(kind string) func(TaskInfo) {
+		return func(info TaskInfo) {
+			events = append(events, kind+":"+info.Key)
+		}
+	}
+	tw := NewTimeWheel(0, 10, nil,
+		WithOnSchedule(record("schedule")),
+		WithOnCancel(record("cancel")),
+		WithOnReschedule(record("reschedule")),
+		WithOnFire(record("fire")),
+	)
+	defer tw.Stop()
+
+	tw.Set("a", 1, 5*ManualInterval)
+	tw.Set("a", 2, 5*ManualInterval)
+	tw.Move("a", 2*ManualInterval)
+	tw.Set("b", 1, 5*ManualInterval)
+	tw.Delete("b")
+	tw.Advance(2 * ManualInterval)
+
+	expected := []string{
+		"schedule:a",
+		"reschedule:a",
+		"reschedule:a",
+		"schedule:b",
+		"cancel:b",
+		"fire:a",
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected %v, got %v", expected, events)
+	}
+}
+
+func TestHooksMayCallWheel(t *testing.T) {
+	var tw *TimeWheel
+	tw = NewTimeWheel(0, 10, nil, WithOnSchedule(func(info TaskInfo) {
+		if info.Key == "first" {
+			tw.Set("second", nil, ManualInterval)
+		}
+	}))
+	defer tw.Stop()
+
+	tw.Set("first", nil, ManualInterval)
+	if n := tw.Metrics()[MetricPendingTasks]; n != 2 {
+		t.Errorf("Expected hook to schedule a second task, got %v pending", n)
+	}
+}
@@ -0,0 +1,85 @@
+package timewheel
+
+import "time"
+
+// WithHybridTimers fires tasks whose expiration is shorter than one base
+// interval from a timer of their own instead of rounding them up to the next
+// tick, so short timers are accurate while long ones stay on the wheel. It
+// has no effect in manual mode or while the wheel is unstarted or gated.
+func WithHybridTimers() Option {
+	return func(tw *TimeWheel) {
+		tw.hybrid = true
+	}
+}
+
+// shortTimer is the timer behind a task scheduled outside the layers.
+type shortTimer struct {
+	stop func()
+}
+
+// timed reports whether a delay of d goes to a timer rather than a slot.
+func (tw *TimeWheel) timed(d time.Duration) bool {
+	return tw.hybrid && d > 0 && d < tw.baseInterval && !tw.manual && tw.started && !tw.gated
+}
+
+// arm schedules an entry on its own timer. The entry stays in keyMap but in
+// no bucket, like a parked one.
+func (tw *TimeWheel) arm(entry *taskEntry, d time.Duration) {
+	st := &shortTimer{}
+	entry.timer = st
+	entry.layerIndex = -1
+	entry.bucketPos = 0
+	entry.rounds = 0
+	st.stop = tw.afterFunc(d, func() { tw.timerFired(entry, st) })
+}
+
+// timerFired delivers a timed entry unless it was removed, replaced or moved
+// back onto the layers since the timer was armed. A timer going off while the
+// wheel is stopped leaves the entry to the first tick after a restart.
+func (tw *TimeWheel) timerFired(entry *taskEntry, st *shortTimer) {
+	tw.mu.Lock()
+	if entry.timer != st {
+		tw.unlock()
+		return
+	}
+	entry.timer = nil
+	if tw.stopped() {
+		base := tw.layers[0]
+		tw.place(entry, base, (base.currentPos+1)%base.slots, 0)
+		tw.unlock()
+		return
+	}
+	if tw.holding(entry) {
+		tw.park(entry)
+		tw.unlock()
+		return
+	}
+	tw.untrack(entry)
+	tw.board(entry)
+	expired := tw.applyBudget([]*taskEntry{entry})
+	tw.unlock()
+
+	tw.dispatch(expired)
+}
+
+// afterFunc runs f once after d on the wheel's clock and returns the
+// function that cancels it. Clocks other than the system one get a ticker
+// that is stopped after its first tick.
+func (tw *TimeWheel) afterFunc(d time.Duration, f func()) func() {
+	if _, system := tw.clock.(systemClock); system {
+		t := time.AfterFunc(d, f)
+		return func() { t.Stop() }
+	}
+
+	ticker := tw.clock.NewTicker(d)
+	stop := make(chan struct{})
+	tw.runtime.Go(func() {
+		defer ticker.Stop()
+		select {
+		case <-ticker.C():
+			f()
+		case <-stop:
+		}
+	})
+	return func() { close(stop) }
+}
@@ -0,0 +1,45 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHybridTimers(t *testing.T) {
+	fired := make(chan string, 4)
+	tw := NewTimeWheel(200*time.Millisecond, 10, func(k string, v any) {
+		fired <- k
+	}, WithHybridTimers())
+	defer tw.Stop()
+
+	start := time.Now()
+	tw.Set("short", "data", 10*time.Millisecond)
+	tw.Set("cancelled", "data", 10*time.Millisecond)
+	tw.Delete("cancelled")
+	tw.Set("moved", "data", 10*time.Millisecond)
+	tw.Move("moved", time.Hour)
+
+	select {
+	case k := <-fired:
+		if k != "short" {
+			t.Fatalf("Expected short to fire, got %s", k)
+		}
+		if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+			t.Errorf("Expected a sub-tick task to fire on its own timer, took %s", elapsed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Short task did not fire")
+	}
+
+	select {
+	case k := <-fired:
+		t.Errorf("Expected cancelled and moved tasks not to fire, got %s", k)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if _, _, ok := tw.Remaining("moved"); !ok {
+		t.Error("Expected the moved task to stay pending on the wheel")
+	}
+	if tw.Stats().Pending != 1 {
+		t.Errorf("Expected 1 pending task, got %d", tw.Stats().Pending)
+	}
+}
@@ -0,0 +1,90 @@
+package timewheel
+
+import (
+	"container/heap"
+	"slices"
+	"time"
+)
+
+// Inspection is a detailed snapshot of the wheel's layout, for diagnosing
+// tasks clustering into single slots and tuning slotsPerLayer.
+type Inspection struct {
+	// Cursor is the number of ticks stepped so far.
+	Cursor uint64
+	Layers []LayerInspection
+	// Parked counts overdue held or gated tasks, kept outside the layers;
+	// Timed counts sub-tick tasks on their own timers.
+	Parked int
+	Timed  int
+	// Upcoming lists the soonest pending tasks, earliest first.
+	Upcoming []TaskInfo
+}
+
+// LayerInspection describes one layer of the wheel.
+type LayerInspection struct {
+	Interval time.Duration
+	// Position is the slot the layer last processed.
+	Position int
+	// Slots holds the number of entries in every slot.
+	Slots   []int
+	Entries int
+	// Busiest is the slot holding the most entries.
+	Busiest int
+}
+
+// Inspect walks every slot of every layer under the read lock, so it is
+// meant for diagnostics rather than hot paths. upcoming bounds the number of
+// soonest tasks reported.
+func (tw *TimeWheel) Inspect(upcoming int) Inspection {
+	tw.mu.RLock()
+	defer tw.mu.RUnlock()
+
+	in := Inspection{Cursor: tw.cursor, Parked: len(tw.parked)}
+	for _, l := range tw.layers {
+		li := LayerInspection{Interval: l.interval, Position: l.currentPos, Slots: make([]int, l.slots)}
+		for i := range l.buckets {
+			n := l.buckets[i].len()
+			li.Slots[i] = n
+			li.Entries += n
+			if n > li.Slots[li.Busiest] {
+				li.Busiest = i
+			}
+		}
+		in.Layers = append(in.Layers, li)
+	}
+
+	var soonest latest
+	for _, entry := range tw.keyMap {
+		if entry.timer != nil {
+			in.Timed++
+		}
+		if upcoming <= 0 {
+			continue
+		}
+		if len(soonest) < upcoming {
+			heap.Push(&soonest, entry.info())
+		} else if entry.expiration.Before(soonest[0].Expiration) {
+			soonest[0] = entry.info()
+			heap.Fix(&soonest, 0)
+		}
+	}
+	slices.SortFunc(soonest, func(a, b TaskInfo) int {
+		return a.Expiration.Compare(b.Expiration)
+	})
+	in.Upcoming = soonest
+	return in
+}
+
+// latest is a max-heap by expiration, keeping the soonest tasks seen.
+type latest []TaskInfo
+
+func (l latest) Len() int           { return len(l) }
+func (l latest) Less(i, j int) bool { return l[i].Expiration.After(l[j].Expiration) }
+func (l latest) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
+func (l *latest) Push(x any)        { *l = append(*l, x.(TaskInfo)) }
+func (l *latest) Pop() any {
+	old := *l
+	x := old[len(old)-1]
+	*l = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,33 @@
+package timewheel
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestInspect(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil)
+	defer tw.Stop()
+
+	for i := 1; i <= 5; i++ {
+		tw.Set(fmt.Sprint(i), i, 3*ManualInterval)
+	}
+	tw.Set("later", "data", 250*ManualInterval)
+	tw.Set("soon", "data", 2*ManualInterval)
+	tw.Tick()
+
+	in := tw.Inspect(2)
+	if in.Cursor != 1 || len(in.Layers) != 3 {
+		t.Fatalf("Expected cursor 1 and 3 layers, got %d and %d", in.Cursor, len(in.Layers))
+	}
+	base := in.Layers[0]
+	if base.Position != 1 || base.Entries != 6 || base.Busiest != 3 || base.Slots[3] != 5 {
+		t.Errorf("Expected 5 of 6 base entries clustered in slot 3, got %+v", base)
+	}
+	if in.Layers[2].Entries != 1 {
+		t.Errorf("Expected the long task on the top layer, got %v", in.Layers[2].Slots)
+	}
+	if len(in.Upcoming) != 2 || in.Upcoming[0].Key != "soon" {
+		t.Errorf("Expected the two soonest tasks with soon first, got %v", in.Upcoming)
+	}
+}
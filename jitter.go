@@ -0,0 +1,39 @@
+package timewheel
+
+import (
+	"math"
+	"time"
+)
+
+// WithJitter delays every positive expiration by a random amount in
+// [0, max), spreading out tasks set with the same TTL so they do not fire
+// as a thundering herd.
+func WithJitter(max time.Duration) Option {
+	return func(tw *TimeWheel) {
+		tw.jitter = max
+	}
+}
+
+// TaskJitter overrides the wheel's jitter for one task; zero disables it. A
+// cron task applies it to every occurrence.
+func TaskJitter(max time.Duration) SetOption {
+	return func(so *setOptions) {
+		so.jitter = max
+	}
+}
+
+func (tw *TimeWheel) jitterFor(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(tw.runtime.Int64N(int64(max)))
+}
+
+// addJitter extends a positive expiration by jitter, saturating rather than
+// wrapping round to a deadline in the past.
+func addJitter(expiration, jitter time.Duration) time.Duration {
+	if expiration > time.Duration(math.MaxInt64)-jitter {
+		return time.Duration(math.MaxInt64)
+	}
+	return expiration + jitter
+}
@@ -0,0 +1,31 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitter(t *testing.T) {
+	tw := NewTimeWheel(0, 100, nil, WithJitter(50*ManualInterval))
+	defer tw.Stop()
+
+	start := tw.now()
+	deadlines := make(map[time.Time]bool)
+	for i := 0; i < 20; i++ {
+		key := string(rune('a' + i))
+		tw.Set(key, "data", 10*ManualInterval)
+		exp := tw.keyMap[key].expiration
+		if d := exp.Sub(start); d < 10*ManualInterval || d >= 60*ManualInterval {
+			t.Fatalf("Expected jittered delay in [10ms, 60ms), got %s", d)
+		}
+		deadlines[exp] = true
+	}
+	if len(deadlines) < 2 {
+		t.Error("Expected jitter to spread deadlines")
+	}
+
+	tw.SetWith("exact", "data", 10*ManualInterval, TaskJitter(0))
+	if d := tw.keyMap["exact"].expiration.Sub(start); d != 10*ManualInterval {
+		t.Errorf("Expected TaskJitter(0) to disable jitter, got %s", d)
+	}
+}
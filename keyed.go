@@ -0,0 +1,204 @@
+package timewheel
+
+import (
+	"sync"
+	"time"
+)
+
+// KeyedWheel is a lean wheel keyed by any comparable type, for hot paths
+// keyed by integers such as connection IDs, where converting keys to strings
+// would cost more than the timer itself. It offers only Set, Delete and
+// Move: none of TimeWheel's options, hooks, persistence or observability.
+//
+// It is a single level of slots; a deadline beyond one revolution waits in
+// its slot for as many revolutions as it needs. Callbacks run one after
+// another on the tick goroutine, outside the lock, so keep them quick.
+type KeyedWheel[K comparable, V any] struct {
+	mu       sync.Mutex
+	interval time.Duration
+	slots    []*keyedEntry[K, V]
+	keys     map[K]*keyedEntry[K, V]
+	cursor   uint64
+	callback func(key K, value V)
+
+	manual  bool
+	elapsed time.Duration
+	ticker  *time.Ticker
+	quit    chan struct{}
+	once    sync.Once
+}
+
+type keyedEntry[K comparable, V any] struct {
+	key        K
+	value      V
+	deadline   uint64
+	prev, next *keyedEntry[K, V]
+}
+
+// NewKeyedWheel starts a wheel ticking every baseInterval over slots slots.
+// A non-positive baseInterval selects manual mode, driven by Advance. A nil
+// callback lets tasks expire silently.
+func NewKeyedWheel[K comparable, V any](baseInterval time.Duration, slots int, callback func(key K, value V)) *KeyedWheel[K, V] {
+	w := &KeyedWheel[K, V]{
+		interval: baseInterval,
+		slots:    make([]*keyedEntry[K, V], max(slots, 1)),
+		keys:     make(map[K]*keyedEntry[K, V]),
+		callback: callback,
+		quit:     make(chan struct{}),
+	}
+	if baseInterval <= 0 {
+		w.interval = ManualInterval
+		w.manual = true
+		return w
+	}
+	w.ticker = time.NewTicker(baseInterval)
+	go w.loop()
+	return w
+}
+
+func (w *KeyedWheel[K, V]) loop() {
+	for {
+		select {
+		case <-w.ticker.C:
+			w.tick()
+		case <-w.quit:
+			return
+		}
+	}
+}
+
+// Set schedules value under key to fire after d, at the earliest on the
+// next tick, and reports whether it replaced a pending task.
+func (w *KeyedWheel[K, V]) Set(key K, value V, d time.Duration) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	entry, replaced := w.keys[key]
+	if replaced {
+		w.unlink(entry)
+	} else {
+		entry = &keyedEntry[K, V]{key: key}
+		w.keys[key] = entry
+	}
+	entry.value = value
+	w.place(entry, d)
+	return replaced
+}
+
+// Delete cancels the task under key and reports whether it was pending.
+func (w *KeyedWheel[K, V]) Delete(key K) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	entry, ok := w.keys[key]
+	if !ok {
+		return false
+	}
+	w.unlink(entry)
+	delete(w.keys, key)
+	return true
+}
+
+// Move reschedules the task under key to fire after d and reports whether
+// it was pending.
+func (w *KeyedWheel[K, V]) Move(key K, d time.Duration) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	entry, ok := w.keys[key]
+	if !ok {
+		return false
+	}
+	w.unlink(entry)
+	w.place(entry, d)
+	return true
+}
+
+// Len returns how many tasks are pending.
+func (w *KeyedWheel[K, V]) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.keys)
+}
+
+// Advance moves a manual wheel's clock forward by d, running every tick
+// that becomes due on the way. It is a no-op on a ticker-driven wheel.
+func (w *KeyedWheel[K, V]) Advance(d time.Duration) {
+	if !w.manual || d <= 0 {
+		return
+	}
+	w.mu.Lock()
+	w.elapsed += d
+	ticks := w.elapsed / w.interval
+	w.elapsed -= ticks * w.interval
+	w.mu.Unlock()
+
+	for ; ticks > 0; ticks-- {
+		w.tick()
+	}
+}
+
+// Stop halts the wheel. Pending tasks never fire.
+func (w *KeyedWheel[K, V]) Stop() {
+	w.once.Do(func() {
+		if w.ticker != nil {
+			w.ticker.Stop()
+		}
+		close(w.quit)
+	})
+}
+
+// tick advances the cursor and fires the tasks due in its slot.
+func (w *KeyedWheel[K, V]) tick() {
+	w.mu.Lock()
+	w.cursor++
+	pos := w.cursor % uint64(len(w.slots))
+	var due []*keyedEntry[K, V]
+	var next *keyedEntry[K, V]
+	for entry := w.slots[pos]; entry != nil; entry = next {
+		next = entry.next
+		if entry.deadline > w.cursor {
+			continue
+		}
+		w.unlink(entry)
+		delete(w.keys, entry.key)
+		due = append(due, entry)
+	}
+	w.mu.Unlock()
+
+	if w.callback == nil {
+		return
+	}
+	for _, entry := range due {
+		w.callback(entry.key, entry.value)
+	}
+}
+
+// place links entry into the slot of the tick d from now.
+func (w *KeyedWheel[K, V]) place(entry *keyedEntry[K, V], d time.Duration) {
+	ticks := uint64(1)
+	if d > w.interval {
+		ticks = uint64((d + w.interval - 1) / w.interval)
+	}
+	entry.deadline = w.cursor + ticks
+	pos := entry.deadline % uint64(len(w.slots))
+
+	entry.prev = nil
+	entry.next = w.slots[pos]
+	if entry.next != nil {
+		entry.next.prev = entry
+	}
+	w.slots[pos] = entry
+}
+
+func (w *KeyedWheel[K, V]) unlink(entry *keyedEntry[K, V]) {
+	if entry.prev != nil {
+		entry.prev.next = entry.next
+	} else {
+		w.slots[entry.deadline%uint64(len(w.slots))] = entry.next
+	}
+	if entry.next != nil {
+		entry.next.prev = entry.prev
+	}
+	entry.prev, entry.next = nil, nil
+}
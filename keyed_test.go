@@ -0,0 +1,90 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeyedWheel(t *testing.T) {
+	var fired []uint64
+	w := NewKeyedWheel(0, 8, func(id uint64, conn string) {
+		fired = append(fired, id)
+	})
+	defer w.Stop()
+
+	w.Set(1, "a", 2*ManualInterval)
+	w.Set(2, "b", 2*ManualInterval)
+	w.Set(3, "c", 20*ManualInterval) // beyond one revolution
+	if !w.Delete(2) || w.Delete(2) {
+		t.Error("Expected Delete to report only the first deletion")
+	}
+	if !w.Set(1, "a2", 3*ManualInterval) {
+		t.Error("Expected Set to report replacing a pending task")
+	}
+	if w.Len() != 2 {
+		t.Errorf("Expected 2 pending tasks, got %d", w.Len())
+	}
+
+	w.Advance(2 * ManualInterval)
+	if len(fired) != 0 {
+		t.Fatalf("Expected the replaced deadline to hold, got %v", fired)
+	}
+	w.Advance(ManualInterval)
+	if len(fired) != 1 || fired[0] != 1 {
+		t.Fatalf("Expected 1 to fire, got %v", fired)
+	}
+
+	if !w.Move(3, ManualInterval) || w.Move(1, time.Hour) {
+		t.Error("Expected Move to reschedule only pending tasks")
+	}
+	w.Advance(ManualInterval)
+	if len(fired) != 2 || fired[1] != 3 {
+		t.Errorf("Expected 3 to fire at its moved deadline, got %v", fired)
+	}
+	if w.Len() != 0 {
+		t.Errorf("Expected no pending tasks, got %d", w.Len())
+	}
+}
+
+func TestKeyedWheelRevolutions(t *testing.T) {
+	fired := 0
+	w := NewKeyedWheel(0, 4, func(int, struct{}) { fired++ })
+	defer w.Stop()
+
+	w.Set(7, struct{}{}, 10*ManualInterval)
+	w.Advance(9 * ManualInterval)
+	if fired != 0 {
+		t.Fatal("Expected the task to wait out its revolutions")
+	}
+	w.Advance(ManualInterval)
+	if fired != 1 {
+		t.Errorf("Expected the task to fire after 10 ticks, fired %d", fired)
+	}
+}
+
+func TestKeyedWheelNilCallback(t *testing.T) {
+	w := NewKeyedWheel[int, string](0, 4, nil)
+	defer w.Stop()
+
+	w.Set(1, "data", ManualInterval)
+	w.Advance(ManualInterval)
+	if w.Move(1, ManualInterval) {
+		t.Error("Expected the task to expire without a callback")
+	}
+}
+
+func TestKeyedWheelTicker(t *testing.T) {
+	done := make(chan int64, 1)
+	w := NewKeyedWheel(5*time.Millisecond, 16, func(id int64, _ any) { done <- id })
+	defer w.Stop()
+
+	w.Set(42, nil, 10*time.Millisecond)
+	select {
+	case id := <-done:
+		if id != 42 {
+			t.Errorf("Expected 42, got %d", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Task did not fire")
+	}
+}
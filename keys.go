@@ -0,0 +1,175 @@
+package timewheel
+
+import (
+	"strings"
+	"time"
+)
+
+// KeySeparator joins the parts of a composite key into the key string that
+// callbacks, hooks and the other APIs see.
+const KeySeparator = "\x1f"
+
+// Key is a composite key made of structured parts, e.g. tenant, kind and id.
+type Key []string
+
+func K(parts ...string) Key {
+	return Key(parts)
+}
+
+// String returns the key as stored in the wheel.
+func (k Key) String() string {
+	return strings.Join(k, KeySeparator)
+}
+
+func (k Key) valid() bool {
+	if len(k) == 0 {
+		return false
+	}
+	for _, part := range k {
+		if part == "" || strings.Contains(part, KeySeparator) {
+			return false
+		}
+	}
+	return true
+}
+
+// SplitKey returns the parts of a key set by SetK.
+func SplitKey(key string) Key {
+	return Key(strings.Split(key, KeySeparator))
+}
+
+// SetK schedules a task under a composite key. Parts must be non-empty and
+// free of KeySeparator, or SetK returns ErrInvalidKey.
+func (tw *TimeWheel) SetK(key Key, value any, expiration time.Duration, opts ...SetOption) error {
+	if !key.valid() {
+		return ErrInvalidKey
+	}
+	return tw.SetWith(key.String(), value, expiration, append(opts, taskParts(key))...)
+}
+
+func taskParts(key Key) SetOption {
+	return func(so *setOptions) {
+		so.parts = append(Key(nil), key...)
+	}
+}
+
+// DeleteK removes every composite-key task whose key starts with the given
+// parts, without firing it, and returns how many were removed. An index of
+// the parts makes this proportional to the tasks removed.
+func (tw *TimeWheel) DeleteK(prefix ...string) int {
+	tw.mu.Lock()
+	defer tw.unlock()
+
+	node := tw.keyIndex.find(prefix)
+	if node == nil {
+		return 0
+	}
+	var doomed []*taskEntry
+	node.walk(func(entry *taskEntry) {
+		doomed = append(doomed, entry)
+	})
+	for _, entry := range doomed {
+		tw.untrack(entry)
+		tw.unlink(entry)
+		tw.counters.deleted.Add(1)
+		tw.record(hookCancel, entry)
+		tw.removed(entry, ReasonDeleted)
+		releaseEntry(entry)
+	}
+	return len(doomed)
+}
+
+// CountK returns how many composite-key tasks start with the given parts.
+func (tw *TimeWheel) CountK(prefix ...string) int {
+	tw.mu.RLock()
+	defer tw.mu.RUnlock()
+
+	if node := tw.keyIndex.find(prefix); node != nil {
+		return node.n
+	}
+	return 0
+}
+
+// keyNode is a trie over composite key parts; n counts the tasks below.
+type keyNode struct {
+	children map[string]*keyNode
+	entry    *taskEntry
+	n        int
+}
+
+func (nd *keyNode) find(parts []string) *keyNode {
+	for _, part := range parts {
+		nd = nd.children[part]
+		if nd == nil {
+			return nil
+		}
+	}
+	return nd
+}
+
+func (nd *keyNode) add(parts []string, entry *taskEntry) {
+	for _, part := range parts {
+		nd.n++
+		child := nd.children[part]
+		if child == nil {
+			if nd.children == nil {
+				nd.children = make(map[string]*keyNode)
+			}
+			child = &keyNode{}
+			nd.children[part] = child
+		}
+		nd = child
+	}
+	nd.n++
+	nd.entry = entry
+}
+
+func (nd *keyNode) remove(parts []string) {
+	if len(parts) == 0 {
+		nd.n--
+		nd.entry = nil
+		return
+	}
+	child := nd.children[parts[0]]
+	if child == nil {
+		return
+	}
+	nd.n--
+	child.remove(parts[1:])
+	if child.n == 0 {
+		delete(nd.children, parts[0])
+	}
+}
+
+func (nd *keyNode) walk(fn func(*taskEntry)) {
+	if nd.entry != nil {
+		fn(nd.entry)
+	}
+	for _, child := range nd.children {
+		child.walk(fn)
+	}
+}
+
+// track adds entry to keyMap and the composite key index.
+func (tw *TimeWheel) track(entry *taskEntry) {
+	tw.rouse()
+	tw.keyMap[entry.key] = entry
+	if entry.parts != nil {
+		tw.keyIndex.add(entry.parts, entry)
+	}
+	tw.indexMany(entry)
+	tw.indexTags(entry)
+}
+
+func (tw *TimeWheel) untrack(entry *taskEntry) {
+	delete(tw.keyMap, entry.key)
+	if entry.parts != nil {
+		tw.keyIndex.remove(entry.parts)
+	}
+	tw.unindexMany(entry)
+	tw.unindexTags(entry)
+	// A fired cron entry comes back once it is rescheduled
+	if entry.cron == nil {
+		tw.noteEmpty()
+	}
+}
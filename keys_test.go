@@ -0,0 +1,51 @@
+package timewheel
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompositeKeys(t *testing.T) {
+	fired := make(chan string, 4)
+	tw := NewTimeWheel(0, 10, func(k string, v any) {
+		fired <- k
+	}, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	tw.SetK(K("tenant1", "session", "a"), "data", 5*ManualInterval)
+	tw.SetK(K("tenant1", "session", "b"), "data", 5*ManualInterval)
+	tw.SetK(K("tenant1", "job", "c"), "data", 5*ManualInterval)
+	tw.SetK(K("tenant2", "session", "a"), "data", 5*ManualInterval)
+	if err := tw.SetK(K("tenant1", ""), "data", ManualInterval); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Expected ErrInvalidKey for an empty part, got %v", err)
+	}
+
+	if n := tw.CountK("tenant1"); n != 3 {
+		t.Errorf("Expected 3 tasks under tenant1, got %d", n)
+	}
+	if n := tw.DeleteK("tenant1", "session"); n != 2 {
+		t.Errorf("Expected 2 tasks deleted, got %d", n)
+	}
+	if n := tw.DeleteK("tenant3"); n != 0 {
+		t.Errorf("Expected nothing deleted for an unknown prefix, got %d", n)
+	}
+
+	// A plain Set on the same key string replaces the composite task
+	tw.Set(K("tenant2", "session", "a").String(), "plain", 5*ManualInterval)
+	if n := tw.CountK("tenant2"); n != 0 {
+		t.Errorf("Expected the replaced task to leave the index, got %d", n)
+	}
+
+	tw.Advance(5 * ManualInterval)
+	got := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		got[<-fired] = true
+	}
+	job := K("tenant1", "job", "c").String()
+	if !got[job] || SplitKey(job)[1] != "job" {
+		t.Errorf("Expected %q to fire and split back into parts, got %v", job, got)
+	}
+	if n := tw.CountK(); n != 0 {
+		t.Errorf("Expected fired tasks to leave the index, got %d", n)
+	}
+}
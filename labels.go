@@ -0,0 +1,26 @@
+package timewheel
+
+import "runtime/pprof"
+
+// Profiler label keys set by WithProfilerLabels by default.
+const (
+	keyLabel   = "timewheel_key"
+	batchLabel = "timewheel_batch"
+)
+
+// WithProfilerLabels runs every callback under pprof.Do with the labels
+// returned for its task, so goroutine dumps and CPU profiles attribute work
+// to specific timers; the context callback receives the labelled context. A
+// nil labels function labels the task's key as timewheel_key. Batch callbacks
+// are labelled with their size as timewheel_batch. Labelling allocates per
+// callback, which is why it is opt-in.
+func WithProfilerLabels(labels func(TaskInfo) pprof.LabelSet) Option {
+	return func(tw *TimeWheel) {
+		if labels == nil {
+			labels = func(info TaskInfo) pprof.LabelSet {
+				return pprof.Labels(keyLabel, info.Key)
+			}
+		}
+		tw.labels = labels
+	}
+}
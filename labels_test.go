@@ -0,0 +1,35 @@
+package timewheel
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+)
+
+func TestProfilerLabels(t *testing.T) {
+	got := make(chan string, 1)
+	labelOf := func(label string) func(ctx context.Context, key string, value any) {
+		return func(ctx context.Context, key string, value any) {
+			v, _ := pprof.Label(ctx, label)
+			got <- v
+		}
+	}
+
+	tw := NewTimeWheel(0, 10, nil, WithSyncCallbacks(0), WithProfilerLabels(nil), WithContextCallback(labelOf(keyLabel)))
+	defer tw.Stop()
+	tw.Set("job-1", "data", ManualInterval)
+	tw.Tick()
+	if label := <-got; label != "job-1" {
+		t.Errorf("Expected the callback labelled with its key, got %q", label)
+	}
+
+	custom := NewTimeWheel(0, 10, nil, WithSyncCallbacks(0), WithProfilerLabels(func(info TaskInfo) pprof.LabelSet {
+		return pprof.Labels("tenant", info.Annotations["tenant"])
+	}), WithContextCallback(labelOf("tenant")))
+	defer custom.Stop()
+	custom.SetWith("job-2", "data", ManualInterval, TaskAnnotations(map[string]string{"tenant": "acme"}))
+	custom.Tick()
+	if label := <-got; label != "acme" {
+		t.Errorf("Expected the custom label, got %q", label)
+	}
+}
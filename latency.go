@@ -0,0 +1,111 @@
+package timewheel
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Histogram is a snapshot of a latency distribution. Buckets[i] counts
+// observations up to Bounds[i]; the final bucket, with no bound, counts the
+// rest.
+type Histogram struct {
+	Count   uint64
+	Sum     time.Duration
+	Bounds  []time.Duration
+	Buckets []uint64
+}
+
+// OpLatency splits a mutation's latency into the wait for the wheel lock and
+// the total, wait included.
+type OpLatency struct {
+	LockWait Histogram
+	Total    Histogram
+}
+
+// Bucket bounds double from 64ns to about 1s.
+const (
+	minLatencyShift = 6
+	latencyBounds   = 25
+)
+
+type histogram struct {
+	// shift scales the bounds up by a power of two.
+	shift   int
+	count   atomic.Uint64
+	sum     atomic.Int64
+	buckets [latencyBounds + 1]atomic.Uint64
+}
+
+func (h *histogram) observe(d time.Duration) {
+	i := 0
+	for i < latencyBounds && d > time.Duration(1)<<(minLatencyShift+h.shift+i) {
+		i++
+	}
+	h.buckets[i].Add(1)
+	h.count.Add(1)
+	h.sum.Add(int64(d))
+}
+
+func (h *histogram) snapshot() Histogram {
+	s := Histogram{
+		Count:   h.count.Load(),
+		Sum:     time.Duration(h.sum.Load()),
+		Bounds:  make([]time.Duration, latencyBounds),
+		Buckets: make([]uint64, latencyBounds+1),
+	}
+	for i := range s.Bounds {
+		s.Bounds[i] = time.Duration(1) << (minLatencyShift + h.shift + i)
+	}
+	for i := range s.Buckets {
+		s.Buckets[i] = h.buckets[i].Load()
+	}
+	return s
+}
+
+// Quantile returns the upper bound of the bucket holding the q-th quantile,
+// or the largest bound when it falls in the unbounded bucket.
+func (h Histogram) Quantile(q float64) time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	rank := uint64(q * float64(h.Count))
+	var seen uint64
+	for i, n := range h.Buckets {
+		seen += n
+		if seen > rank && i < len(h.Bounds) {
+			return h.Bounds[i]
+		}
+	}
+	return h.Bounds[len(h.Bounds)-1]
+}
+
+func (h Histogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Count)
+}
+
+type opLatency struct {
+	wait, total histogram
+}
+
+type latencies struct {
+	set, delete, move opLatency
+}
+
+func (l *opLatency) snapshot() OpLatency {
+	return OpLatency{LockWait: l.wait.snapshot(), Total: l.total.snapshot()}
+}
+
+// lockFor takes the wheel lock on behalf of a mutation and returns the
+// function that records its latency; call it before unlocking so hooks run
+// afterwards are not counted.
+func (tw *TimeWheel) lockFor(l *opLatency) (done func()) {
+	start := tw.clock.Now()
+	tw.mu.Lock()
+	l.wait.observe(tw.clock.Now().Sub(start))
+	return func() {
+		l.total.observe(tw.clock.Now().Sub(start))
+	}
+}
@@ -0,0 +1,52 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOpLatency(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil)
+	defer tw.Stop()
+
+	tw.Set("a", "data", 5*ManualInterval)
+	tw.SetNX("b", "data", 5*ManualInterval)
+	tw.Move("a", 8*ManualInterval)
+	tw.Extend("a", ManualInterval)
+	tw.Delete("b")
+
+	s := tw.Stats()
+	for name, c := range map[string]struct {
+		op   OpLatency
+		want uint64
+	}{"set": {s.SetLatency, 2}, "move": {s.MoveLatency, 2}, "delete": {s.DeleteLatency, 1}} {
+		if c.op.Total.Count != c.want || c.op.LockWait.Count != c.want {
+			t.Errorf("Expected %d %s observations, got %d total and %d lock waits", c.want, name, c.op.Total.Count, c.op.LockWait.Count)
+		}
+		if c.op.Total.Sum < c.op.LockWait.Sum {
+			t.Errorf("Expected %s total latency to include the lock wait", name)
+		}
+	}
+	if m := tw.Metrics(); m[MetricSetCalls] != 2 {
+		t.Errorf("Expected %s = 2, got %v", MetricSetCalls, m[MetricSetCalls])
+	}
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	var h histogram
+	for i := 0; i < 99; i++ {
+		h.observe(100 * time.Nanosecond)
+	}
+	h.observe(time.Millisecond)
+
+	s := h.snapshot()
+	if got := s.Quantile(0.5); got != 128*time.Nanosecond {
+		t.Errorf("Expected p50 in the 128ns bucket, got %s", got)
+	}
+	if got := s.Quantile(0.999); got != 1<<20*time.Nanosecond {
+		t.Errorf("Expected p99.9 in the ~1ms bucket, got %s", got)
+	}
+	if got := s.Mean(); got != (99*100*time.Nanosecond+time.Millisecond)/100 {
+		t.Errorf("Unexpected mean %s", got)
+	}
+}
@@ -0,0 +1,36 @@
+package timewheel
+
+import (
+	"context"
+	"time"
+)
+
+// Lateness buckets double from 64µs to about 18 minutes.
+const latenessShift = 10
+
+type fireTimesKey struct{}
+
+type fireTimes struct {
+	scheduled, fired time.Time
+}
+
+// FireTimes returns, inside a context callback, the deadline the task was
+// scheduled for and when the wheel actually fired it, so a consumer can
+// compensate for tick granularity. ok is false outside a callback.
+func FireTimes(ctx context.Context) (scheduled, fired time.Time, ok bool) {
+	ft, ok := ctx.Value(fireTimesKey{}).(fireTimes)
+	return ft.scheduled, ft.fired, ok
+}
+
+func withFireTimes(ctx context.Context, entry *taskEntry) context.Context {
+	return context.WithValue(ctx, fireTimesKey{}, fireTimes{entry.expiration, entry.firedAt})
+}
+
+// countFired stamps an entry with the time it fires, records how late that
+// is against its deadline and adds the firing to the history.
+func (tw *TimeWheel) countFired(entry *taskEntry) {
+	tw.counters.fired.Add(1)
+	entry.firedAt = tw.now()
+	tw.lateness.observe(clampDuration(entry.firedAt.Sub(entry.expiration)))
+	tw.remember(entry, ReasonExpired, entry.firedAt)
+}
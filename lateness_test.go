@@ -0,0 +1,46 @@
+package timewheel
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLateness(t *testing.T) {
+	late := make(chan time.Duration, 1)
+	tw := NewTimeWheel(time.Second, 10, nil, WithManualMode(), WithSyncCallbacks(0),
+		WithContextCallback(func(ctx context.Context, key string, _ any) {
+			scheduled, fired, ok := FireTimes(ctx)
+			if !ok {
+				t.Error("Expected the callback to see its fire times")
+			}
+			late <- fired.Sub(scheduled)
+		}))
+	defer tw.Stop()
+
+	tw.Set("ontime", nil, 2*time.Second)
+	tw.Advance(2 * time.Second)
+	if d := <-late; d != 0 {
+		t.Errorf("Expected an on-time firing, got %v late", d)
+	}
+
+	// A held task fires on release, well after its deadline
+	tw.Set("held", nil, time.Second)
+	tw.Hold("held")
+	tw.Advance(4 * time.Second)
+	tw.ReleaseHold("held")
+	if d := <-late; d != 3*time.Second {
+		t.Errorf("Expected the held task to fire 3s late, got %v", d)
+	}
+
+	h := tw.Stats().Lateness
+	if h.Count != 2 || h.Sum != 3*time.Second {
+		t.Errorf("Expected 2 firings totalling 3s late, got %d totalling %v", h.Count, h.Sum)
+	}
+	if q := h.Quantile(0.99); q < 3*time.Second || q > 5*time.Second {
+		t.Errorf("Expected p99 lateness in the 3s bucket, got %v", q)
+	}
+	if _, _, ok := FireTimes(context.Background()); ok {
+		t.Error("Expected no fire times outside a callback")
+	}
+}
@@ -0,0 +1,21 @@
+package timewheel
+
+// Loader produces a task's value as the task fires. Set one as the value of
+// a long-lived task to keep a large payload out of the wheel until then,
+// for example by reloading it from a store by ID. The callback, channel and
+// listeners receive what it returns, or ErrValueGone if it reports the
+// value missing. A Loader cannot be written to a WAL.
+type Loader func() (value any, ok bool)
+
+// load replaces a Loader value with what it produces, outside the lock.
+func (tw *TimeWheel) load(entry *taskEntry) {
+	loader, ok := entry.value.(Loader)
+	if !ok {
+		return
+	}
+	if value, ok := loader(); ok {
+		entry.value = value
+	} else {
+		entry.value = ErrValueGone
+	}
+}
@@ -0,0 +1,55 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoader(t *testing.T) {
+	got := make(map[string]any)
+	tw := NewTimeWheel(0, 10, func(key string, value any) {
+		got[key] = value
+	}, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	store := map[int]string{1: "payload"}
+	lookup := func(id int) Loader {
+		return func() (any, bool) {
+			v, ok := store[id]
+			return v, ok
+		}
+	}
+	tw.Set("present", lookup(1), 2*ManualInterval)
+	tw.Set("missing", lookup(2), 2*ManualInterval)
+	tw.Set("plain", "value", 2*ManualInterval)
+	store[1] = "reloaded"
+
+	tw.Advance(2 * ManualInterval)
+	if got["present"] != "reloaded" {
+		t.Errorf("Expected the value loaded at fire time, got %v", got["present"])
+	}
+	if got["missing"] != ErrValueGone {
+		t.Errorf("Expected ErrValueGone for a missing value, got %v", got["missing"])
+	}
+	if got["plain"] != "value" {
+		t.Errorf("Expected a plain value untouched, got %v", got["plain"])
+	}
+}
+
+func TestLoaderAsync(t *testing.T) {
+	tw := NewTimeWheel(10*time.Millisecond, 10, nil, WithExpiredChannel(1, OverflowBlock))
+	defer tw.Stop()
+
+	// Zero-TTL tasks fire from under the lock; the loader may call back in
+	tw.Set("now", Loader(func() (any, bool) {
+		return tw.Stats().Pending, true
+	}), 0)
+	select {
+	case task := <-tw.Expired():
+		if task.Value != 0 {
+			t.Errorf("Expected the loaded value, got %v", task.Value)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Task did not fire")
+	}
+}
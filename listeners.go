@@ -0,0 +1,90 @@
+package timewheel
+
+import (
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// ListenerID identifies a listener added with AddListener.
+type ListenerID uint64
+
+type listener struct {
+	id ListenerID
+	fn func(task ExpiredTask)
+}
+
+// listeners is a copy-on-write list, read without locking on every firing.
+type listeners struct {
+	mu   sync.Mutex
+	list atomic.Pointer[[]listener]
+	next ListenerID
+}
+
+// AddListener registers fn to observe every expiration, independently of the
+// callback and of other listeners, and returns the ID for RemoveListener.
+// Listeners run one after another on the goroutine that fires the task,
+// usually the tick goroutine, before the callback; they must not block. A
+// panicking listener is logged and counted in Stats().Panics.
+func (tw *TimeWheel) AddListener(fn func(task ExpiredTask)) ListenerID {
+	ls := &tw.listeners
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	ls.next++
+	var list []listener
+	if cur := ls.list.Load(); cur != nil {
+		list = slices.Clone(*cur)
+	}
+	list = append(list, listener{id: ls.next, fn: fn})
+	ls.list.Store(&list)
+	return ls.next
+}
+
+// RemoveListener unregisters a listener and reports whether it was
+// registered. A firing already under way may still reach it.
+func (tw *TimeWheel) RemoveListener(id ListenerID) bool {
+	ls := &tw.listeners
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	cur := ls.list.Load()
+	if cur == nil {
+		return false
+	}
+	i := slices.IndexFunc(*cur, func(l listener) bool { return l.id == id })
+	if i < 0 {
+		return false
+	}
+	list := slices.Delete(slices.Clone(*cur), i, i+1)
+	ls.list.Store(&list)
+	return true
+}
+
+func (tw *TimeWheel) listening() bool {
+	list := tw.listeners.list.Load()
+	return list != nil && len(*list) > 0
+}
+
+// notify hands a firing to every listener.
+func (tw *TimeWheel) notify(entry *taskEntry) {
+	list := tw.listeners.list.Load()
+	if list == nil || len(*list) == 0 {
+		return
+	}
+	task := entry.expiredTask()
+	for _, l := range *list {
+		tw.notifyOne(l, task)
+	}
+}
+
+func (tw *TimeWheel) notifyOne(l listener, task ExpiredTask) {
+	defer func() {
+		if r := recover(); r != nil {
+			tw.counters.panics.Add(1)
+			tw.log(slog.LevelError, "timewheel: listener panicked", "key", task.Key, "listener", l.id, "panic", r)
+		}
+	}()
+	l.fn(task)
+}
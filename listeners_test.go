@@ -0,0 +1,58 @@
+package timewheel
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestListeners(t *testing.T) {
+	var got []string
+	tw := NewTimeWheel(0, 10, func(k string, _ any) {
+		got = append(got, "callback:"+k)
+	}, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	audit := tw.AddListener(func(task ExpiredTask) {
+		got = append(got, "audit:"+task.Key)
+	})
+	tw.AddListener(func(task ExpiredTask) {
+		panic("broken listener")
+	})
+	tw.AddListener(func(task ExpiredTask) {
+		got = append(got, "metrics:"+task.Key)
+	})
+
+	tw.Set("a", nil, ManualInterval)
+	tw.Tick()
+	if expected := []string{"audit:a", "metrics:a", "callback:a"}; !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if n := tw.Stats().Panics; n != 1 {
+		t.Errorf("Expected the panicking listener to be counted, got %d", n)
+	}
+
+	if !tw.RemoveListener(audit) {
+		t.Fatal("Expected RemoveListener to find the listener")
+	}
+	if tw.RemoveListener(audit) {
+		t.Error("Expected a second RemoveListener to report false")
+	}
+	got = nil
+	tw.Set("b", nil, ManualInterval)
+	tw.Tick()
+	if expected := []string{"metrics:b", "callback:b"}; !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestListenersWithoutCallback(t *testing.T) {
+	fired := make(chan string, 1)
+	tw := NewTimeWheel(0, 10, nil)
+	defer tw.Stop()
+	tw.AddListener(func(task ExpiredTask) { fired <- task.Key })
+
+	tw.Set("now", nil, 0)
+	if got := <-fired; got != "now" {
+		t.Errorf("Expected the listener to see a zero-TTL task, got %s", got)
+	}
+}
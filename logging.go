@@ -0,0 +1,40 @@
+package timewheel
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WithLogger reports internal events that are otherwise only counted:
+// ticks running late, catch-ups, tasks dropped by a full Expired channel,
+// panicking, failing or timed-out callbacks, WAL write failures and
+// coordinator errors. Nothing is logged under the wheel lock except the
+// first WAL failure.
+func WithLogger(l *slog.Logger) Option {
+	return func(tw *TimeWheel) {
+		tw.logger = l
+	}
+}
+
+func (tw *TimeWheel) log(level slog.Level, msg string, args ...any) {
+	if tw.logger == nil {
+		return
+	}
+	tw.logger.Log(context.Background(), level, msg, args...)
+}
+
+// logLag reports a tick falling behind its schedule by more than one
+// interval, once per episode rather than on every late tick.
+func (tw *TimeWheel) logLag(lag int64) {
+	late := lag > int64(tw.baseInterval)
+	if tw.lagging == late {
+		return
+	}
+	tw.lagging = late
+	if late {
+		tw.log(slog.LevelWarn, "timewheel: ticks running late", "lag", time.Duration(lag), "interval", tw.baseInterval)
+	} else {
+		tw.log(slog.LevelInfo, "timewheel: ticks back on schedule")
+	}
+}
@@ -0,0 +1,30 @@
+package timewheel
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	tw := NewTimeWheel(0, 10, func(k string, v any) {
+		if k == "bad" {
+			panic("boom")
+		}
+	}, WithSyncCallbacks(0), WithLogger(logger), WithExpiredChannel(1, OverflowDropNewest))
+	defer tw.Stop()
+
+	tw.Set("bad", "data", ManualInterval)
+	tw.Set("dropped", "data", ManualInterval)
+	tw.Tick()
+
+	out := buf.String()
+	for _, want := range []string{"callback panicked", "key=bad", "panic=boom", "dropped newest"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected the log to contain %q, got:\n%s", want, out)
+		}
+	}
+}
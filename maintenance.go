@@ -0,0 +1,123 @@
+package timewheel
+
+import (
+	"container/heap"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// maintenance is the job behind a pinned entry. Pinned entries live in the
+// wheel's slots but not in keyMap, so FlushAll, Delete, capacity limits,
+// holds and the start gate never touch them.
+type maintenance struct {
+	every   time.Duration
+	fn      func()
+	running atomic.Bool
+	removed atomic.Bool
+}
+
+// Maintain runs fn every interval on its own goroutine for as long as the
+// wheel runs, skipping a run while the previous one is still going. Use it
+// for periodic housekeeping such as snapshots or stats rollups; the wheel
+// uses it for its own. every must be at least the base interval.
+func (tw *TimeWheel) Maintain(name string, every time.Duration, fn func()) error {
+	if tw.stopped() {
+		return ErrStopped
+	}
+	if every < tw.baseInterval {
+		return ErrOutOfRange
+	}
+
+	tw.mu.Lock()
+	defer tw.unlock()
+
+	if _, exists := tw.pinned[name]; exists {
+		return ErrDuplicate
+	}
+	entry := &taskEntry{
+		key:        name,
+		expiration: tw.now().Add(every),
+		maint:      &maintenance{every: every, fn: fn},
+	}
+	tw.grow(every)
+	targetLayer, targetPos, rounds := tw.findPosition(every)
+	tw.place(entry, targetLayer, targetPos, rounds)
+	tw.pinned[name] = entry
+	tw.rouse()
+	return nil
+}
+
+// StopMaintenance unregisters a maintenance task. A run already due may
+// still start.
+func (tw *TimeWheel) StopMaintenance(name string) error {
+	tw.mu.Lock()
+	defer tw.unlock()
+
+	entry, exists := tw.pinned[name]
+	if !exists {
+		return ErrNotFound
+	}
+	delete(tw.pinned, name)
+	tw.unlink(entry)
+	entry.maint.removed.Store(true)
+	return nil
+}
+
+// replan puts a due pinned entry back for its next run. Runs missed during
+// a stall are skipped.
+func (tw *TimeWheel) replan(entry *taskEntry, now time.Time) {
+	next := entry.expiration.Add(entry.maint.every)
+	if !next.After(now) {
+		next = now.Add(entry.maint.every)
+	}
+	entry.expiration = next
+	targetLayer, targetPos, rounds := tw.findPosition(next.Sub(now))
+	tw.place(entry, targetLayer, targetPos, rounds)
+}
+
+// repin restores the pinned entries after the slots were cleared.
+func (tw *TimeWheel) repin() {
+	for _, entry := range tw.pinned {
+		tw.place(entry, tw.layers[entry.layerIndex], entry.bucketPos, entry.rounds)
+	}
+}
+
+func (tw *TimeWheel) runMaintenance(entry *taskEntry) {
+	m := entry.maint
+	if m.removed.Load() || !m.running.CompareAndSwap(false, true) {
+		return
+	}
+	tw.runtime.Go(func() {
+		defer m.running.Store(false)
+		defer func() {
+			if r := recover(); r != nil {
+				tw.counters.panics.Add(1)
+				tw.log(slog.LevelError, "timewheel: maintenance task panicked", "name", entry.key, "panic", r)
+				if tw.panicHandler != nil {
+					tw.panicHandler(entry.key, nil, r)
+				}
+			}
+		}()
+		m.fn()
+	})
+}
+
+// warningCompaction is how often stale pre-expiry warnings, left behind by
+// tasks moved or removed, are dropped from the warning heap.
+const warningCompaction = time.Minute
+
+func (tw *TimeWheel) compactWarnings() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	live := tw.warnings[:0]
+	for _, w := range tw.warnings {
+		if tw.warned(w) != nil {
+			live = append(live, w)
+		}
+	}
+	clear(tw.warnings[len(live):])
+	tw.warnings = live
+	heap.Init(&tw.warnings)
+}
@@ -0,0 +1,64 @@
+package timewheel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMaintain(t *testing.T) {
+	runs := make(chan struct{}, 4)
+	tw := NewTimeWheel(0, 10, nil, WithCapacity(1, EvictRejectNew), WithStartGate())
+	defer tw.Stop()
+
+	if err := tw.Maintain("snapshot", 3*ManualInterval, func() { runs <- struct{}{} }); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Maintain("snapshot", 3*ManualInterval, func() {}); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Expected ErrDuplicate, got %v", err)
+	}
+	if err := tw.Maintain("fast", ManualInterval/2, func() {}); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Expected ErrOutOfRange, got %v", err)
+	}
+
+	// Neither a full wheel, the closed start gate nor FlushAll stops it
+	tw.Set("task", "data", time.Hour)
+	tw.FlushAll()
+	if n := tw.Stats().Pending; n != 0 {
+		t.Errorf("Expected maintenance tasks not to count as pending, got %d", n)
+	}
+
+	for i := 0; i < 2; i++ {
+		tw.Advance(3 * ManualInterval)
+		select {
+		case <-runs:
+		case <-time.After(time.Second):
+			t.Fatalf("Expected run %d", i+1)
+		}
+	}
+
+	if err := tw.StopMaintenance("snapshot"); err != nil {
+		t.Fatal(err)
+	}
+	tw.Advance(6 * ManualInterval)
+	select {
+	case <-runs:
+		t.Error("Expected no run after StopMaintenance")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestCompactWarnings(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil, WithWarnings(func(TaskInfo, time.Duration) {}, ManualInterval))
+	defer tw.Stop()
+
+	tw.Set("moved", "data", 10*ManualInterval)
+	tw.Move("moved", 20*ManualInterval)
+	tw.Set("deleted", "data", 10*ManualInterval)
+	tw.Delete("deleted")
+
+	tw.compactWarnings()
+	if n := len(tw.warnings); n != 1 {
+		t.Errorf("Expected only the live warning to remain, got %d", n)
+	}
+}
@@ -0,0 +1,185 @@
+package timewheel
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// defaultManagerBacklog bounds the expirations one wheel defers, unless
+// ManagerBacklog says otherwise.
+const defaultManagerBacklog = 1 << 16
+
+// Manager coordinates wheels sharing a process. Its arbiter splits a budget of
+// expirations per round across the registered wheels by priority, a round
+// being one tick of each; a wheel with more tasks due than its share
+// dispatches the rest on following ticks, so an expiration storm in one wheel
+// cannot starve the others. Budget a wheel leaves unused passes to the wheels
+// drawing after it in the round.
+type Manager struct {
+	mu      sync.Mutex
+	budget  int
+	backlog int
+	wheels  map[*TimeWheel]int
+	total   int
+	// left is the budget not yet drawn this round; drawn holds the wheels
+	// that have ticked in it.
+	left  int
+	drawn map[*TimeWheel]bool
+}
+
+// ManagerOption configures a Manager.
+type ManagerOption func(*Manager)
+
+// ManagerBacklog caps the expirations each wheel defers at n; beyond it the
+// oldest dispatch at once, over budget, so a burst cannot grow the backlog
+// without bound. n <= 0 removes the cap.
+func ManagerBacklog(n int) ManagerOption {
+	return func(m *Manager) {
+		m.backlog = n
+	}
+}
+
+// NewManager returns a Manager sharing budget expirations per round. A
+// budget <= 0 disables arbitration.
+func NewManager(budget int, opts ...ManagerOption) *Manager {
+	m := &Manager{
+		budget:  budget,
+		backlog: defaultManagerBacklog,
+		wheels:  make(map[*TimeWheel]int),
+		drawn:   make(map[*TimeWheel]bool),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	m.newRound()
+	return m
+}
+
+// Add registers tw with the given priority, replacing any earlier priority.
+// A wheel registered with another Manager moves to this one, keeping the
+// tasks it has deferred.
+func (m *Manager) Add(tw *TimeWheel, priority int) {
+	if priority < 1 {
+		priority = 1
+	}
+
+	tw.mu.Lock()
+	old := tw.manager
+	tw.manager = m
+	tw.unlock()
+	if old != nil && old != m {
+		old.forget(tw)
+	}
+
+	m.mu.Lock()
+	m.total += priority - m.wheels[tw]
+	m.wheels[tw] = priority
+	m.newRound()
+	m.mu.Unlock()
+}
+
+func (m *Manager) Remove(tw *TimeWheel) {
+	m.forget(tw)
+
+	tw.mu.Lock()
+	if tw.manager != m {
+		tw.unlock()
+		return
+	}
+	tw.manager = nil
+	backlog := tw.backlog
+	tw.backlog = nil
+	tw.unlock()
+
+	tw.dispatch(backlog)
+}
+
+// forget drops tw from the arbitration.
+func (m *Manager) forget(tw *TimeWheel) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.total -= m.wheels[tw]
+	delete(m.wheels, tw)
+	m.newRound()
+}
+
+func (m *Manager) Wheels() []*TimeWheel {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	wheels := make([]*TimeWheel, 0, len(m.wheels))
+	for tw := range m.wheels {
+		wheels = append(wheels, tw)
+	}
+	return wheels
+}
+
+func (m *Manager) Stop() {
+	for _, tw := range m.Wheels() {
+		tw.Stop()
+	}
+}
+
+// newRound refills the budget. Called under m.mu.
+func (m *Manager) newRound() {
+	m.left = m.budget
+	clear(m.drawn)
+}
+
+// reserve is the share of the budget a wheel of the given priority is
+// guaranteed each round, at least one.
+func (m *Manager) reserve(priority int) int {
+	return max(m.budget*priority/m.total, 1)
+}
+
+// grant returns how many of want expirations tw may dispatch this tick, or
+// -1 for no limit. A wheel drawing twice opens a new round; within a round a
+// wheel gets its reserve, or everything left beyond the reserves of the
+// wheels yet to draw if that is more.
+func (m *Manager) grant(tw *TimeWheel, want int) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	priority, ok := m.wheels[tw]
+	if !ok || m.budget <= 0 {
+		return -1
+	}
+	if m.drawn[tw] {
+		m.newRound()
+	}
+	m.drawn[tw] = true
+
+	held := 0
+	for w, p := range m.wheels {
+		if !m.drawn[w] {
+			held += m.reserve(p)
+		}
+	}
+	n := min(want, max(m.reserve(priority), m.left-held))
+	m.left = max(m.left-n, 0)
+	return n
+}
+
+// applyBudget trims a tick's expirations to the wheel's share, queuing the
+// rest behind earlier leftovers up to the Manager's backlog cap. Called
+// under tw.mu.
+func (tw *TimeWheel) applyBudget(expired []*taskEntry) []*taskEntry {
+	m := tw.manager
+	if m == nil {
+		return expired
+	}
+	if len(tw.backlog) > 0 {
+		expired = append(tw.backlog, expired...)
+		tw.backlog = nil
+	}
+	quota := m.grant(tw, len(expired))
+	if quota < 0 || len(expired) <= quota {
+		return expired
+	}
+	if over := len(expired) - quota - m.backlog; m.backlog > 0 && over > 0 {
+		tw.log(slog.LevelWarn, "timewheel: manager backlog full, dispatching over budget", "tasks", over)
+		quota += over
+	}
+	tw.backlog = append([]*taskEntry(nil), expired[quota:]...)
+	return expired[:quota]
+}
@@ -0,0 +1,148 @@
+package timewheel
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+func TestManagerBudget(t *testing.T) {
+	var stormFired, quietFired atomic.Int32
+	storm := NewTimeWheel(0, 10, func(string, any) { stormFired.Add(1) }, WithSyncCallbacks(0))
+	quiet := NewTimeWheel(0, 10, func(string, any) { quietFired.Add(1) }, WithSyncCallbacks(0))
+
+	m := NewManager(4)
+	m.Add(storm, 3)
+	m.Add(quiet, 1)
+	defer m.Stop()
+
+	for _, k := range []string{"a", "b", "c", "d", "e"} {
+		storm.Set(k, nil, ManualInterval)
+	}
+	quiet.Set("q", nil, ManualInterval)
+
+	storm.Tick()
+	quiet.Tick()
+	if n := stormFired.Load(); n != 3 {
+		t.Errorf("Expected storm wheel limited to 3 expirations, got %d", n)
+	}
+	if n := quietFired.Load(); n != 1 {
+		t.Errorf("Expected quiet wheel to fire its task, got %d", n)
+	}
+	if d := storm.Stats().Deferred; d != 2 {
+		t.Errorf("Expected 2 deferred tasks, got %d", d)
+	}
+
+	storm.Tick()
+	if n := stormFired.Load(); n != 5 {
+		t.Errorf("Expected deferred tasks to fire on the next tick, got %d", n)
+	}
+}
+
+func TestManagerRemoveFlushesBacklog(t *testing.T) {
+	var fired atomic.Int32
+	tw := NewTimeWheel(0, 10, func(string, any) { fired.Add(1) }, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	m := NewManager(1)
+	m.Add(tw, 1)
+	tw.Set("a", nil, ManualInterval)
+	tw.Set("b", nil, ManualInterval)
+	tw.Tick()
+
+	m.Remove(tw)
+	if n := fired.Load(); n != 2 {
+		t.Errorf("Expected backlog to be dispatched on Remove, got %d", n)
+	}
+}
+
+func TestManagerStopAndDrainBacklog(t *testing.T) {
+	var fired atomic.Int32
+	tw := NewTimeWheel(0, 10, func(string, any) { fired.Add(1) }, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	m := NewManager(1)
+	m.Add(tw, 1)
+	tw.Set("a", nil, ManualInterval)
+	tw.Set("b", nil, ManualInterval)
+	tw.Tick()
+
+	pending := tw.StopAndDrain()
+	if len(pending) != 1 || pending[0].Key != "b" {
+		t.Fatalf("Expected the deferred task to be drained, got %v", pending)
+	}
+	tw.Start(context.Background())
+	tw.Tick()
+	if n := fired.Load(); n != 1 {
+		t.Errorf("Expected the drained task not to fire after a restart, got %d fired", n)
+	}
+}
+
+func TestManagerRedistributesUnusedBudget(t *testing.T) {
+	var fired atomic.Int32
+	storm := NewTimeWheel(0, 10, func(string, any) { fired.Add(1) }, WithSyncCallbacks(0))
+	quiet := NewTimeWheel(0, 10, func(string, any) {}, WithSyncCallbacks(0))
+
+	m := NewManager(4)
+	m.Add(storm, 1)
+	m.Add(quiet, 1)
+	defer m.Stop()
+
+	for _, k := range []string{"a", "b", "c", "d", "e", "f"} {
+		storm.Set(k, nil, ManualInterval)
+	}
+
+	quiet.Tick()
+	storm.Tick()
+	if n := fired.Load(); n != 4 {
+		t.Errorf("Expected storm wheel to use the budget quiet left, got %d", n)
+	}
+	if d := storm.Stats().Deferred; d != 2 {
+		t.Errorf("Expected 2 deferred tasks, got %d", d)
+	}
+}
+
+func TestManagerBacklogLimit(t *testing.T) {
+	var fired atomic.Int32
+	tw := NewTimeWheel(0, 10, func(string, any) { fired.Add(1) }, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	m := NewManager(1, ManagerBacklog(2))
+	m.Add(tw, 1)
+	for _, k := range []string{"a", "b", "c", "d", "e"} {
+		tw.Set(k, nil, ManualInterval)
+	}
+	tw.Tick()
+
+	if n := fired.Load(); n != 3 {
+		t.Errorf("Expected expirations beyond the backlog cap to fire, got %d", n)
+	}
+	if d := tw.Stats().Deferred; d != 2 {
+		t.Errorf("Expected the backlog capped at 2, got %d", d)
+	}
+}
+
+func TestManagerAddMovesWheel(t *testing.T) {
+	tw := NewTimeWheel(0, 10, func(string, any) {})
+	defer tw.Stop()
+
+	m1 := NewManager(4)
+	m2 := NewManager(4)
+	m1.Add(tw, 1)
+	m2.Add(tw, 2)
+
+	if len(m1.Wheels()) != 0 {
+		t.Errorf("Expected wheel to leave its old manager, got %d wheels", len(m1.Wheels()))
+	}
+	if len(m2.Wheels()) != 1 {
+		t.Errorf("Expected wheel in the new manager, got %d wheels", len(m2.Wheels()))
+	}
+
+	m1.Remove(tw)
+	tw.mu.Lock()
+	owner := tw.manager
+	tw.mu.Unlock()
+	if owner != m2 {
+		t.Error("Expected Remove on the old manager to leave the wheel with the new one")
+	}
+}
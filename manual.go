@@ -0,0 +1,56 @@
+package timewheel
+
+import "time"
+
+// ManualInterval is the virtual base interval of a wheel constructed with
+// baseInterval <= 0.
+const ManualInterval = time.Millisecond
+
+// WithManualMode keeps the given base interval but drives the wheel from a
+// virtual clock instead of a ticker. Time only moves through Advance and Tick.
+func WithManualMode() Option {
+	return func(tw *TimeWheel) {
+		tw.manual = true
+	}
+}
+
+func (tw *TimeWheel) Manual() bool {
+	return tw.manual
+}
+
+// Advance moves the virtual clock forward by d, running every tick that
+// becomes due on the way. It is a no-op on a ticker-driven wheel.
+func (tw *TimeWheel) Advance(d time.Duration) {
+	if !tw.manual || d <= 0 {
+		return
+	}
+
+	tw.mu.Lock()
+	end := tw.virtualNow.Add(d)
+	for next := tw.lastTick.Add(tw.baseInterval); !next.After(end); next = tw.lastTick.Add(tw.baseInterval) {
+		tw.lastTick = next
+		tw.virtualNow = next
+		expired := tw.applyBudget(tw.step(next))
+
+		// Dispatch each tick outside the lock, as the ticker-driven loop does
+		tw.unlock()
+		tw.dispatch(expired)
+		tw.mu.Lock()
+	}
+	if end.After(tw.virtualNow) {
+		tw.virtualNow = end
+	}
+	tw.unlock()
+}
+
+// Tick advances the virtual clock by exactly one base interval.
+func (tw *TimeWheel) Tick() {
+	tw.Advance(tw.baseInterval)
+}
+
+func (tw *TimeWheel) now() time.Time {
+	if tw.manual {
+		return tw.virtualNow
+	}
+	return tw.clock.Now()
+}
@@ -0,0 +1,99 @@
+package timewheel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestManualMode(t *testing.T) {
+	fired := make(chan string, 1)
+	tw := NewTimeWheel(0, 10, func(k string, v any) {
+		fired <- k
+	})
+	defer tw.Stop()
+
+	if !tw.Manual() {
+		t.Fatal("Expected zero base interval to create a manual wheel")
+	}
+
+	tw.Set("test", "data", 5*ManualInterval)
+	tw.Advance(4 * ManualInterval)
+	select {
+	case <-fired:
+		t.Fatal("Callback fired before its deadline")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	tw.Tick()
+	select {
+	case k := <-fired:
+		if k != "test" {
+			t.Errorf("Expected key test, got %s", k)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Callback did not fire after advancing past its deadline")
+	}
+}
+
+func TestManualModeHigherLayer(t *testing.T) {
+	fired := make(chan string, 1)
+	tw := NewTimeWheel(100*time.Millisecond, 10, func(k string, v any) {
+		fired <- k
+	}, WithManualMode())
+	defer tw.Stop()
+
+	tw.Set("test", "data", 1500*time.Millisecond)
+	tw.Advance(1400 * time.Millisecond)
+	select {
+	case <-fired:
+		t.Fatal("Callback fired before its deadline")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	tw.Advance(100 * time.Millisecond)
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("Callback did not fire after advancing past its deadline")
+	}
+}
+
+func TestExtendAndShorten(t *testing.T) {
+	fired := make(chan string, 1)
+	tw := NewTimeWheel(0, 10, func(k string, v any) {
+		fired <- k
+	})
+	defer tw.Stop()
+
+	if _, err := tw.Extend("missing", ManualInterval); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	tw.Set("lease", "data", 5*ManualInterval)
+	tw.Advance(2 * ManualInterval)
+
+	remaining, err := tw.Extend("lease", 4*ManualInterval)
+	if err != nil || remaining != 7*ManualInterval {
+		t.Errorf("Expected 7ms remaining, got %s (%v)", remaining, err)
+	}
+
+	remaining, err = tw.Shorten("lease", 3*ManualInterval)
+	if err != nil || remaining != 4*ManualInterval {
+		t.Errorf("Expected 4ms remaining, got %s (%v)", remaining, err)
+	}
+
+	tw.Advance(3 * ManualInterval)
+	select {
+	case <-fired:
+		t.Fatal("Callback fired before its shortened deadline")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	tw.Tick()
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("Callback did not fire at its shortened deadline")
+	}
+}
@@ -0,0 +1,132 @@
+package timewheel
+
+import (
+	"slices"
+	"time"
+)
+
+// Many is the value delivered for a task set with SetMany: the keys it
+// covers and the value they share.
+type Many struct {
+	Keys  []string
+	Value any
+}
+
+// SetMany schedules one task for keys that expire together, such as a batch
+// of cache entries, and returns the key it waits under. The callback gets a
+// Many with every key when it fires. The wheel holds one entry for the whole
+// batch, but each key also addresses it: Delete drops the key from the batch,
+// Remaining reports the batch's deadline and Move takes the key out to fire
+// on its own. Setting a key anew, alone or in another batch, takes it out
+// too; a batch left with no keys is removed.
+func (tw *TimeWheel) SetMany(keys []string, value any, ttl time.Duration, opts ...SetOption) (string, error) {
+	keys = slices.Clone(keys)
+	return tw.ScheduleWith(Many{Keys: keys, Value: value}, ttl, append(opts, taskMany(keys))...)
+}
+
+// SetMany schedules the keys like TimeWheel.SetMany, one batch per shard the
+// keys hash to, so each key is addressed through the shard that holds it and
+// the callback runs once per batch. If a shard refuses its batch, the others
+// are deleted again.
+func (s *ShardedTimeWheel) SetMany(keys []string, value any, ttl time.Duration, opts ...SetOption) error {
+	byShard := make(map[*TimeWheel][]string)
+	var order []*TimeWheel
+	for _, key := range keys {
+		shard := s.Shard(key)
+		if byShard[shard] == nil {
+			order = append(order, shard)
+		}
+		byShard[shard] = append(byShard[shard], key)
+	}
+	set := make(map[*TimeWheel]string, len(order))
+	for _, shard := range order {
+		batch, err := shard.SetMany(byShard[shard], value, ttl, opts...)
+		if err != nil {
+			for shard, batch := range set {
+				shard.Delete(batch)
+			}
+			return err
+		}
+		set[shard] = batch
+	}
+	return nil
+}
+
+func taskMany(keys []string) SetOption {
+	return func(so *setOptions) {
+		so.many = keys
+	}
+}
+
+// lookup returns the entry of key, or the SetMany batch holding it.
+func (tw *TimeWheel) lookup(key string) (*taskEntry, bool) {
+	if entry, ok := tw.keyMap[key]; ok {
+		return entry, true
+	}
+	entry, ok := tw.manyKeys[key]
+	return entry, ok
+}
+
+// indexMany registers the keys of a batch, taking each out of the batch that
+// held it before, as it does the key of a plain task.
+func (tw *TimeWheel) indexMany(entry *taskEntry) {
+	if old := tw.manyKeys[entry.key]; old != nil && old != entry {
+		tw.dropMany(old, entry.key, ReasonReplaced)
+	}
+	for _, key := range entry.many {
+		if old := tw.manyKeys[key]; old != nil && old != entry {
+			tw.dropMany(old, key, ReasonReplaced)
+		}
+		tw.manyKeys[key] = entry
+	}
+}
+
+func (tw *TimeWheel) unindexMany(entry *taskEntry) {
+	for _, key := range entry.many {
+		if tw.manyKeys[key] == entry {
+			delete(tw.manyKeys, key)
+		}
+	}
+}
+
+// dropMany takes key out of its batch, removing the batch for reason once no
+// key is left.
+func (tw *TimeWheel) dropMany(batch *taskEntry, key string, reason Reason) {
+	delete(tw.manyKeys, key)
+	// Copied, as a Many already handed out shares the slice
+	batch.many = slices.DeleteFunc(slices.Clone(batch.many), func(k string) bool { return k == key })
+	if len(batch.many) == 0 {
+		tw.cancel(batch, reason)
+		return
+	}
+	if m, ok := batch.value.(Many); ok {
+		m.Keys = batch.many
+		batch.value = m
+	}
+	tw.journal(hookReschedule, batch)
+}
+
+// moveMany takes key out of its batch to fire d from now on its own, keeping
+// the batch's value and options. The key stays in the batch if the wheel
+// refuses the new task.
+func (tw *TimeWheel) moveMany(batch *taskEntry, key string, d time.Duration) {
+	var value any
+	if m, ok := batch.value.(Many); ok {
+		value = m.Value
+	}
+	so := &setOptions{
+		zeroTTL:     FireAsync,
+		annotations: batch.annotations,
+		tags:        batch.tags,
+		ctx:         batch.ctx,
+		execTimeout: batch.execTimeout,
+		many:        []string{key},
+	}
+	if _, err := tw.set(key, Many{Keys: so.many, Value: value}, d, so); err != nil {
+		return
+	}
+	// A move too short for any layer fires without being tracked
+	if tw.manyKeys[key] == batch {
+		tw.dropMany(batch, key, ReasonReplaced)
+	}
+}
@@ -0,0 +1,112 @@
+package timewheel
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSetMany(t *testing.T) {
+	var got []Many
+	tw := NewTimeWheel(0, 10, func(key string, value any) {
+		got = append(got, value.(Many))
+	}, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	keys := []string{"cache:1", "cache:2", "cache:3"}
+	key, err := tw.SetMany(keys, "v1", 2*ManualInterval)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys[0] = "changed"
+	if n := tw.Stats().Pending; n != 1 {
+		t.Errorf("Expected one entry for the batch, got %d", n)
+	}
+	cancelled, _ := tw.SetMany([]string{"cache:4"}, "v2", 2*ManualInterval)
+	tw.Delete(cancelled)
+
+	tw.Advance(2 * ManualInterval)
+	if len(got) != 1 {
+		t.Fatalf("Expected one callback for the batch, got %d", len(got))
+	}
+	if want := []string{"cache:1", "cache:2", "cache:3"}; !slices.Equal(got[0].Keys, want) || got[0].Value != "v1" {
+		t.Errorf("Expected %v with v1, got %+v", want, got[0])
+	}
+	if _, existed := tw.Delete(key); existed {
+		t.Error("Expected the batch to be gone once fired")
+	}
+}
+
+func TestShardedSetMany(t *testing.T) {
+	fired := make(chan Many, 1)
+	s := NewShardedTimeWheel(4, 10*time.Millisecond, 10, func(key string, value any) {
+		fired <- value.(Many)
+	})
+	defer s.Stop()
+
+	keys := []string{"a", "b", "c", "d"}
+	if err := s.SetMany(keys, nil, 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if _, existed := s.Delete("d"); !existed {
+		t.Error("Expected a batched key to be deleted through its shard")
+	}
+	var got []string
+	deadline := time.After(time.Second)
+	for len(got) < 3 {
+		select {
+		case m := <-fired:
+			got = append(got, m.Keys...)
+		case <-deadline:
+			t.Fatalf("Batches did not fire, got %v", got)
+		}
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("Expected [a b c], got %v", got)
+	}
+}
+
+func TestSetManyKeys(t *testing.T) {
+	var got []Many
+	tw := NewTimeWheel(0, 10, func(key string, value any) {
+		got = append(got, value.(Many))
+	}, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	batch, err := tw.SetMany([]string{"a", "b", "c", "d"}, "v", 4*ManualInterval)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lo, _, ok := tw.Remaining("b"); !ok || lo != 4*ManualInterval {
+		t.Errorf("Expected b to report the batch deadline, got %s %v", lo, ok)
+	}
+	if left, existed := tw.Delete("a"); !existed || left != 4*ManualInterval {
+		t.Errorf("Expected to delete a from the batch, got %s %v", left, existed)
+	}
+	if _, existed := tw.Delete("a"); existed {
+		t.Error("Expected a to be gone after its Delete")
+	}
+	if prev, existed := tw.Move("b", 2*ManualInterval); !existed || prev != 4*ManualInterval {
+		t.Errorf("Expected to move b out of the batch, got %s %v", prev, existed)
+	}
+	tw.Set("c", "alone", 8*ManualInterval)
+
+	tw.Advance(2 * ManualInterval)
+	if len(got) != 1 || !slices.Equal(got[0].Keys, []string{"b"}) || got[0].Value != "v" {
+		t.Fatalf("Expected b to fire alone with the batch value, got %+v", got)
+	}
+	tw.Advance(2 * ManualInterval)
+	if len(got) != 2 || !slices.Equal(got[1].Keys, []string{"d"}) {
+		t.Fatalf("Expected the batch to fire with d only, got %+v", got)
+	}
+	if _, existed := tw.Delete(batch); existed {
+		t.Error("Expected the batch to be gone once fired")
+	}
+
+	last, _ := tw.SetMany([]string{"e"}, nil, time.Minute)
+	tw.Delete("e")
+	if _, existed := tw.Delete(last); existed {
+		t.Error("Expected a batch to be removed with its last key")
+	}
+}
@@ -0,0 +1,52 @@
+package timewheel
+
+import (
+	"math"
+	"time"
+)
+
+// WithMaxDuration caps the TTL SetE accepts at d, below what the wheel
+// could represent anyway.
+func WithMaxDuration(d time.Duration) Option {
+	return func(tw *TimeWheel) {
+		tw.maxDuration = d
+	}
+}
+
+// MaxDuration returns the longest TTL SetE accepts: the WithMaxDuration cap,
+// or else the longest delay whose deadline survives the wheel's jitter.
+func (tw *TimeWheel) MaxDuration() time.Duration {
+	limit := time.Duration(math.MaxInt64) - tw.jitter
+	if tw.maxDuration > 0 && tw.maxDuration < limit {
+		limit = tw.maxDuration
+	}
+	return limit
+}
+
+// SetE schedules the task like Set, but returns ErrDurationTooLong instead of
+// scheduling a TTL beyond MaxDuration, whether given or from a TTLProvider,
+// and any error SetWith would.
+func (tw *TimeWheel) SetE(key string, value any, expiration time.Duration) error {
+	if ttlOf(value, expiration) > tw.MaxDuration() {
+		return ErrDurationTooLong
+	}
+	return tw.SetWith(key, value, expiration)
+}
+
+func (ns *Namespace) SetE(key string, value any, expiration time.Duration) error {
+	return ns.tw.SetE(ns.Key(key), value, expiration)
+}
+
+func (ns *Namespace) MaxDuration() time.Duration {
+	return ns.tw.MaxDuration()
+}
+
+func (s *ShardedTimeWheel) SetE(key string, value any, expiration time.Duration) error {
+	return s.Shard(key).SetE(key, value, expiration)
+}
+
+// MaxDuration returns the limit shared by the shards, which are configured
+// alike.
+func (s *ShardedTimeWheel) MaxDuration() time.Duration {
+	return s.shards[0].MaxDuration()
+}
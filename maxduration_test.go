@@ -0,0 +1,57 @@
+package timewheel
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSetE(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil, WithMaxDuration(time.Hour))
+	defer tw.Stop()
+
+	if got := tw.MaxDuration(); got != time.Hour {
+		t.Errorf("Expected the configured maximum, got %s", got)
+	}
+	if err := tw.SetE("long", "data", time.Hour+time.Second); !errors.Is(err, ErrDurationTooLong) {
+		t.Errorf("Expected ErrDurationTooLong, got %v", err)
+	}
+	if _, ok := tw.keyMap["long"]; ok {
+		t.Error("Expected the rejected task not to be scheduled")
+	}
+	if err := tw.SetE("fits", "data", time.Hour); err != nil {
+		t.Errorf("Expected a TTL at the maximum to be accepted, got %v", err)
+	}
+	if _, ok := tw.keyMap["fits"]; !ok {
+		t.Error("Expected the accepted task to be pending")
+	}
+	if err := tw.SetE("provided", session{ttl: 2 * time.Hour}, 0); !errors.Is(err, ErrDurationTooLong) {
+		t.Errorf("Expected a TTLProvider's TTL to be checked too, got %v", err)
+	}
+}
+
+func TestMaxDurationJitter(t *testing.T) {
+	fired := false
+	tw := NewTimeWheel(0, 10, func(key string, value any) {
+		fired = true
+	}, WithSyncCallbacks(0), WithJitter(time.Hour), WithZeroTTLPolicy(FireSync))
+	defer tw.Stop()
+
+	if got, want := tw.MaxDuration(), time.Duration(math.MaxInt64)-time.Hour; got != want {
+		t.Errorf("Expected the maximum to leave room for jitter, got %s, want %s", got, want)
+	}
+	if err := tw.SetE("longest", "data", time.Duration(math.MaxInt64)); !errors.Is(err, ErrDurationTooLong) {
+		t.Errorf("Expected ErrDurationTooLong, got %v", err)
+	}
+
+	// Plain Set saturates instead of wrapping round to an immediate fire
+	tw.Set("longest", "data", time.Duration(math.MaxInt64))
+	tw.Advance(2 * ManualInterval)
+	if fired {
+		t.Error("Expected an overlong TTL not to fire immediately")
+	}
+	if _, ok := tw.keyMap["longest"]; !ok {
+		t.Error("Expected the overlong task to stay pending")
+	}
+}
@@ -0,0 +1,94 @@
+package timewheel
+
+import "sync/atomic"
+
+// Well-known metric names reported by Metrics, following the runtime/metrics
+// "/path/name:unit" convention.
+const (
+	MetricPendingTasks     = "/timewheel/tasks/pending:tasks"
+	MetricScheduledTasks   = "/timewheel/tasks/scheduled:tasks"
+	MetricFiredTasks       = "/timewheel/tasks/fired:tasks"
+	MetricDeletedTasks     = "/timewheel/tasks/deleted:tasks"
+	MetricOverdueHeldTasks = "/timewheel/tasks/held-overdue:tasks"
+	MetricDroppedTasks     = "/timewheel/tasks/dropped:tasks"
+	MetricDeferredTasks    = "/timewheel/tasks/deferred:tasks"
+	MetricEvictedTasks     = "/timewheel/tasks/evicted:tasks"
+	MetricTicks            = "/timewheel/ticks:ticks"
+	MetricCompensatedTicks = "/timewheel/ticks/compensated:ticks"
+	MetricTickLag          = "/timewheel/ticks/lag:seconds"
+	MetricCatchUps         = "/timewheel/ticks/catch-ups:events"
+	MetricLateTasks        = "/timewheel/tasks/late:tasks"
+	MetricCallbackPanics   = "/timewheel/callbacks/panics:calls"
+	MetricCallbackTimeouts = "/timewheel/callbacks/timeouts:calls"
+	MetricCallbackErrors   = "/timewheel/callbacks/errors:calls"
+	MetricRetriedTasks     = "/timewheel/tasks/retried:tasks"
+	MetricTaskLateness     = "/timewheel/tasks/lateness:seconds"
+	MetricBaseInterval     = "/timewheel/config/base-interval:seconds"
+	MetricLayers           = "/timewheel/config/layers:layers"
+	MetricTimerResolution  = "/timewheel/config/timer-resolution:seconds"
+
+	// Cumulative calls and seconds spent per mutation; Stats has the full
+	// histograms.
+	MetricSetCalls       = "/timewheel/ops/set/calls:calls"
+	MetricSetLockWait    = "/timewheel/ops/set/lock-wait:seconds"
+	MetricSetLatency     = "/timewheel/ops/set/latency:seconds"
+	MetricDeleteCalls    = "/timewheel/ops/delete/calls:calls"
+	MetricDeleteLockWait = "/timewheel/ops/delete/lock-wait:seconds"
+	MetricDeleteLatency  = "/timewheel/ops/delete/latency:seconds"
+	MetricMoveCalls      = "/timewheel/ops/move/calls:calls"
+	MetricMoveLockWait   = "/timewheel/ops/move/lock-wait:seconds"
+	MetricMoveLatency    = "/timewheel/ops/move/latency:seconds"
+)
+
+type counters struct {
+	scheduled      atomic.Uint64
+	fired          atomic.Uint64
+	deleted        atomic.Uint64
+	dropped        atomic.Uint64
+	ticks          atomic.Uint64
+	compensated    atomic.Uint64
+	catchUps       atomic.Uint64
+	late           atomic.Uint64
+	panics         atomic.Uint64
+	callbackErrors atomic.Uint64
+	timeouts       atomic.Uint64
+	evicted        atomic.Uint64
+	retries        atomic.Uint64
+}
+
+// Metrics returns a snapshot of the wheel's metrics keyed by name.
+func (tw *TimeWheel) Metrics() map[string]float64 {
+	s := tw.Stats()
+	return map[string]float64{
+		MetricPendingTasks:     float64(s.Pending),
+		MetricScheduledTasks:   float64(s.Scheduled),
+		MetricFiredTasks:       float64(s.Fired),
+		MetricDeletedTasks:     float64(s.Deleted),
+		MetricOverdueHeldTasks: float64(s.OverdueHeld),
+		MetricDroppedTasks:     float64(s.Dropped),
+		MetricDeferredTasks:    float64(s.Deferred),
+		MetricEvictedTasks:     float64(s.Evicted),
+		MetricTicks:            float64(s.Ticks),
+		MetricCompensatedTicks: float64(s.CompensatedTicks),
+		MetricTickLag:          s.TickLag.Seconds(),
+		MetricCatchUps:         float64(s.CatchUps),
+		MetricLateTasks:        float64(s.LateTasks),
+		MetricCallbackPanics:   float64(s.Panics),
+		MetricCallbackTimeouts: float64(s.Timeouts),
+		MetricCallbackErrors:   float64(s.CallbackErrors),
+		MetricRetriedTasks:     float64(s.Retries),
+		MetricTaskLateness:     s.Lateness.Sum.Seconds(),
+		MetricBaseInterval:     s.BaseInterval.Seconds(),
+		MetricLayers:           float64(s.Layers),
+		MetricTimerResolution:  s.TimerResolution.Seconds(),
+		MetricSetCalls:         float64(s.SetLatency.Total.Count),
+		MetricSetLockWait:      s.SetLatency.LockWait.Sum.Seconds(),
+		MetricSetLatency:       s.SetLatency.Total.Sum.Seconds(),
+		MetricDeleteCalls:      float64(s.DeleteLatency.Total.Count),
+		MetricDeleteLockWait:   s.DeleteLatency.LockWait.Sum.Seconds(),
+		MetricDeleteLatency:    s.DeleteLatency.Total.Sum.Seconds(),
+		MetricMoveCalls:        float64(s.MoveLatency.Total.Count),
+		MetricMoveLockWait:     s.MoveLatency.LockWait.Sum.Seconds(),
+		MetricMoveLatency:      s.MoveLatency.Total.Sum.Seconds(),
+	}
+}
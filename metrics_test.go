@@ -0,0 +1,30 @@
+package timewheel
+
+import "testing"
+
+func TestMetrics(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil)
+	defer tw.Stop()
+
+	tw.Set("a", "data", ManualInterval)
+	tw.Set("b", "data", 5*ManualInterval)
+	tw.Set("c", "data", 5*ManualInterval)
+	tw.Delete("c")
+	tw.Tick()
+
+	m := tw.Metrics()
+	expected := map[string]float64{
+		MetricPendingTasks:   1,
+		MetricScheduledTasks: 3,
+		MetricFiredTasks:     1,
+		MetricDeletedTasks:   1,
+		MetricTicks:          1,
+		MetricLayers:         3,
+		MetricBaseInterval:   ManualInterval.Seconds(),
+	}
+	for name, want := range expected {
+		if got := m[name]; got != want {
+			t.Errorf("Expected %s = %v, got %v", name, want, got)
+		}
+	}
+}
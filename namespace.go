@@ -0,0 +1,103 @@
+package timewheel
+
+import (
+	"strings"
+	"time"
+)
+
+// NamespaceSeparator joins a namespace name and a key. Like KeySeparator it
+// is a control character, so tenant names such as "a/b" and plain keys such
+// as "a/x" stay apart from namespace "a". Names must not contain it.
+const NamespaceSeparator = "\x1e"
+
+// Namespace is a view of a TimeWheel whose keys are prefixed with
+// name + NamespaceSeparator. Callbacks receive the prefixed key.
+type Namespace struct {
+	tw     *TimeWheel
+	name   string
+	prefix string
+}
+
+func (tw *TimeWheel) Namespace(name string) *Namespace {
+	return &Namespace{
+		tw:     tw,
+		name:   name,
+		prefix: name + NamespaceSeparator,
+	}
+}
+
+func (ns *Namespace) Name() string {
+	return ns.name
+}
+
+func (ns *Namespace) Key(key string) string {
+	return ns.prefix + key
+}
+
+func (ns *Namespace) Set(key string, value any, expiration time.Duration) (time.Duration, bool) {
+	return ns.tw.Set(ns.Key(key), value, expiration)
+}
+
+func (ns *Namespace) SetWith(key string, value any, expiration time.Duration, opts ...SetOption) error {
+	return ns.tw.SetWith(ns.Key(key), value, expiration, opts...)
+}
+
+func (ns *Namespace) SetNX(key string, value any, expiration time.Duration) bool {
+	return ns.tw.SetNX(ns.Key(key), value, expiration)
+}
+
+func (ns *Namespace) Delete(key string) (time.Duration, bool) {
+	return ns.tw.Delete(ns.Key(key))
+}
+
+func (ns *Namespace) Take(key string) (any, bool) {
+	return ns.tw.Take(ns.Key(key))
+}
+
+func (ns *Namespace) UpdateValue(key string, value any) bool {
+	return ns.tw.UpdateValue(ns.Key(key), value)
+}
+
+func (ns *Namespace) Move(key string, expiration time.Duration) (time.Duration, bool) {
+	return ns.tw.Move(ns.Key(key), expiration)
+}
+
+func (ns *Namespace) MoveIf(key string, expected any, expiration time.Duration) bool {
+	return ns.tw.MoveIf(ns.Key(key), expected, expiration)
+}
+
+func (ns *Namespace) Extend(key string, delta time.Duration) (time.Duration, error) {
+	return ns.tw.Extend(ns.Key(key), delta)
+}
+
+func (ns *Namespace) Shorten(key string, delta time.Duration) (time.Duration, error) {
+	return ns.tw.Shorten(ns.Key(key), delta)
+}
+
+func (ns *Namespace) Hold(key string) error {
+	return ns.tw.Hold(ns.Key(key))
+}
+
+func (ns *Namespace) ReleaseHold(key string) error {
+	return ns.tw.ReleaseHold(ns.Key(key))
+}
+
+func (ns *Namespace) Flush() int {
+	return ns.tw.FlushNamespace(ns.name)
+}
+
+// FlushNamespace removes every task in the namespace without firing it and
+// returns how many were removed. A name containing NamespaceSeparator is no
+// namespace and flushes nothing.
+func (tw *TimeWheel) FlushNamespace(name string) int {
+	if strings.Contains(name, NamespaceSeparator) {
+		return 0
+	}
+	return tw.FlushPrefix(name + NamespaceSeparator)
+}
+
+// SplitNamespace splits a key delivered to a callback into its namespace and
+// the key within it. ok is false for keys set outside any namespace.
+func SplitNamespace(key string) (namespace, rest string, ok bool) {
+	return strings.Cut(key, NamespaceSeparator)
+}
@@ -0,0 +1,71 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNamespace(t *testing.T) {
+	fired := make(chan string, 2)
+	tw := NewTimeWheel(0, 10, func(k string, v any) {
+		fired <- k
+	})
+	defer tw.Stop()
+
+	tenantA := tw.Namespace("a")
+	tenantB := tw.Namespace("b")
+	tenantA.Set("session", 1, 2*ManualInterval)
+	tenantA.Set("other", 1, 2*ManualInterval)
+	tenantB.Set("session", 2, 2*ManualInterval)
+
+	if n := tw.FlushNamespace("a"); n != 2 {
+		t.Errorf("Expected 2 tasks flushed, got %d", n)
+	}
+	tw.Advance(2 * ManualInterval)
+
+	select {
+	case k := <-fired:
+		if k != "b"+NamespaceSeparator+"session" {
+			t.Errorf("Expected the session of b, got %q", k)
+		}
+		ns, rest, ok := SplitNamespace(k)
+		if !ok || ns != "b" || rest != "session" {
+			t.Errorf("Unexpected split %q %q %v", ns, rest, ok)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Namespace b task did not fire")
+	}
+
+	select {
+	case k := <-fired:
+		t.Errorf("Flushed task %s fired", k)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestNamespaceNestedPrefix(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil)
+	defer tw.Stop()
+
+	tw.Namespace("a").Set("session", nil, time.Hour)
+	nested := tw.Namespace("a/b")
+	nested.Set("session", nil, time.Hour)
+	tw.Set("a/x", nil, time.Hour)
+
+	if n := tw.FlushNamespace("a"); n != 1 {
+		t.Errorf("Expected only the task of namespace a flushed, got %d", n)
+	}
+	if _, _, ok := tw.Remaining(nested.Key("session")); !ok {
+		t.Error("Expected namespace a/b to keep its task")
+	}
+	if _, _, ok := tw.Remaining("a/x"); !ok {
+		t.Error("Expected the plain key a/x to stay")
+	}
+
+	if ns, rest, ok := SplitNamespace(nested.Key("session")); !ok || ns != "a/b" || rest != "session" {
+		t.Errorf("Unexpected split %q %q %v", ns, rest, ok)
+	}
+	if _, _, ok := SplitNamespace("a/x"); ok {
+		t.Error("Expected a plain key not to split")
+	}
+}
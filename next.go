@@ -0,0 +1,59 @@
+package timewheel
+
+import "time"
+
+// NextExpiration returns the deadline of the soonest pending task, which
+// fires within a base interval of it, so a caller can size its own sleeps
+// or upstream deadlines. Held tasks and those waiting on the start gate are
+// left out, as nothing fires them on time; maintenance tasks are too. ok is
+// false if no task is pending.
+func (tw *TimeWheel) NextExpiration() (next time.Time, ok bool) {
+	tw.mu.RLock()
+	defer tw.mu.RUnlock()
+
+	consider := func(entry *taskEntry) {
+		if !ok || entry.expiration.Before(next) {
+			next, ok = entry.expiration, true
+		}
+	}
+	for _, entry := range tw.backlog {
+		consider(entry)
+	}
+
+	// Within a layer, slots come due in order from the current one, so the
+	// first holding a task without rounds left bounds the layer's soonest
+	for _, l := range tw.layers {
+		for j := 1; j <= l.slots; j++ {
+			due := false
+			for entry := l.buckets[(l.currentPos+j)%l.slots].head; entry != nil; entry = entry.next {
+				// A tick yielding mid-slot leaves a marker readers can see
+				if entry.isMarker() || entry.maint != nil || entry.held {
+					continue
+				}
+				consider(entry)
+				due = due || entry.rounds == 0
+			}
+			if due {
+				break
+			}
+		}
+	}
+	if tw.hybrid {
+		for _, entry := range tw.keyMap {
+			if entry.timer != nil {
+				consider(entry)
+			}
+		}
+	}
+	return next, ok
+}
+
+// NextExpiration returns the soonest deadline across the shards.
+func (s *ShardedTimeWheel) NextExpiration() (next time.Time, ok bool) {
+	for _, tw := range s.shards {
+		if t, found := tw.NextExpiration(); found && (!ok || t.Before(next)) {
+			next, ok = t, true
+		}
+	}
+	return next, ok
+}
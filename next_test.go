@@ -0,0 +1,87 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextExpiration(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	if _, ok := tw.NextExpiration(); ok {
+		t.Error("Expected no next expiration on an empty wheel")
+	}
+	tw.Maintain("job", ManualInterval, func() {})
+	if _, ok := tw.NextExpiration(); ok {
+		t.Error("Expected maintenance tasks to be left out")
+	}
+
+	start := tw.now()
+	tw.Set("upper", nil, 50*ManualInterval) // on the second layer
+	tw.Set("base", nil, 7*ManualInterval)
+	tw.Set("held", nil, 2*ManualInterval)
+	tw.Hold("held")
+	next, ok := tw.NextExpiration()
+	if !ok || !next.Equal(start.Add(7*ManualInterval)) {
+		t.Errorf("Expected the base layer task next, got %v %v", next.Sub(start), ok)
+	}
+
+	tw.Advance(7 * ManualInterval)
+	next, ok = tw.NextExpiration()
+	if !ok || !next.Equal(start.Add(50*ManualInterval)) {
+		t.Errorf("Expected the second layer task next, got %v %v", next.Sub(start), ok)
+	}
+}
+
+func TestNextExpirationRounds(t *testing.T) {
+	tw := NewTimeWheel(0, 4, nil, WithMaxLayers(1))
+	defer tw.Stop()
+
+	// On a single layer of 4 slots, the later task sits in an earlier slot
+	start := tw.now()
+	tw.Set("later", nil, 5*ManualInterval)
+	tw.Set("sooner", nil, 3*ManualInterval)
+	next, ok := tw.NextExpiration()
+	if !ok || !next.Equal(start.Add(3*ManualInterval)) {
+		t.Errorf("Expected the task without rounds first, got %v %v", next.Sub(start), ok)
+	}
+}
+
+func TestShardedNextExpiration(t *testing.T) {
+	s := NewShardedTimeWheel(4, time.Second, 60, nil)
+	defer s.Stop()
+
+	before := time.Now()
+	s.Set("a", nil, time.Hour)
+	s.Set("b", nil, time.Minute)
+	next, ok := s.NextExpiration()
+	if !ok || next.Before(before.Add(time.Minute)) || next.After(time.Now().Add(time.Minute)) {
+		t.Errorf("Expected b's deadline, got %v %v", next, ok)
+	}
+}
+
+func TestNextExpirationMidWalk(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil)
+	defer tw.Stop()
+
+	start := tw.now()
+	tw.Set("a", nil, 3*ManualInterval)
+
+	// Leave the marker of a tick yielding at a's place in the slot
+	tw.mu.Lock()
+	entry := tw.keyMap["a"]
+	b := &tw.layers[entry.layerIndex].buckets[entry.bucketPos]
+	marker := &taskEntry{}
+	b.mark(marker, entry)
+	tw.mu.Unlock()
+
+	next, ok := tw.NextExpiration()
+	if !ok || !next.Equal(start.Add(3*ManualInterval)) {
+		t.Errorf("Expected the marker to be skipped, got %v %v", next, ok)
+	}
+
+	tw.mu.Lock()
+	b.detach(marker)
+	tw.mu.Unlock()
+}
@@ -0,0 +1,103 @@
+package timewheel
+
+import (
+	"context"
+	"time"
+)
+
+// Option configures a TimeWheel at construction time.
+type Option func(*TimeWheel)
+
+// SetOption configures a single SetWith call.
+type SetOption func(*setOptions)
+
+type setOptions struct {
+	zeroTTL     ZeroTTLPolicy
+	duplicate   DuplicatePolicy
+	annotations map[string]string
+	tags        []string
+	ctx         context.Context
+	cron        *cronSchedule
+	jitter      time.Duration
+	execTimeout time.Duration
+	parts       Key
+	many        []string
+	handle      *Timer
+}
+
+// ZeroTTLPolicy decides what Set does with an expiration <= 0.
+type ZeroTTLPolicy int
+
+const (
+	// FireAsync runs the callback on a new goroutine (default).
+	FireAsync ZeroTTLPolicy = iota
+	// FireSync runs the callback on the caller's goroutine before Set returns.
+	FireSync
+	// Reject drops the task and makes SetWith return ErrZeroTTL.
+	Reject
+	// Ignore drops the task silently.
+	Ignore
+)
+
+// DuplicatePolicy decides what Set does when the key is already scheduled.
+type DuplicatePolicy int
+
+const (
+	// DuplicateOverwrite replaces the pending task (default).
+	DuplicateOverwrite DuplicatePolicy = iota
+	// DuplicateReject keeps the pending task and makes SetWith return ErrDuplicate.
+	DuplicateReject
+	// DuplicateKeepEarliest keeps whichever task has the earlier deadline.
+	DuplicateKeepEarliest
+	// DuplicateKeepLatest keeps whichever task has the later deadline.
+	DuplicateKeepLatest
+)
+
+func WithDuplicatePolicy(p DuplicatePolicy) Option {
+	return func(tw *TimeWheel) {
+		tw.duplicate = p
+	}
+}
+
+func TaskDuplicate(p DuplicatePolicy) SetOption {
+	return func(so *setOptions) {
+		so.duplicate = p
+	}
+}
+
+func WithZeroTTLPolicy(p ZeroTTLPolicy) Option {
+	return func(tw *TimeWheel) {
+		tw.zeroTTL = p
+	}
+}
+
+func TaskZeroTTL(p ZeroTTLPolicy) SetOption {
+	return func(so *setOptions) {
+		so.zeroTTL = p
+	}
+}
+
+// TaskAnnotations attaches freeform operational context to the task. It is
+// copied and reported alongside the task wherever the wheel exposes it.
+func TaskAnnotations(annotations map[string]string) SetOption {
+	return func(so *setOptions) {
+		so.annotations = copyAnnotations(annotations)
+	}
+}
+
+// TaskContext stores ctx with the task; the context handed to the Tracer and
+// the context callback when the task fires derives from it. The task itself
+// is not cancelled when ctx is.
+func TaskContext(ctx context.Context) SetOption {
+	return func(so *setOptions) {
+		so.ctx = ctx
+	}
+}
+
+// WithImmediateDispatch fires tasks whose expiration is shorter than one base
+// interval right away instead of on the next tick.
+func WithImmediateDispatch() Option {
+	return func(tw *TimeWheel) {
+		tw.immediate = true
+	}
+}
@@ -0,0 +1,138 @@
+package timewheel
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestZeroTTLPolicy(t *testing.T) {
+	var calls int32
+	tw := NewTimeWheel(100*time.Millisecond, 10, func(string, any) {
+		atomic.AddInt32(&calls, 1)
+	}, WithZeroTTLPolicy(Reject))
+	defer tw.Stop()
+
+	if err := tw.SetWith("reject", "data", 0); !errors.Is(err, ErrZeroTTL) {
+		t.Errorf("Expected ErrZeroTTL, got %v", err)
+	}
+
+	if err := tw.SetWith("ignore", "data", 0, TaskZeroTTL(Ignore)); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if err := tw.SetWith("sync", "data", -time.Second, TaskZeroTTL(FireSync)); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected 1 synchronous callback, got %d", n)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected rejected and ignored tasks not to fire, got %d callbacks", n)
+	}
+}
+
+func TestZeroTTLPolicyPending(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil)
+	defer tw.Stop()
+
+	tw.Set("key", "data", 5*ManualInterval)
+	if err := tw.SetWith("key", "other", 0, TaskZeroTTL(Reject)); !errors.Is(err, ErrZeroTTL) {
+		t.Errorf("Expected ErrZeroTTL, got %v", err)
+	}
+	if err := tw.SetWith("key", "other", 0, TaskZeroTTL(Ignore)); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	entry, ok := tw.keyMap["key"]
+	if !ok || entry.value != "data" {
+		t.Fatal("Expected a rejected or ignored zero TTL to leave the pending task alone")
+	}
+	if lo, _, _ := tw.Remaining("key"); lo <= 0 {
+		t.Errorf("Expected the pending task to keep its deadline, got %s left", lo)
+	}
+}
+
+func TestSubTickDispatch(t *testing.T) {
+	fired := make(chan string, 1)
+	tw := NewTimeWheel(100*time.Millisecond, 10, func(k string, v any) {
+		fired <- k
+	}, WithManualMode())
+	defer tw.Stop()
+
+	tw.Set("next", "data", 10*time.Millisecond)
+	select {
+	case <-fired:
+		t.Fatal("Sub-tick task should wait for the next tick")
+	case <-time.After(20 * time.Millisecond):
+	}
+	tw.Tick()
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("Sub-tick task did not fire on the next tick")
+	}
+
+	immediate := NewTimeWheel(100*time.Millisecond, 10, func(k string, v any) {
+		fired <- k
+	}, WithManualMode(), WithImmediateDispatch())
+	defer immediate.Stop()
+
+	immediate.Set("now", "data", 10*time.Millisecond)
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("Sub-tick task should fire immediately with immediate dispatch")
+	}
+}
+
+func TestDuplicatePolicy(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil, WithDuplicatePolicy(DuplicateReject), WithExpiredChannel(4, OverflowBlock))
+	defer tw.Stop()
+
+	tw.Set("reject", "first", 2*ManualInterval)
+	if err := tw.SetWith("reject", "second", 2*ManualInterval); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Expected ErrDuplicate, got %v", err)
+	}
+
+	tw.Set("earliest", "first", 2*ManualInterval)
+	tw.SetWith("earliest", "later", 3*ManualInterval, TaskDuplicate(DuplicateKeepEarliest))
+
+	tw.Set("latest", "first", 2*ManualInterval)
+	tw.SetWith("latest", "later", 3*ManualInterval, TaskDuplicate(DuplicateKeepLatest))
+
+	tw.Set("overwrite", "first", 2*ManualInterval)
+	tw.SetWith("overwrite", "second", 2*ManualInterval, TaskDuplicate(DuplicateOverwrite))
+
+	tw.Advance(3 * ManualInterval)
+	got := make(map[string]any)
+	for i := 0; i < 4; i++ {
+		task := <-tw.Expired()
+		got[task.Key] = task.Value
+	}
+
+	expected := map[string]any{"reject": "first", "earliest": "first", "latest": "later", "overwrite": "second"}
+	for k, v := range expected {
+		if got[k] != v {
+			t.Errorf("Expected %s to fire with %v, got %v", k, v, got[k])
+		}
+	}
+}
+
+func TestZeroTTLPolicySetNX(t *testing.T) {
+	fired := false
+	tw := NewTimeWheel(0, 10, func(string, any) {
+		fired = true
+	}, WithSyncCallbacks(0), WithZeroTTLPolicy(Ignore))
+	defer tw.Stop()
+
+	if tw.SetNX("key", "data", 0) {
+		t.Error("Expected SetNX to report an ignored zero TTL as not added")
+	}
+	tw.Advance(ManualInterval)
+	if fired || tw.Stats().Pending != 0 {
+		t.Error("Expected the ignored task to be dropped")
+	}
+}
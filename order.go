@@ -0,0 +1,23 @@
+package timewheel
+
+import (
+	"cmp"
+	"slices"
+)
+
+// sortExpired puts the entries due on one tick in deadline order, and those
+// with equal deadlines in the order they were scheduled. The slot lists
+// themselves are unordered: entries join at the head and cascades mix them.
+func sortExpired(expired []*taskEntry) {
+	slices.SortFunc(expired, func(a, b *taskEntry) int {
+		if c := a.expiration.Compare(b.expiration); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.seq, b.seq)
+	})
+}
+
+func (tw *TimeWheel) nextSeq() uint64 {
+	tw.seq++
+	return tw.seq
+}
@@ -0,0 +1,39 @@
+package timewheel
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestExpirationOrder(t *testing.T) {
+	var fired []string
+	tw := NewTimeWheel(time.Second, 10, func(k string, v any) {
+		fired = append(fired, k)
+	}, WithManualMode(), WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	// All due on the same tick, in an order unrelated to insertion
+	tw.Set("c", nil, 1500*time.Millisecond)
+	tw.Set("a", nil, 1200*time.Millisecond)
+	tw.Set("b", nil, 1200*time.Millisecond)
+	tw.Set("early", nil, 1100*time.Millisecond)
+	tw.Tick()
+	if expected := []string{"early", "a", "b", "c"}; !reflect.DeepEqual(fired, expected) {
+		t.Errorf("Expected %v, got %v", expected, fired)
+	}
+
+	// Equal deadlines cascading down from an upper layer keep insertion order
+	fired = nil
+	var expected []string
+	for i := 0; i < 50; i++ {
+		key := strconv.Itoa(i)
+		tw.Set(key, nil, 95*time.Second)
+		expected = append(expected, key)
+	}
+	tw.Advance(95 * time.Second)
+	if !reflect.DeepEqual(fired, expected) {
+		t.Errorf("Expected insertion order, got %v", fired)
+	}
+}
@@ -0,0 +1,27 @@
+package timewheel
+
+import "time"
+
+// probePrefix keeps probe names apart from those passed to Maintain.
+const probePrefix = "probe:"
+
+// ScheduleProbe runs probe after the given delay, for a circuit breaker
+// testing whether a dependency has recovered. A probe that fails runs again
+// after the same delay, until one succeeds or CancelProbe is called; runs
+// never overlap. Like maintenance tasks, probes survive FlushAll and ignore
+// capacity limits and the start gate. Scheduling a name that is still
+// probing returns ErrDuplicate.
+func (tw *TimeWheel) ScheduleProbe(name string, after time.Duration, probe func() error) error {
+	key := probePrefix + name
+	return tw.Maintain(key, after, func() {
+		if probe() == nil {
+			tw.StopMaintenance(key)
+		}
+	})
+}
+
+// CancelProbe stops probing name, returning ErrNotFound if it was not. A
+// run already due may still start.
+func (tw *TimeWheel) CancelProbe(name string) error {
+	return tw.StopMaintenance(probePrefix + name)
+}
@@ -0,0 +1,87 @@
+package timewheel
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScheduleProbe(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil)
+	defer tw.Stop()
+
+	runs := make(chan int, 4)
+	healthyAfter := 3
+	attempt := 0
+	probe := func() error {
+		attempt++
+		runs <- attempt
+		if attempt < healthyAfter {
+			return errors.New("still down")
+		}
+		return nil
+	}
+	if err := tw.ScheduleProbe("db", 2*ManualInterval, probe); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.ScheduleProbe("db", 2*ManualInterval, probe); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Expected ErrDuplicate while probing, got %v", err)
+	}
+
+	for want := 1; want <= healthyAfter; want++ {
+		tw.Advance(2 * ManualInterval)
+		select {
+		case got := <-runs:
+			if got != want {
+				t.Fatalf("Expected attempt %d, got %d", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected attempt %d", want)
+		}
+	}
+
+	// Once a probe succeeds the name is free again
+	deadline := time.Now().Add(time.Second)
+	for tw.probing("db") {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected probing to stop after a success")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	tw.Advance(10 * ManualInterval)
+	select {
+	case got := <-runs:
+		t.Errorf("Expected no runs after a success, got attempt %d", got)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestCancelProbe(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil)
+	defer tw.Stop()
+
+	if err := tw.CancelProbe("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	runs := make(chan struct{}, 1)
+	tw.ScheduleProbe("api", 2*ManualInterval, func() error {
+		runs <- struct{}{}
+		return errors.New("down")
+	})
+	if err := tw.CancelProbe("api"); err != nil {
+		t.Fatal(err)
+	}
+	tw.Advance(4 * ManualInterval)
+	select {
+	case <-runs:
+		t.Error("Expected a cancelled probe not to run")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func (tw *TimeWheel) probing(name string) bool {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	_, ok := tw.pinned[probePrefix+name]
+	return ok
+}
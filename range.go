@@ -0,0 +1,42 @@
+package timewheel
+
+import (
+	"strings"
+	"time"
+)
+
+// Range calls fn for each pending task, in no particular order, until fn
+// returns false. It holds the read lock throughout, so fn must not modify
+// the wheel; collect the keys and act on them once Range returns.
+func (tw *TimeWheel) Range(fn func(key string, value any, expireAt time.Time) bool) {
+	tw.mu.RLock()
+	defer tw.mu.RUnlock()
+
+	for key, entry := range tw.keyMap {
+		if !fn(key, entry.value, entry.expiration) {
+			return
+		}
+	}
+}
+
+// Range visits the tasks in the namespace, with the key within it.
+func (ns *Namespace) Range(fn func(key string, value any, expireAt time.Time) bool) {
+	ns.tw.Range(func(key string, value any, expireAt time.Time) bool {
+		rest, ok := strings.CutPrefix(key, ns.prefix)
+		return !ok || fn(rest, value, expireAt)
+	})
+}
+
+// Range visits every shard in turn, locking one at a time.
+func (s *ShardedTimeWheel) Range(fn func(key string, value any, expireAt time.Time) bool) {
+	more := true
+	for _, shard := range s.shards {
+		shard.Range(func(key string, value any, expireAt time.Time) bool {
+			more = fn(key, value, expireAt)
+			return more
+		})
+		if !more {
+			return
+		}
+	}
+}
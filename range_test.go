@@ -0,0 +1,67 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRange(t *testing.T) {
+	tw := NewTimeWheel(0, 10, func(string, any) {})
+	defer tw.Stop()
+
+	tw.Set("a", 1, ManualInterval)
+	tw.Set("b", 2, 2*ManualInterval)
+	tw.Namespace("ns").Set("c", 3, 3*ManualInterval)
+
+	seen := map[string]any{}
+	tw.Range(func(key string, value any, expireAt time.Time) bool {
+		seen[key] = value
+		return true
+	})
+	if len(seen) != 3 || seen["a"] != 1 || seen["ns"+NamespaceSeparator+"c"] != 3 {
+		t.Errorf("Expected every task to be visited, got %v", seen)
+	}
+
+	n := 0
+	tw.Range(func(string, any, time.Time) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("Expected Range to stop after false, visited %d", n)
+	}
+
+	var keys []string
+	tw.Namespace("ns").Range(func(key string, _ any, _ time.Time) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if len(keys) != 1 || keys[0] != "c" {
+		t.Errorf("Expected the namespace to see only its key, got %v", keys)
+	}
+}
+
+func TestShardedRange(t *testing.T) {
+	s := NewShardedTimeWheel(4, 0, 10, func(string, any) {})
+	defer s.Stop()
+
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		s.Set(key, key, ManualInterval)
+	}
+	n := 0
+	s.Range(func(string, any, time.Time) bool {
+		n++
+		return true
+	})
+	if n != 5 {
+		t.Errorf("Expected 5 tasks across shards, got %d", n)
+	}
+	n = 0
+	s.Range(func(string, any, time.Time) bool {
+		n++
+		return n < 2
+	})
+	if n != 2 {
+		t.Errorf("Expected Range to stop across shards, visited %d", n)
+	}
+}
@@ -0,0 +1,104 @@
+package timewheel
+
+import (
+	"sync"
+	"time"
+)
+
+// limiterSlots is how many ticks a rate limiter's window spans, which makes
+// a window expire at most a tenth of it late.
+const limiterSlots = 10
+
+// RateLimiter allows each key a number of events per sliding window. Every
+// allowed event is a task on the limiter's wheel that gives its place back
+// when the window has passed since it happened.
+type RateLimiter struct {
+	tw     *TimeWheel
+	window time.Duration
+	limit  int
+
+	mu     sync.Mutex
+	counts map[string]int
+	limits map[string]int
+}
+
+// NewRateLimiter allows limit events per window for every key, on a wheel
+// of its own configured by opts. A window too short to split into ticks
+// still runs on a real clock, ticking every nanosecond.
+func NewRateLimiter(limit int, window time.Duration, opts ...Option) *RateLimiter {
+	rl := &RateLimiter{
+		window: window,
+		limit:  limit,
+		counts: make(map[string]int),
+		limits: make(map[string]int),
+	}
+	opts = append(opts[:len(opts):len(opts)], WithSyncCallbacks(0))
+	// A zero interval would make the wheel manual
+	interval := max(window/limiterSlots, time.Nanosecond)
+	rl.tw = NewTimeWheel(interval, limiterSlots, rl.expire, opts...)
+	return rl
+}
+
+// SetLimit overrides the limit for key; a negative limit restores the
+// default. Events already allowed keep their places.
+func (rl *RateLimiter) SetLimit(key string, limit int) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if limit < 0 {
+		delete(rl.limits, key)
+		return
+	}
+	rl.limits[key] = limit
+}
+
+// Allow reports whether an event for key fits in its window and, if so,
+// counts it. A stopped limiter, or one whose wheel refuses the event, allows
+// nothing.
+func (rl *RateLimiter) Allow(key string) bool {
+	rl.mu.Lock()
+	limit, ok := rl.limits[key]
+	if !ok {
+		limit = rl.limit
+	}
+	if rl.counts[key] >= limit {
+		rl.mu.Unlock()
+		return false
+	}
+	rl.counts[key]++
+	rl.mu.Unlock()
+
+	if _, err := rl.tw.ScheduleWith(key, rl.window); err != nil {
+		rl.expire("", key)
+		return false
+	}
+	return true
+}
+
+// Count returns how many events for key are in the current window.
+func (rl *RateLimiter) Count(key string) int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return rl.counts[key]
+}
+
+// Wheel returns the wheel behind the limiter, for its stats and options.
+func (rl *RateLimiter) Wheel() *TimeWheel {
+	return rl.tw
+}
+
+// Stop stops the wheel. Windows no longer pass, so keys stay at the count
+// they had.
+func (rl *RateLimiter) Stop() {
+	rl.tw.Stop()
+}
+
+func (rl *RateLimiter) expire(_ string, value any) {
+	key := value.(string)
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if rl.counts[key]--; rl.counts[key] <= 0 {
+		delete(rl.counts, key)
+	}
+}
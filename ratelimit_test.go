@@ -0,0 +1,70 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	rl := NewRateLimiter(2, 10*time.Millisecond, WithManualMode())
+	defer rl.Stop()
+	rl.SetLimit("vip", 3)
+
+	allowed := func(key string, n int) int {
+		got := 0
+		for i := 0; i < n; i++ {
+			if rl.Allow(key) {
+				got++
+			}
+		}
+		return got
+	}
+
+	if got := allowed("user", 1); got != 1 {
+		t.Fatalf("Expected the first event through, got %d", got)
+	}
+	rl.Wheel().Advance(5 * time.Millisecond)
+	if got := allowed("user", 3); got != 1 {
+		t.Errorf("Expected 1 more event in the window, got %d", got)
+	}
+	if got := allowed("vip", 5); got != 3 {
+		t.Errorf("Expected the per-key limit of 3, got %d", got)
+	}
+
+	// The first event leaves the window, the second is still in it
+	rl.Wheel().Advance(5 * time.Millisecond)
+	if got := rl.Count("user"); got != 1 {
+		t.Errorf("Expected 1 event left in the window, got %d", got)
+	}
+	if got := allowed("user", 2); got != 1 {
+		t.Errorf("Expected the window to slide by one event, got %d", got)
+	}
+
+	rl.SetLimit("vip", -1)
+	rl.Wheel().Advance(10 * time.Millisecond)
+	if got := allowed("vip", 3); got != 2 {
+		t.Errorf("Expected the default limit back, got %d", got)
+	}
+}
+
+func TestRateLimiterStopped(t *testing.T) {
+	rl := NewRateLimiter(2, 10*time.Millisecond, WithManualMode())
+	rl.Allow("user")
+	rl.Stop()
+
+	if rl.Allow("user") {
+		t.Error("Expected a stopped limiter to allow nothing")
+	}
+	if got := rl.Count("user"); got != 1 {
+		t.Errorf("Expected the refused event not to be counted, got %d", got)
+	}
+}
+
+func TestRateLimiterShortWindow(t *testing.T) {
+	rl := NewRateLimiter(1, 5*time.Nanosecond)
+	defer rl.Stop()
+
+	if rl.Wheel().manual {
+		t.Error("Expected a window shorter than its ticks to keep a real clock")
+	}
+}
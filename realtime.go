@@ -0,0 +1,21 @@
+package timewheel
+
+import "runtime"
+
+// WithRealtime runs the tick loop on a goroutine locked to its own OS thread
+// and, where supported, applies nice as a scheduling priority hint to that
+// thread. A zero nice leaves the priority alone; negative values usually need
+// elevated privileges and are ignored when the hint is refused.
+func WithRealtime(nice int) Option {
+	return func(tw *TimeWheel) {
+		tw.realtime = true
+		tw.nice = nice
+	}
+}
+
+func (tw *TimeWheel) lockTickThread() {
+	runtime.LockOSThread()
+	if tw.nice != 0 {
+		setThreadPriority(tw.nice)
+	}
+}
@@ -0,0 +1,7 @@
+package timewheel
+
+import "syscall"
+
+func setThreadPriority(nice int) error {
+	return syscall.Setpriority(syscall.PRIO_PROCESS, syscall.Gettid(), nice)
+}
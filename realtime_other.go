@@ -0,0 +1,7 @@
+//go:build !linux
+
+package timewheel
+
+func setThreadPriority(nice int) error {
+	return nil
+}
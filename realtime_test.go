@@ -0,0 +1,21 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRealtime(t *testing.T) {
+	fired := make(chan string, 1)
+	tw := NewTimeWheel(10*time.Millisecond, 10, func(k string, v any) {
+		fired <- k
+	}, WithRealtime(0))
+	defer tw.Stop()
+
+	tw.Set("test", "data", 30*time.Millisecond)
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("Callback did not fire on a realtime wheel")
+	}
+}
@@ -0,0 +1,350 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Client is the subset of Redis the distributed wheel needs. Dial returns a
+// dependency-free implementation; applications already using a Redis library
+// can adapt their client instead.
+type Client interface {
+	ZScore(ctx context.Context, key string, member string) (float64, bool, error)
+	// ZRangeByScore returns up to limit members with a score <= max, lowest first.
+	ZRangeByScore(ctx context.Context, key string, max float64, limit int) ([]string, error)
+	HGet(ctx context.Context, key string, field string) ([]byte, bool, error)
+	// Schedule atomically stores value as member's field in the hash values
+	// and adds member to the sorted set schedule with score.
+	Schedule(ctx context.Context, schedule, values, member string, score float64, value []byte) error
+	// Reschedule atomically gives member a new score in schedule, reporting
+	// false, and adding nothing, if member is not there.
+	Reschedule(ctx context.Context, schedule, member string, score float64) (bool, error)
+	// Unschedule atomically removes member from schedule and its field from
+	// values.
+	Unschedule(ctx context.Context, schedule, values, member string) error
+	// Claim atomically removes member from the sorted set schedule if its
+	// score is still <= max, and then removes and returns its field in the
+	// hash values. claimed is false if the member was missing or not due.
+	Claim(ctx context.Context, schedule, values, member string, max float64) (value []byte, claimed bool, err error)
+	// AcquireLock takes or renews a lock held by owner for ttl.
+	AcquireLock(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error)
+	ReleaseLock(ctx context.Context, key string, owner string) error
+	Close() error
+}
+
+// Error is an error reply from the server.
+type Error string
+
+func (e Error) Error() string {
+	return "redis: " + string(e)
+}
+
+var (
+	errProtocol = errors.New("redis: protocol error")
+	errBroken   = errors.New("redis: connection broken")
+)
+
+type conn struct {
+	mu sync.Mutex
+	c  net.Conn
+	r  *bufio.Reader
+	w  *bufio.Writer
+	// broken is set once an error may have left a reply unread, after which
+	// c is closed and replies on it would not match their commands.
+	broken bool
+	closed bool
+	// dial reconnects a broken conn; nil if it cannot.
+	dial func(ctx context.Context) (net.Conn, error)
+}
+
+// Dial connects to a Redis server speaking RESP at addr, reconnecting on the
+// next command after a timeout or IO error.
+func Dial(addr string) (Client, error) {
+	c, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	conn := newConn(c)
+	conn.dial = func(ctx context.Context) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "tcp", addr)
+	}
+	return conn, nil
+}
+
+func newConn(c net.Conn) *conn {
+	return &conn{c: c, r: bufio.NewReader(c), w: bufio.NewWriter(c)}
+}
+
+func (c *conn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return c.c.Close()
+}
+
+func (c *conn) do(ctx context.Context, args ...string) (any, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.broken {
+		if err := c.redial(ctx); err != nil {
+			return nil, err
+		}
+	}
+	deadline, _ := ctx.Deadline()
+	c.c.SetDeadline(deadline)
+
+	fmt.Fprintf(c.w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(c.w, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if err := c.w.Flush(); err != nil {
+		c.drop()
+		return nil, err
+	}
+
+	reply, err := readReply(c.r)
+	if err != nil {
+		c.drop()
+		return nil, err
+	}
+	if e, ok := reply.(Error); ok {
+		return nil, e
+	}
+	return reply, nil
+}
+
+// drop closes a conn whose command failed midway, so a late reply cannot be
+// read as the answer to the next command.
+func (c *conn) drop() {
+	c.broken = true
+	c.c.Close()
+}
+
+func (c *conn) redial(ctx context.Context) error {
+	if c.closed || c.dial == nil {
+		return errBroken
+	}
+	nc, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	c.c, c.broken = nc, false
+	c.r.Reset(nc)
+	c.w.Reset(nc)
+	return nil
+}
+
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errProtocol
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return Error(body), nil
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, errProtocol
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, errProtocol
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]any, n)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, errProtocol
+	}
+}
+
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', -1, 64)
+}
+
+func (c *conn) ZScore(ctx context.Context, key string, member string) (float64, bool, error) {
+	reply, err := c.do(ctx, "ZSCORE", key, member)
+	if err != nil || reply == nil {
+		return 0, false, err
+	}
+	b, ok := reply.([]byte)
+	if !ok {
+		return 0, false, errProtocol
+	}
+	score, err := strconv.ParseFloat(string(b), 64)
+	return score, err == nil, err
+}
+
+func (c *conn) ZRangeByScore(ctx context.Context, key string, max float64, limit int) ([]string, error) {
+	reply, err := c.do(ctx, "ZRANGEBYSCORE", key, "-inf", formatScore(max), "LIMIT", "0", strconv.Itoa(limit))
+	if err != nil {
+		return nil, err
+	}
+	items, _ := reply.([]any)
+	members := make([]string, 0, len(items))
+	for _, item := range items {
+		b, ok := item.([]byte)
+		if !ok {
+			return nil, errProtocol
+		}
+		members = append(members, string(b))
+	}
+	return members, nil
+}
+
+func (c *conn) ZRangeWithScores(ctx context.Context, key string) ([]string, []float64, error) {
+	reply, err := c.do(ctx, "ZRANGE", key, "0", "-1", "WITHSCORES")
+	if err != nil {
+		return nil, nil, err
+	}
+	items, _ := reply.([]any)
+	if len(items)%2 != 0 {
+		return nil, nil, errProtocol
+	}
+	members := make([]string, 0, len(items)/2)
+	scores := make([]float64, 0, len(items)/2)
+	for i := 0; i < len(items); i += 2 {
+		member, ok1 := items[i].([]byte)
+		b, ok2 := items[i+1].([]byte)
+		if !ok1 || !ok2 {
+			return nil, nil, errProtocol
+		}
+		score, err := strconv.ParseFloat(string(b), 64)
+		if err != nil {
+			return nil, nil, errProtocol
+		}
+		members = append(members, string(member))
+		scores = append(scores, score)
+	}
+	return members, scores, nil
+}
+
+func (c *conn) HGet(ctx context.Context, key string, field string) ([]byte, bool, error) {
+	reply, err := c.do(ctx, "HGET", key, field)
+	if err != nil || reply == nil {
+		return nil, false, err
+	}
+	b, ok := reply.([]byte)
+	return b, ok, nil
+}
+
+// scheduleScript, rescheduleScript and unscheduleScript keep the schedule
+// and the values in step: a Claim, or a crash, between two round trips would
+// leave a member without its value or bring back one already fired.
+const (
+	scheduleScript = `redis.call('HSET', KEYS[2], ARGV[1], ARGV[3])
+return redis.call('ZADD', KEYS[1], ARGV[2], ARGV[1])`
+	rescheduleScript = `if not redis.call('ZSCORE', KEYS[1], ARGV[1]) then return 0 end
+redis.call('ZADD', KEYS[1], ARGV[2], ARGV[1])
+return 1`
+	unscheduleScript = `redis.call('ZREM', KEYS[1], ARGV[1])
+return redis.call('HDEL', KEYS[2], ARGV[1])`
+)
+
+func (c *conn) Schedule(ctx context.Context, schedule, values, member string, score float64, value []byte) error {
+	_, err := c.do(ctx, "EVAL", scheduleScript, "2", schedule, values, member, formatScore(score), string(value))
+	return err
+}
+
+func (c *conn) Reschedule(ctx context.Context, schedule, member string, score float64) (bool, error) {
+	reply, err := c.do(ctx, "EVAL", rescheduleScript, "1", schedule, member, formatScore(score))
+	if err != nil {
+		return false, err
+	}
+	n, _ := reply.(int64)
+	return n > 0, nil
+}
+
+func (c *conn) Unschedule(ctx context.Context, schedule, values, member string) error {
+	_, err := c.do(ctx, "EVAL", unscheduleScript, "2", schedule, values, member)
+	return err
+}
+
+// claimScript removes a member only while its score is due, so a Set that
+// moved it later in the meantime keeps its new schedule.
+const claimScript = `local s = redis.call('ZSCORE', KEYS[1], ARGV[1])
+if not s or tonumber(s) > tonumber(ARGV[2]) then return false end
+redis.call('ZREM', KEYS[1], ARGV[1])
+local v = redis.call('HGET', KEYS[2], ARGV[1])
+redis.call('HDEL', KEYS[2], ARGV[1])
+return {1, v}`
+
+func (c *conn) Claim(ctx context.Context, schedule, values, member string, max float64) ([]byte, bool, error) {
+	reply, err := c.do(ctx, "EVAL", claimScript, "2", schedule, values, member, formatScore(max))
+	if err != nil || reply == nil {
+		return nil, false, err
+	}
+	items, ok := reply.([]any)
+	if !ok || len(items) != 2 {
+		return nil, false, errProtocol
+	}
+	value, _ := items[1].([]byte)
+	return value, true, nil
+}
+
+// renewScript and releaseScript touch the lock only while owner still holds
+// it, so a node whose lock lapsed cannot extend or delete the next holder's.
+const (
+	renewScript   = `if redis.call('GET', KEYS[1]) == ARGV[1] then return redis.call('PEXPIRE', KEYS[1], ARGV[2]) end return 0`
+	releaseScript = `if redis.call('GET', KEYS[1]) == ARGV[1] then return redis.call('DEL', KEYS[1]) end return 0`
+)
+
+// AcquireLock uses SET NX PX and renews with a compare-and-PEXPIRE script
+// when owner already holds the lock.
+func (c *conn) AcquireLock(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error) {
+	ms := strconv.FormatInt(ttl.Milliseconds(), 10)
+	reply, err := c.do(ctx, "SET", key, owner, "NX", "PX", ms)
+	if err != nil {
+		return false, err
+	}
+	if reply != nil {
+		return true, nil
+	}
+
+	reply, err = c.do(ctx, "EVAL", renewScript, "1", key, owner, ms)
+	if err != nil {
+		return false, err
+	}
+	n, _ := reply.(int64)
+	return n > 0, nil
+}
+
+func (c *conn) ReleaseLock(ctx context.Context, key string, owner string) error {
+	_, err := c.do(ctx, "EVAL", releaseScript, "1", key, owner)
+	return err
+}
@@ -0,0 +1,226 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRESP(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	c := newConn(client)
+	defer c.Close()
+
+	commands := make(chan []any, 1)
+	go func() {
+		r := bufio.NewReader(server)
+		cmd, _ := readReply(r)
+		commands <- cmd.([]any)
+		server.Write([]byte("*2\r\n$1\r\na\r\n$1\r\nb\r\n"))
+	}()
+
+	members, err := c.ZRangeByScore(context.Background(), "jobs:schedule", 1500, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(members, []string{"a", "b"}) {
+		t.Errorf("Expected [a b], got %v", members)
+	}
+
+	var got []string
+	for _, arg := range <-commands {
+		got = append(got, string(arg.([]byte)))
+	}
+	expected := []string{"ZRANGEBYSCORE", "jobs:schedule", "-inf", "1500", "LIMIT", "0", "10"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected command %v, got %v", expected, got)
+	}
+}
+
+func TestRESPScores(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	c := newConn(client)
+	defer c.Close()
+
+	go func() {
+		readReply(bufio.NewReader(server))
+		server.Write([]byte("*4\r\n$1\r\na\r\n$4\r\n1500\r\n$1\r\nb\r\n$4\r\n2500\r\n"))
+	}()
+
+	members, scores, err := c.ZRangeWithScores(context.Background(), "jobs:schedule")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(members, []string{"a", "b"}) || !reflect.DeepEqual(scores, []float64{1500, 2500}) {
+		t.Errorf("Expected [a b] at [1500 2500], got %v at %v", members, scores)
+	}
+}
+
+func TestRESPError(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	c := newConn(client)
+	defer c.Close()
+
+	go func() {
+		readReply(bufio.NewReader(server))
+		server.Write([]byte("-WRONGTYPE bad key\r\n"))
+	}()
+
+	if _, _, err := c.HGet(context.Background(), "k", "f"); err == nil || err.Error() != "redis: WRONGTYPE bad key" {
+		t.Errorf("Expected server error, got %v", err)
+	}
+}
+
+func TestRESPClaim(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	c := newConn(client)
+	defer c.Close()
+
+	commands := make(chan []any, 2)
+	go func() {
+		r := bufio.NewReader(server)
+		cmd, _ := readReply(r)
+		commands <- cmd.([]any)
+		server.Write([]byte("*2\r\n:1\r\n$5\r\nvalue\r\n"))
+		readReply(r)
+		server.Write([]byte("$-1\r\n"))
+	}()
+
+	value, claimed, err := c.Claim(context.Background(), "jobs:schedule", "jobs:values", "a", 1500)
+	if err != nil || !claimed || string(value) != "value" {
+		t.Errorf("Expected the claimed value, got %q, %v, %v", value, claimed, err)
+	}
+	var got []string
+	for _, arg := range (<-commands)[2:] {
+		got = append(got, string(arg.([]byte)))
+	}
+	expected := []string{"2", "jobs:schedule", "jobs:values", "a", "1500"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected script arguments %v, got %v", expected, got)
+	}
+
+	if _, claimed, err := c.Claim(context.Background(), "jobs:schedule", "jobs:values", "a", 1500); claimed || err != nil {
+		t.Errorf("Expected a task not due to stay unclaimed, got %v, %v", claimed, err)
+	}
+}
+
+func TestRESPLock(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	c := newConn(client)
+	defer c.Close()
+
+	commands := make(chan []string, 3)
+	go func() {
+		r := bufio.NewReader(server)
+		for _, reply := range []string{"$-1\r\n", ":0\r\n", ":1\r\n"} {
+			cmd, _ := readReply(r)
+			var args []string
+			for _, arg := range cmd.([]any) {
+				args = append(args, string(arg.([]byte)))
+			}
+			commands <- args
+			server.Write([]byte(reply))
+		}
+	}()
+
+	// Held by another owner: SET NX fails and the renewal finds a stranger
+	held, err := c.AcquireLock(context.Background(), "leader", "a", time.Second)
+	if err != nil || held {
+		t.Errorf("Expected the lock to stay with its holder, got %v, %v", held, err)
+	}
+	if err := c.ReleaseLock(context.Background(), "leader", "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	<-commands
+	for _, want := range [][]string{{"EVAL", renewScript, "1", "leader", "a", "1000"}, {"EVAL", releaseScript, "1", "leader", "a"}} {
+		if got := <-commands; !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected command %q, got %q", want, got)
+		}
+	}
+}
+
+func TestRESPSchedule(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	c := newConn(client)
+	defer c.Close()
+
+	commands := make(chan []any, 3)
+	go func() {
+		r := bufio.NewReader(server)
+		for _, reply := range []string{":1\r\n", ":0\r\n", ":1\r\n"} {
+			cmd, _ := readReply(r)
+			commands <- cmd.([]any)
+			server.Write([]byte(reply))
+		}
+	}()
+	args := func() []string {
+		var got []string
+		for _, arg := range (<-commands)[2:] {
+			got = append(got, string(arg.([]byte)))
+		}
+		return got
+	}
+
+	ctx := context.Background()
+	if err := c.Schedule(ctx, "jobs:schedule", "jobs:values", "a", 1500, []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	if got, expected := args(), []string{"2", "jobs:schedule", "jobs:values", "a", "1500", "value"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected script arguments %v, got %v", expected, got)
+	}
+	if moved, err := c.Reschedule(ctx, "jobs:schedule", "a", 2500); moved || err != nil {
+		t.Errorf("Expected a missing member not to move, got %v, %v", moved, err)
+	}
+	if got, expected := args(), []string{"1", "jobs:schedule", "a", "2500"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected script arguments %v, got %v", expected, got)
+	}
+	if err := c.Unschedule(ctx, "jobs:schedule", "jobs:values", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if got, expected := args(), []string{"2", "jobs:schedule", "jobs:values", "a"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected script arguments %v, got %v", expected, got)
+	}
+}
+
+func TestRESPBrokenConn(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	c := newConn(client)
+	defer c.Close()
+
+	// The first server never answers in time; the redialed one does
+	go readReply(bufio.NewReader(server))
+	fresh, redialed := net.Pipe()
+	defer fresh.Close()
+	c.dial = func(context.Context) (net.Conn, error) { return redialed, nil }
+	go func() {
+		readReply(bufio.NewReader(fresh))
+		fresh.Write([]byte("$5\r\nfresh\r\n"))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, _, err := c.HGet(ctx, "jobs:values", "late"); err == nil {
+		t.Fatal("Expected the unanswered command to time out")
+	}
+	value, ok, err := c.HGet(context.Background(), "jobs:values", "a")
+	if err != nil || !ok || string(value) != "fresh" {
+		t.Errorf("Expected the next command to run on a new connection, got %q, %v, %v", value, ok, err)
+	}
+
+	c.dial = nil
+	c.drop()
+	if _, _, err := c.HGet(context.Background(), "jobs:values", "a"); err != errBroken {
+		t.Errorf("Expected a broken conn that cannot redial to fail, got %v", err)
+	}
+}
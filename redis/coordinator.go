@@ -0,0 +1,27 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/nzai/timewheel"
+)
+
+type coordinator struct {
+	client Client
+	key    string
+}
+
+// NewCoordinator elects a leader among in-memory wheels through a Redis lock
+// at key, for use with timewheel.WithCoordinator.
+func NewCoordinator(client Client, key string) timewheel.Coordinator {
+	return &coordinator{client: client, key: key}
+}
+
+func (c *coordinator) TryLead(ctx context.Context, id string, ttl time.Duration) (bool, error) {
+	return c.client.AcquireLock(ctx, c.key, id, ttl)
+}
+
+func (c *coordinator) Resign(ctx context.Context, id string) error {
+	return c.client.ReleaseLock(ctx, c.key, id)
+}
@@ -0,0 +1,25 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCoordinator(t *testing.T) {
+	client := newMemClient()
+	a := NewCoordinator(client, "jobs:leader")
+	b := NewCoordinator(client, "jobs:leader")
+	ctx := context.Background()
+
+	if ok, err := a.TryLead(ctx, "a", time.Minute); !ok || err != nil {
+		t.Fatalf("Expected a to lead, got %v, %v", ok, err)
+	}
+	if ok, _ := b.TryLead(ctx, "b", time.Minute); ok {
+		t.Fatal("Expected b to follow while a holds the lock")
+	}
+	a.Resign(ctx, "a")
+	if ok, _ := b.TryLead(ctx, "b", time.Minute); !ok {
+		t.Fatal("Expected b to lead after a resigned")
+	}
+}
@@ -0,0 +1,108 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrFollower is returned by Set, Delete and Move on a follower, which only
+// mirrors the schedule.
+var ErrFollower = errors.New("redis: wheel is a follower")
+
+// RangeScorer lists a whole sorted set with its scores, lowest first. The
+// client returned by Dial implements it; a follower on a client that does
+// not falls back to a ZScore per task on every poll.
+type RangeScorer interface {
+	ZRangeWithScores(ctx context.Context, key string) (members []string, scores []float64, err error)
+}
+
+// WithFollower starts the wheel as a read-only replica: it never takes the
+// leader lock or fires, and rejects Set, Delete and Move with ErrFollower,
+// but refreshes a copy of the shared schedule on every poll. Schedule reads
+// the copy; Promote makes the instance a regular one.
+func WithFollower() Option {
+	return func(w *Wheel) {
+		w.following.Store(true)
+	}
+}
+
+func (w *Wheel) IsFollower() bool {
+	return w.following.Load()
+}
+
+// Promote turns a follower into a regular instance that contends for
+// leadership, polling right away rather than at the next interval. It
+// reports whether the wheel was a follower.
+func (w *Wheel) Promote() bool {
+	if !w.following.CompareAndSwap(true, false) {
+		return false
+	}
+	w.mirrorMu.Lock()
+	w.mirror = nil
+	w.mirrorMu.Unlock()
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// Schedule returns the deadlines a follower saw at its last poll, by key,
+// or nil once promoted or if the wheel is not a follower.
+func (w *Wheel) Schedule() map[string]time.Time {
+	w.mirrorMu.Lock()
+	defer w.mirrorMu.Unlock()
+	if w.mirror == nil {
+		return nil
+	}
+	schedule := make(map[string]time.Time, len(w.mirror))
+	for key, at := range w.mirror {
+		schedule[key] = at
+	}
+	return schedule
+}
+
+// follow refreshes the mirrored schedule.
+func (w *Wheel) follow(ctx context.Context) {
+	members, scores, err := w.scores(ctx)
+	if err != nil {
+		w.report(err)
+		return
+	}
+	mirror := make(map[string]time.Time, len(members))
+	for i, key := range members {
+		mirror[key] = time.UnixMilli(int64(scores[i]))
+	}
+
+	w.mirrorMu.Lock()
+	defer w.mirrorMu.Unlock()
+	if w.following.Load() {
+		w.mirror = mirror
+	}
+}
+
+func (w *Wheel) scores(ctx context.Context) ([]string, []float64, error) {
+	if rs, ok := w.client.(RangeScorer); ok {
+		return rs.ZRangeWithScores(ctx, w.scheduleKey())
+	}
+	members, err := w.client.ZRangeByScore(ctx, w.scheduleKey(), math.Inf(1), math.MaxInt32)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Tasks that fire between the two calls are left out
+	found := members[:0]
+	var scores []float64
+	for _, key := range members {
+		score, ok, err := w.client.ZScore(ctx, w.scheduleKey(), key)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			found = append(found, key)
+			scores = append(scores, score)
+		}
+	}
+	return found, scores, nil
+}
@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFollower(t *testing.T) {
+	client := newMemClient()
+	fired := make(chan string, 4)
+	callback := func(k string, v any) {
+		fired <- k
+	}
+
+	leader := New(client, "jobs", 10*time.Millisecond, callback, WithInstanceID("leader"))
+	defer leader.Stop()
+	follower := New(client, "jobs", 10*time.Millisecond, callback, WithInstanceID("follower"), WithFollower())
+	defer follower.Stop()
+
+	if err := follower.Set("a", "data", time.Second); !errors.Is(err, ErrFollower) {
+		t.Errorf("Expected a follower to reject Set, got %v", err)
+	}
+	leader.Set("a", "data", 200*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	if at, ok := follower.Schedule()["a"]; !ok || time.Until(at) <= 0 {
+		t.Errorf("Expected the follower to mirror a's deadline, got %v", follower.Schedule())
+	}
+	if follower.IsLeader() {
+		t.Error("Expected a follower never to lead")
+	}
+
+	// The leader dies before a is due; the follower takes over once promoted
+	leader.Stop()
+	if !follower.Promote() || follower.IsFollower() {
+		t.Fatal("Expected Promote to turn the follower into a regular instance")
+	}
+	if follower.Promote() {
+		t.Error("Expected a second Promote to report false")
+	}
+	if follower.Schedule() != nil {
+		t.Error("Expected no mirror after promotion")
+	}
+	select {
+	case got := <-fired:
+		if got != "a" {
+			t.Errorf("Expected a to fire, got %s", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Task did not fire after promotion")
+	}
+	if !follower.IsLeader() {
+		t.Error("Expected the promoted instance to lead")
+	}
+}
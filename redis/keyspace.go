@@ -0,0 +1,45 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/nzai/timewheel"
+)
+
+// Publisher sends a pub/sub message. The client returned by Dial implements
+// it.
+type Publisher interface {
+	Publish(ctx context.Context, channel string, message string) error
+}
+
+// KeyspaceEvents returns a fire hook, for timewheel.WithOnFire, that
+// announces each expiration the way Redis keyspace notifications do: the key
+// on __keyevent@<db>__:expired and "expired" on __keyspace@<db>__:<key>.
+// Consumers of Redis expiry events can then be pointed at the wheel
+// unchanged. The hook publishes on the tick goroutine; onError, if set,
+// receives publish failures.
+func KeyspaceEvents(pub Publisher, db int, onError func(error)) func(timewheel.TaskInfo) {
+	n := strconv.Itoa(db)
+	keyevent := "__keyevent@" + n + "__:expired"
+	keyspace := "__keyspace@" + n + "__:"
+
+	return func(task timewheel.TaskInfo) {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		for _, err := range []error{
+			pub.Publish(ctx, keyevent, task.Key),
+			pub.Publish(ctx, keyspace+task.Key, "expired"),
+		} {
+			if err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+func (c *conn) Publish(ctx context.Context, channel string, message string) error {
+	_, err := c.do(ctx, "PUBLISH", channel, message)
+	return err
+}
@@ -0,0 +1,44 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nzai/timewheel"
+)
+
+type message struct {
+	channel, payload string
+}
+
+type recordingPublisher chan message
+
+func (p recordingPublisher) Publish(ctx context.Context, channel string, payload string) error {
+	p <- message{channel, payload}
+	return nil
+}
+
+func TestKeyspaceEvents(t *testing.T) {
+	pub := make(recordingPublisher, 2)
+	tw := timewheel.NewTimeWheel(0, 10, nil, timewheel.WithOnFire(KeyspaceEvents(pub, 3, nil)))
+	defer tw.Stop()
+
+	tw.Set("session:42", "data", timewheel.ManualInterval)
+	tw.Tick()
+
+	want := []message{
+		{"__keyevent@3__:expired", "session:42"},
+		{"__keyspace@3__:session:42", "expired"},
+	}
+	for _, w := range want {
+		select {
+		case got := <-pub:
+			if got != w {
+				t.Errorf("Expected %+v, got %+v", w, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected %+v to be published", w)
+		}
+	}
+}
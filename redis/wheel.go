@@ -0,0 +1,281 @@
+// Package redis provides a distributed wheel with the TimeWheel Set, Delete
+// and Move API whose schedule lives in a Redis sorted set, so timers survive
+// process crashes and several instances can share one schedule. Any instance
+// may schedule; only the instance holding the leader lock fires.
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nzai/timewheel"
+)
+
+const (
+	defaultBatchSize = 100
+	requestTimeout   = 5 * time.Second
+)
+
+type Wheel struct {
+	client   Client
+	name     string
+	interval time.Duration
+	callback func(key string, value any)
+	id       string
+	lockTTL  time.Duration
+	batch    int
+	onError  func(error)
+	codec    timewheel.Codec
+	leader   atomic.Bool
+	quit     chan struct{}
+	wake     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+
+	following atomic.Bool
+	mirrorMu  sync.Mutex
+	mirror    map[string]time.Time
+}
+
+type Option func(*Wheel)
+
+// WithInstanceID names this instance in the leader lock. Defaults to a random ID.
+func WithInstanceID(id string) Option {
+	return func(w *Wheel) {
+		w.id = id
+	}
+}
+
+// WithLockTTL sets how long leadership survives without renewal, which bounds
+// failover time after the leader dies. Defaults to three polling intervals.
+func WithLockTTL(ttl time.Duration) Option {
+	return func(w *Wheel) {
+		w.lockTTL = ttl
+	}
+}
+
+// WithBatchSize caps the number of due tasks fired per poll.
+func WithBatchSize(n int) Option {
+	return func(w *Wheel) {
+		w.batch = n
+	}
+}
+
+// WithErrorHandler receives Redis errors from the polling loop, which
+// otherwise retries silently on the next interval.
+func WithErrorHandler(h func(error)) Option {
+	return func(w *Wheel) {
+		w.onError = h
+	}
+}
+
+// WithCodec encodes values with c instead of as JSON. Every instance sharing
+// the schedule must use the same codec.
+func WithCodec(c timewheel.Codec) Option {
+	return func(w *Wheel) {
+		w.codec = c
+	}
+}
+
+// New starts a wheel storing its schedule under keys prefixed with name and
+// polling for due tasks every interval. Values are stored as JSON unless
+// WithCodec says otherwise, so the callback receives them decoded into basic
+// Go types.
+func New(client Client, name string, interval time.Duration, callback func(key string, value any), opts ...Option) *Wheel {
+	w := &Wheel{
+		client:   client,
+		name:     name,
+		interval: interval,
+		callback: callback,
+		id:       randomID(),
+		lockTTL:  3 * interval,
+		batch:    defaultBatchSize,
+		codec:    timewheel.JSONCodec(),
+		quit:     make(chan struct{}),
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if w.following.Load() {
+		w.mirror = make(map[string]time.Time)
+	}
+
+	go w.run()
+	return w
+}
+
+func randomID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (w *Wheel) scheduleKey() string {
+	return w.name + ":schedule"
+}
+
+func (w *Wheel) valuesKey() string {
+	return w.name + ":values"
+}
+
+func (w *Wheel) leaderKey() string {
+	return w.name + ":leader"
+}
+
+func score(t time.Time) float64 {
+	return float64(t.UnixMilli())
+}
+
+func (w *Wheel) stopped() bool {
+	select {
+	case <-w.quit:
+		return true
+	default:
+		return false
+	}
+}
+
+func (w *Wheel) Set(key string, value any, expiration time.Duration) error {
+	if w.stopped() {
+		return timewheel.ErrStopped
+	}
+	if w.following.Load() {
+		return ErrFollower
+	}
+	data, err := w.codec.Encode(value)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	return w.client.Schedule(ctx, w.scheduleKey(), w.valuesKey(), key, score(time.Now().Add(expiration)), data)
+}
+
+func (w *Wheel) Delete(key string) error {
+	if w.following.Load() {
+		return ErrFollower
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	return w.client.Unschedule(ctx, w.scheduleKey(), w.valuesKey(), key)
+}
+
+// Move reschedules an existing task, returning timewheel.ErrNotFound when
+// the key is not scheduled.
+func (w *Wheel) Move(key string, expiration time.Duration) error {
+	if w.following.Load() {
+		return ErrFollower
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	// Checked and updated in one step, so a task claimed meanwhile stays gone
+	moved, err := w.client.Reschedule(ctx, w.scheduleKey(), key, score(time.Now().Add(expiration)))
+	if err != nil {
+		return err
+	}
+	if !moved {
+		return timewheel.ErrNotFound
+	}
+	return nil
+}
+
+func (w *Wheel) IsLeader() bool {
+	return w.leader.Load()
+}
+
+// Stop ends polling and gives up leadership so another instance can take over.
+func (w *Wheel) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.quit)
+		<-w.done
+
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+		if w.leader.Load() {
+			w.client.ReleaseLock(ctx, w.leaderKey(), w.id)
+			w.leader.Store(false)
+		}
+	})
+}
+
+func (w *Wheel) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.poll()
+		select {
+		case <-ticker.C:
+		case <-w.wake:
+		case <-w.quit:
+			return
+		}
+	}
+}
+
+func (w *Wheel) poll() {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	if w.following.Load() {
+		w.follow(ctx)
+		return
+	}
+	leader, err := w.client.AcquireLock(ctx, w.leaderKey(), w.id, w.lockTTL)
+	w.leader.Store(leader && err == nil)
+	if err != nil {
+		w.report(err)
+		return
+	}
+	if !leader {
+		return
+	}
+
+	now := score(time.Now())
+	due, err := w.client.ZRangeByScore(ctx, w.scheduleKey(), now, w.batch)
+	if err != nil {
+		w.report(err)
+		return
+	}
+	for _, key := range due {
+		w.fire(ctx, key, now)
+	}
+}
+
+// fire claims a due task by removing it from the schedule, together with its
+// value, while it is still due; only the caller whose claim succeeds runs the
+// callback, so a task fires at most once and never for a later schedule.
+func (w *Wheel) fire(ctx context.Context, key string, due float64) {
+	data, claimed, err := w.client.Claim(ctx, w.scheduleKey(), w.valuesKey(), key, due)
+	if err != nil || !claimed {
+		w.report(err)
+		return
+	}
+
+	var value any
+	if len(data) > 0 {
+		if value, err = w.codec.Decode(data); err != nil {
+			w.report(err)
+		}
+	}
+	if w.callback != nil {
+		go w.callback(key, value)
+	}
+}
+
+func (w *Wheel) report(err error) {
+	if err != nil && w.onError != nil {
+		w.onError(err)
+	}
+}
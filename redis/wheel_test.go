@@ -0,0 +1,270 @@
+package redis
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nzai/timewheel"
+)
+
+// memClient is an in-memory Client shared by the wheels under test.
+type memClient struct {
+	mu      sync.Mutex
+	zsets   map[string]map[string]float64
+	hashes  map[string]map[string][]byte
+	locks   map[string]string
+	expires map[string]time.Time
+}
+
+func newMemClient() *memClient {
+	return &memClient{
+		zsets:   make(map[string]map[string]float64),
+		hashes:  make(map[string]map[string][]byte),
+		locks:   make(map[string]string),
+		expires: make(map[string]time.Time),
+	}
+}
+
+func (m *memClient) ZScore(ctx context.Context, key string, member string) (float64, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	score, ok := m.zsets[key][member]
+	return score, ok, nil
+}
+
+func (m *memClient) ZRangeByScore(ctx context.Context, key string, max float64, limit int) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var members []string
+	for member, score := range m.zsets[key] {
+		if score <= max {
+			members = append(members, member)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return m.zsets[key][members[i]] < m.zsets[key][members[j]]
+	})
+	if len(members) > limit {
+		members = members[:limit]
+	}
+	return members, nil
+}
+
+func (m *memClient) HGet(ctx context.Context, key string, field string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.hashes[key][field]
+	return v, ok, nil
+}
+
+func (m *memClient) Schedule(ctx context.Context, schedule, values, member string, score float64, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.hashes[values] == nil {
+		m.hashes[values] = make(map[string][]byte)
+	}
+	if m.zsets[schedule] == nil {
+		m.zsets[schedule] = make(map[string]float64)
+	}
+	m.hashes[values][member] = value
+	m.zsets[schedule][member] = score
+	return nil
+}
+
+func (m *memClient) Reschedule(ctx context.Context, schedule, member string, score float64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.zsets[schedule][member]; !ok {
+		return false, nil
+	}
+	m.zsets[schedule][member] = score
+	return true, nil
+}
+
+func (m *memClient) Unschedule(ctx context.Context, schedule, values, member string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.zsets[schedule], member)
+	delete(m.hashes[values], member)
+	return nil
+}
+
+func (m *memClient) Claim(ctx context.Context, schedule, values, member string, max float64) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if score, ok := m.zsets[schedule][member]; !ok || score > max {
+		return nil, false, nil
+	}
+	delete(m.zsets[schedule], member)
+	value := m.hashes[values][member]
+	delete(m.hashes[values], member)
+	return value, true, nil
+}
+
+func (m *memClient) AcquireLock(ctx context.Context, key string, owner string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if holder, ok := m.locks[key]; ok && holder != owner && time.Now().Before(m.expires[key]) {
+		return false, nil
+	}
+	m.locks[key] = owner
+	m.expires[key] = time.Now().Add(ttl)
+	return true, nil
+}
+
+func (m *memClient) ReleaseLock(ctx context.Context, key string, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.locks[key] == owner {
+		delete(m.locks, key)
+	}
+	return nil
+}
+
+func (m *memClient) Close() error {
+	return nil
+}
+
+func TestDistributedWheel(t *testing.T) {
+	client := newMemClient()
+	fired := make(chan string, 4)
+	callback := func(k string, v any) {
+		fired <- k + "=" + v.(string)
+	}
+
+	first := New(client, "jobs", 10*time.Millisecond, callback, WithInstanceID("first"))
+	defer first.Stop()
+	time.Sleep(30 * time.Millisecond)
+	second := New(client, "jobs", 10*time.Millisecond, callback, WithInstanceID("second"))
+	defer second.Stop()
+
+	if err := second.Set("a", "data", 50*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	second.Set("b", "data", 50*time.Millisecond)
+	second.Delete("b")
+	if err := second.Move("missing", time.Second); !errors.Is(err, timewheel.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	select {
+	case got := <-fired:
+		if got != "a=data" {
+			t.Errorf("Expected a=data, got %s", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Task did not fire")
+	}
+	if !first.IsLeader() || second.IsLeader() {
+		t.Error("Expected the first instance to lead")
+	}
+
+	// Failover: the second instance takes over once the leader stops
+	first.Stop()
+	second.Set("c", "data", 20*time.Millisecond)
+	select {
+	case got := <-fired:
+		if got != "c=data" {
+			t.Errorf("Expected c=data, got %s", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Task did not fire after failover")
+	}
+
+	select {
+	case got := <-fired:
+		t.Errorf("Unexpected extra fire %s", got)
+	case <-time.After(30 * time.Millisecond):
+	}
+}
+
+type job struct {
+	Name string
+}
+
+func TestWheelCodec(t *testing.T) {
+	gob.Register(job{})
+	fired := make(chan any, 1)
+	w := New(newMemClient(), "jobs", 10*time.Millisecond, func(k string, v any) {
+		fired <- v
+	}, WithCodec(timewheel.GobCodec()))
+	defer w.Stop()
+
+	if err := w.Set("a", job{Name: "report"}, 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case v := <-fired:
+		if v != (job{Name: "report"}) {
+			t.Errorf("Expected the job to round-trip, got %#v", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Task did not fire")
+	}
+}
+
+// raceClient runs between once, after a poll has listed due tasks but
+// before it claims them.
+type raceClient struct {
+	*memClient
+	once    sync.Once
+	between func()
+}
+
+func (c *raceClient) ZRangeByScore(ctx context.Context, key string, max float64, limit int) ([]string, error) {
+	due, err := c.memClient.ZRangeByScore(ctx, key, max, limit)
+	if len(due) > 0 {
+		c.once.Do(c.between)
+	}
+	return due, err
+}
+
+func TestWheelClaimRescheduled(t *testing.T) {
+	client := &raceClient{memClient: newMemClient()}
+	fired := make(chan string, 1)
+	w := New(client, "jobs", 10*time.Millisecond, func(k string, v any) {
+		fired <- k + "=" + v.(string)
+	})
+	defer w.Stop()
+	client.between = func() { w.Set("a", "later", time.Hour) }
+
+	w.Set("a", "now", 10*time.Millisecond)
+	select {
+	case got := <-fired:
+		t.Fatalf("Expected the rescheduled task not to fire, got %s", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if _, ok, _ := client.ZScore(context.Background(), "jobs:schedule", "a"); !ok {
+		t.Error("Expected the new schedule to survive the claim")
+	}
+	if v, ok, _ := client.HGet(context.Background(), "jobs:values", "a"); !ok || len(v) == 0 {
+		t.Error("Expected the new value to survive the claim")
+	}
+}
+
+func TestWheelMoveFired(t *testing.T) {
+	client := newMemClient()
+	fired := make(chan string, 2)
+	w := New(client, "jobs", 10*time.Millisecond, func(k string, v any) {
+		fired <- k
+	})
+	defer w.Stop()
+
+	w.Set("a", "data", 10*time.Millisecond)
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("Task did not fire")
+	}
+	if err := w.Move("a", 10*time.Millisecond); !errors.Is(err, timewheel.ErrNotFound) {
+		t.Errorf("Expected a fired task not to move, got %v", err)
+	}
+	if _, ok, _ := client.ZScore(context.Background(), "jobs:schedule", "a"); ok {
+		t.Error("Expected Move not to bring back a fired task")
+	}
+}
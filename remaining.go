@@ -0,0 +1,52 @@
+package timewheel
+
+import (
+	"math"
+	"time"
+)
+
+// Remaining reports the window [lo, hi] in which the task will fire. A task
+// fires on the first tick within one base interval of its deadline, so on a
+// manual wheel, whose ticks are known, lo equals hi; otherwise the window spans
+// the tick before the deadline up to one tick of ticker lateness after it.
+// Stalls beyond that, Manager budgets and holds are not bounded: a held task
+// reports an unbounded hi.
+func (tw *TimeWheel) Remaining(key string) (lo, hi time.Duration, ok bool) {
+	tw.mu.RLock()
+	defer tw.mu.RUnlock()
+
+	entry, exists := tw.lookup(key)
+	if !exists {
+		return 0, 0, false
+	}
+
+	now := tw.now()
+	if tw.manual {
+		// The first tick later than one interval before the deadline
+		ticks := max(entry.expiration.Sub(tw.lastTick)/tw.baseInterval, 1)
+		lo = tw.lastTick.Add(ticks * tw.baseInterval).Sub(now)
+		hi = lo
+	} else if entry.timer != nil {
+		// On its own timer: only scheduling latency remains
+		lo = clampDuration(entry.expiration.Sub(now))
+		hi = lo
+	} else {
+		lo = clampDuration(entry.expiration.Sub(now) - tw.baseInterval)
+		hi = clampDuration(entry.expiration.Sub(now)) + tw.baseInterval
+	}
+	if entry.held {
+		hi = math.MaxInt64
+	}
+	if _, parked := tw.parked[entry.key]; parked {
+		// Parked: overdue and fires on release
+		lo = 0
+	}
+	return lo, hi, true
+}
+
+func clampDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
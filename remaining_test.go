@@ -0,0 +1,50 @@
+package timewheel
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRemaining(t *testing.T) {
+	var start time.Time
+	var tw *TimeWheel
+	fired := make(map[string]time.Duration)
+	tw = NewTimeWheel(time.Second, 10, func(k string, v any) {
+		fired[k] = tw.virtualNow.Sub(start)
+	}, WithManualMode(), WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	tw.Advance(1500 * time.Millisecond)
+	tw.Set("whole", "data", 3500*time.Millisecond) // deadline on a tick
+	tw.Set("sub", "data", 2*time.Second)           // fires half a tick early
+	tw.Set("long", "data", 95*time.Second)
+
+	for key, want := range map[string]time.Duration{
+		"whole": 3500 * time.Millisecond,
+		"sub":   1500 * time.Millisecond,
+		"long":  94500 * time.Millisecond,
+	} {
+		lo, hi, ok := tw.Remaining(key)
+		if !ok || lo != want || hi != want {
+			t.Errorf("Expected %s to report [%s, %s], got [%s, %s] %v", key, want, want, lo, hi, ok)
+		}
+	}
+
+	start = tw.now()
+	tw.Advance(100 * time.Second)
+	for key, want := range map[string]time.Duration{"whole": 3500 * time.Millisecond, "sub": 1500 * time.Millisecond, "long": 94500 * time.Millisecond} {
+		if fired[key] != want {
+			t.Errorf("Expected %s to fire after %s, got %s", key, want, fired[key])
+		}
+	}
+
+	tw.Set("held", "data", time.Minute)
+	tw.Hold("held")
+	if _, hi, _ := tw.Remaining("held"); hi != math.MaxInt64 {
+		t.Errorf("Expected a held task to have no upper bound, got %s", hi)
+	}
+	if _, _, ok := tw.Remaining("missing"); ok {
+		t.Error("Expected ok to be false for a missing key")
+	}
+}
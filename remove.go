@@ -0,0 +1,115 @@
+package timewheel
+
+import "sort"
+
+// Reason says why a task left the wheel.
+type Reason int
+
+const (
+	// ReasonExpired: the task fired and its callback returned.
+	ReasonExpired Reason = iota
+	// ReasonDeleted: Delete or DeleteK cancelled the task.
+	ReasonDeleted
+	// ReasonReplaced: a Set on the key superseded the task's value.
+	ReasonReplaced
+	// ReasonFlushed: FlushAll or a selective flush cancelled the task.
+	ReasonFlushed
+	// ReasonEvicted: the task was cancelled to stay within the capacity.
+	ReasonEvicted
+	// ReasonStopped: the wheel stopped with the task still pending.
+	ReasonStopped
+	// ReasonTaken: Take claimed the task.
+	ReasonTaken
+	// ReasonAcked: Ack acknowledged the task's delivery.
+	ReasonAcked
+)
+
+func (r Reason) String() string {
+	switch r {
+	case ReasonExpired:
+		return "expired"
+	case ReasonDeleted:
+		return "deleted"
+	case ReasonReplaced:
+		return "replaced"
+	case ReasonFlushed:
+		return "flushed"
+	case ReasonEvicted:
+		return "evicted"
+	case ReasonStopped:
+		return "stopped"
+	case ReasonTaken:
+		return "taken"
+	case ReasonAcked:
+		return "acked"
+	default:
+		return "Reason(unknown)"
+	}
+}
+
+// WithOnRemove calls h once for every task that ends, with the reason it
+// ended. An expired task is reported after its callback returns; one that
+// is retried or recurs by cron stays in the wheel and is not reported.
+func WithOnRemove(h func(key string, value any, reason Reason)) Option {
+	return func(tw *TimeWheel) {
+		tw.hooks.onRemove = h
+	}
+}
+
+// removed queues the remove hook for an entry leaving the wheel under the lock.
+func (tw *TimeWheel) removed(entry *taskEntry, reason Reason) {
+	tw.noteEmpty()
+	tw.remember(entry, reason, tw.now())
+	if tw.hooks.onRemove == nil {
+		return
+	}
+	tw.events = append(tw.events, hookEvent{kind: hookRemove, info: entry.info(), reason: reason})
+}
+
+// finish records that a fired entry is done with, outside the lock.
+func (tw *TimeWheel) finish(entry *taskEntry, retried bool) {
+	tw.journal(hookFire, entry)
+	if tw.hooks.onRemove != nil && entry.cron == nil && !retried && !entry.awaitingAck {
+		tw.hooks.onRemove(entry.key, entry.value, ReasonExpired)
+	}
+	tw.land(entry)
+}
+
+// dropPending reports every task still pending when the wheel stops and,
+// if drain is set, returns them in deadline order.
+func (tw *TimeWheel) dropPending(drain bool) []TaskInfo {
+	if tw.hooks.onRemove == nil && !drain {
+		return nil
+	}
+	tw.mu.Lock()
+	defer tw.unlock()
+
+	var pending []TaskInfo
+	for _, entry := range tw.keyMap {
+		tw.removed(entry, ReasonStopped)
+		if drain {
+			pending = append(pending, entry.info())
+		}
+	}
+	// Tasks deferred by the Manager have left the wheel but not fired yet
+	for _, entry := range tw.backlog {
+		if entry.maint != nil {
+			continue
+		}
+		tw.removed(entry, ReasonStopped)
+		if drain {
+			pending = append(pending, entry.info())
+		}
+	}
+	if drain {
+		tw.clearTasks()
+		for _, entry := range tw.backlog {
+			tw.land(entry)
+		}
+		tw.backlog = nil
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Expiration.Before(pending[j].Expiration)
+	})
+	return pending
+}
@@ -0,0 +1,67 @@
+package timewheel
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOnRemove(t *testing.T) {
+	var mu sync.Mutex
+	reasons := make(map[string]Reason)
+	values := make(map[string]any)
+	tw := NewTimeWheel(0, 10, func(string, any) {}, WithSyncCallbacks(0), WithCapacity(3, EvictSoonest),
+		WithOnRemove(func(key string, value any, reason Reason) {
+			mu.Lock()
+			defer mu.Unlock()
+			reasons[key] = reason
+			values[key] = value
+		}))
+
+	tw.Set("expired", "data", ManualInterval)
+	tw.Set("deleted", "data", time.Second)
+	tw.Set("replaced", "old", time.Second)
+	tw.Tick()
+	tw.Delete("deleted")
+	tw.Set("replaced", "new", time.Second)
+
+	tw.Set("evicted", "data", ManualInterval*2)
+	tw.Set("filler", "data", time.Minute)
+	tw.Set("stopped", "data", time.Minute) // evicts the soonest
+	tw.Stop()
+
+	expected := map[string]Reason{
+		"expired":  ReasonExpired,
+		"deleted":  ReasonDeleted,
+		"replaced": ReasonStopped,
+		"evicted":  ReasonEvicted,
+		"filler":   ReasonStopped,
+		"stopped":  ReasonStopped,
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for key, want := range expected {
+		if got, ok := reasons[key]; !ok || got != want {
+			t.Errorf("Expected %s to be removed as %s, got %s (%v)", key, want, got, ok)
+		}
+	}
+	if values["replaced"] != "new" {
+		t.Errorf("Expected the replacement to be the one stopped, got %v", values["replaced"])
+	}
+}
+
+func TestOnRemoveReplacedAndFlushed(t *testing.T) {
+	var got []string
+	tw := NewTimeWheel(0, 10, nil, WithOnRemove(func(key string, value any, reason Reason) {
+		got = append(got, key+"="+value.(string)+":"+reason.String())
+	}))
+	defer tw.Stop()
+
+	tw.Set("key", "old", time.Second)
+	tw.Set("key", "new", time.Second)
+	tw.FlushAll()
+
+	if len(got) != 2 || got[0] != "key=old:replaced" || got[1] != "key=new:flushed" {
+		t.Errorf("Expected the old value replaced and the new one flushed, got %v", got)
+	}
+}
@@ -0,0 +1,52 @@
+package timewheel
+
+import (
+	"sync"
+	"time"
+)
+
+const resolutionSamples = 3
+
+// measureTimerResolution estimates the granularity of the system timer as the
+// shortest observed duration of a minimal sleep.
+func measureTimerResolution() time.Duration {
+	best := time.Duration(-1)
+	for i := 0; i < resolutionSamples; i++ {
+		start := time.Now()
+		time.Sleep(time.Microsecond)
+		if d := time.Since(start); best < 0 || d < best {
+			best = d
+		}
+	}
+	return best
+}
+
+// systemResolution measures the resolution once per process, when a wheel
+// first adjusts to it or reports it, so the probe's sleeps stay off the path
+// of a wheel's construction and first ticks.
+var systemResolution = sync.OnceValue(measureTimerResolution)
+
+// timerResolution is the timer resolution the wheel runs on; zero for a
+// manual or simulated wheel.
+func (tw *TimeWheel) timerResolution() time.Duration {
+	if tw.manual || tw.simulated() {
+		return 0
+	}
+	return systemResolution()
+}
+
+// WithResolutionAdjust rounds the base interval up to a multiple of the
+// measured timer resolution when the requested one is finer than the
+// platform can deliver, so slots do not promise accuracy the ticker lacks.
+func WithResolutionAdjust() Option {
+	return func(tw *TimeWheel) {
+		tw.adjustResolution = true
+	}
+}
+
+func adjustedInterval(base, resolution time.Duration) time.Duration {
+	if resolution <= base {
+		return base
+	}
+	return (resolution + base - 1) / base * base
+}
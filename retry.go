@@ -0,0 +1,159 @@
+package timewheel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// ErrCallback is an expiration callback that can report failure.
+type ErrCallback func(key string, value any) error
+
+// ContextErrCallback is an ErrCallback that also receives the task's
+// context, from which Attempt and TagsFromContext read.
+type ContextErrCallback func(ctx context.Context, key string, value any) error
+
+// WithErrCallback registers a callback whose error decides what happens
+// next: a RetryAfterError reschedules the task, other errors are retried
+// under WithRetryBackoff or else only counted.
+func WithErrCallback(cb ErrCallback) Option {
+	return func(tw *TimeWheel) {
+		tw.errCallback = cb
+	}
+}
+
+// WithContextErrCallback registers a ContextErrCallback, handled like the
+// one of WithErrCallback.
+func WithContextErrCallback(cb ContextErrCallback) Option {
+	return func(tw *TimeWheel) {
+		tw.ctxErrCallback = cb
+	}
+}
+
+type backoff struct {
+	initial     time.Duration
+	max         time.Duration
+	maxAttempts int
+}
+
+// WithRetryBackoff reschedules a task whose error callback fails, waiting
+// initial before the first retry and twice as long before each next one, up
+// to max. A task is given up after maxAttempts deliveries in all, or never
+// for maxAttempts <= 0; RetryAfter errors keep their own delay but count as
+// attempts too.
+func WithRetryBackoff(initial, max time.Duration, maxAttempts int) Option {
+	return func(tw *TimeWheel) {
+		tw.backoff = &backoff{initial: initial, max: max, maxAttempts: maxAttempts}
+	}
+}
+
+// delay is the wait before the retry following the given failed attempts.
+func (b *backoff) delay(failed int) time.Duration {
+	d := b.initial
+	for i := 1; i < failed && d < b.max; i++ {
+		d *= 2
+	}
+	return min(d, b.max)
+}
+
+type attemptKey struct{}
+
+// Attempt returns which delivery of its task a callback's context belongs
+// to, 1 for the first.
+func Attempt(ctx context.Context) int {
+	retries, _ := ctx.Value(attemptKey{}).(int)
+	return retries + 1
+}
+
+func withAttempt(ctx context.Context, retries int) context.Context {
+	if retries == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, attemptKey{}, retries)
+}
+
+// RetryAfterError asks the wheel to deliver the task again after a fixed
+// delay, typically taken from a downstream Retry-After header.
+type RetryAfterError struct {
+	After time.Duration
+	Err   error
+}
+
+func RetryAfter(d time.Duration, err error) error {
+	return &RetryAfterError{After: d, Err: err}
+}
+
+func (e *RetryAfterError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("timewheel: retry after %s", e.After)
+	}
+	return fmt.Sprintf("timewheel: retry after %s: %v", e.After, e.Err)
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// handleCallbackError reports whether the entry was scheduled again.
+func (tw *TimeWheel) handleCallbackError(entry *taskEntry, err error) bool {
+	tw.counters.callbackErrors.Add(1)
+	tw.log(slog.LevelWarn, "timewheel: callback failed", "key", entry.key, "err", err)
+	return tw.failed(entry, err)
+}
+
+// failed retries a failed delivery if it may be, else hands the task to the
+// dead-letter handler, and reports whether it was scheduled again.
+func (tw *TimeWheel) failed(entry *taskEntry, err error) bool {
+	var delay time.Duration
+	var ra *RetryAfterError
+	switch {
+	case errors.As(err, &ra):
+		delay = ra.After
+	case tw.backoff != nil:
+		delay = tw.backoff.delay(entry.retries + 1)
+	case entry.awaitingAck:
+		// The redelivery already pending is the retry
+		return true
+	default:
+		tw.deadLetter(entry, err)
+		return false
+	}
+	if b := tw.backoff; b != nil && b.maxAttempts > 0 && entry.retries+1 >= b.maxAttempts {
+		tw.log(slog.LevelWarn, "timewheel: giving up after retries", "key", entry.key, "attempts", entry.retries+1)
+		tw.dropRedelivery(entry)
+		tw.deadLetter(entry, err)
+		return false
+	}
+	if !tw.requeue(entry, delay, false) {
+		return false
+	}
+	tw.counters.retries.Add(1)
+	return true
+}
+
+// requeue schedules a copy of a fired entry again, since the firing may
+// still be using the original. A key that was set anew in the meantime wins
+// over the retry; the redelivery awaiting this firing's ack is moved instead.
+func (tw *TimeWheel) requeue(entry *taskEntry, d time.Duration, awaitingAck bool) bool {
+	tw.mu.Lock()
+	defer tw.unlock()
+
+	if tw.stopped() {
+		return false
+	}
+	if cur, exists := tw.keyMap[entry.key]; exists {
+		if !tw.redelivers(cur, entry) {
+			return false
+		}
+		tw.reschedule(cur, d)
+		return true
+	}
+	retry := *entry
+	retry.retries++
+	retry.awaitingAck = awaitingAck
+	tw.track(&retry)
+	tw.reschedule(&retry, d)
+	return true
+}
@@ -0,0 +1,98 @@
+package timewheel
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	attempts := make(chan time.Time, 2)
+	var calls int
+	var tw *TimeWheel
+	tw = NewTimeWheel(0, 10, nil, WithSyncCallbacks(0), WithErrCallback(func(k string, v any) error {
+		attempts <- tw.now()
+		if calls++; calls == 1 {
+			return RetryAfter(3*ManualInterval, errors.New("429 Too Many Requests"))
+		}
+		return nil
+	}))
+	defer tw.Stop()
+
+	tw.Set("webhook", "payload", ManualInterval)
+	tw.Tick()
+	first := <-attempts
+
+	tw.Advance(2 * ManualInterval)
+	if len(attempts) != 0 {
+		t.Fatal("Retry fired before the requested delay")
+	}
+	tw.Tick()
+
+	select {
+	case second := <-attempts:
+		if d := second.Sub(first); d != 3*ManualInterval {
+			t.Errorf("Expected retry after 3ms, got %s", d)
+		}
+	default:
+		t.Fatal("Task was not retried")
+	}
+	if n := tw.Stats().CallbackErrors; n != 1 {
+		t.Errorf("Expected 1 callback error, got %d", n)
+	}
+}
+
+func TestRetryAfterLosesToNewSet(t *testing.T) {
+	var calls int
+	var tw *TimeWheel
+	tw = NewTimeWheel(0, 10, nil, WithSyncCallbacks(0), WithErrCallback(func(k string, v any) error {
+		calls++
+		if v == "old" {
+			tw.Set(k, "new", 5*ManualInterval)
+			return RetryAfter(ManualInterval, nil)
+		}
+		return nil
+	}))
+	defer tw.Stop()
+
+	tw.Set("key", "old", ManualInterval)
+	tw.Advance(2 * ManualInterval)
+	if calls != 1 {
+		t.Errorf("Expected the retry to yield to the newer Set, got %d calls", calls)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	var tw *TimeWheel
+	var at []time.Time
+	var attempts []int
+	tw = NewTimeWheel(0, 10, nil, WithSyncCallbacks(0),
+		WithRetryBackoff(ManualInterval, 3*ManualInterval, 4),
+		WithContextErrCallback(func(ctx context.Context, k string, v any) error {
+			at = append(at, tw.now())
+			attempts = append(attempts, Attempt(ctx))
+			return errors.New("downstream unavailable")
+		}))
+	defer tw.Stop()
+
+	tw.Set("job", nil, ManualInterval)
+	tw.Advance(20 * ManualInterval)
+
+	if want := []int{1, 2, 3, 4}; !slices.Equal(attempts, want) {
+		t.Fatalf("Expected attempts %v, got %v", want, attempts)
+	}
+	// Waits double from the initial delay and stop at the cap
+	for i, want := range []time.Duration{ManualInterval, 2 * ManualInterval, 3 * ManualInterval} {
+		if d := at[i+1].Sub(at[i]); d != want {
+			t.Errorf("Expected retry %d after %v, got %v", i+1, want, d)
+		}
+	}
+	if n := tw.Stats().Retries; n != 3 {
+		t.Errorf("Expected 3 retries, got %d", n)
+	}
+	if _, _, ok := tw.Remaining("job"); ok {
+		t.Error("Expected the task to be given up after the last attempt")
+	}
+}
@@ -0,0 +1,53 @@
+package timewheel
+
+import "math/rand/v2"
+
+// Runtime is every source of nondeterminism the wheel draws on: time and
+// ticks, random numbers for jitter, and the goroutines that run callbacks,
+// hooks and background loops. Supplying one through WithRuntime lets a test
+// harness or simulator drive the wheel deterministically.
+//
+// The wheel calls Go while holding its lock, so Go must not run f before
+// returning; a deterministic Runtime typically queues f and runs the queue
+// in an order of its choosing. Tasks evicted for capacity are still sampled,
+// and bulk removals reported, in map iteration order.
+type Runtime interface {
+	Clock
+	// Int64N returns a pseudo-random number in [0, n) for n > 0.
+	Int64N(n int64) int64
+	// Go runs f concurrently, like the go statement.
+	Go(f func())
+}
+
+// WithRuntime replaces the clock, random source and goroutine launcher of
+// the wheel. The timer resolution probe, which sleeps on the real clock,
+// is skipped for any Runtime other than SystemRuntime.
+func WithRuntime(rt Runtime) Option {
+	return func(tw *TimeWheel) {
+		tw.clock = rt
+		tw.runtime = rt
+	}
+}
+
+// SystemRuntime returns the default Runtime: the platform clock,
+// math/rand/v2 and the go statement.
+func SystemRuntime() Runtime {
+	return systemRuntime{defaultClock()}
+}
+
+type systemRuntime struct {
+	Clock
+}
+
+func (systemRuntime) Int64N(n int64) int64 {
+	return rand.Int64N(n)
+}
+
+func (systemRuntime) Go(f func()) {
+	go f()
+}
+
+func (tw *TimeWheel) simulated() bool {
+	_, system := tw.runtime.(systemRuntime)
+	return !system
+}
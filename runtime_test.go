@@ -0,0 +1,78 @@
+package timewheel
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// simRuntime runs everything the wheel launches only when drained.
+type simRuntime struct {
+	mu    sync.Mutex
+	now   time.Time
+	queue []func()
+}
+
+func (s *simRuntime) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.now
+}
+
+func (s *simRuntime) NewTicker(d time.Duration) Ticker {
+	panic("simRuntime drives manual wheels only")
+}
+
+func (s *simRuntime) Int64N(n int64) int64 {
+	return n / 2
+}
+
+func (s *simRuntime) Go(f func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = append(s.queue, f)
+}
+
+func (s *simRuntime) drain() {
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			s.mu.Unlock()
+			return
+		}
+		f := s.queue[0]
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+		f()
+	}
+}
+
+func TestRuntime(t *testing.T) {
+	rt := &simRuntime{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	var fired []string
+	tw := NewTimeWheel(0, 10, func(k string, v any) {
+		fired = append(fired, k)
+	}, WithRuntime(rt), WithJitter(4*ManualInterval))
+	defer tw.Stop()
+
+	if !tw.now().Equal(rt.now) {
+		t.Errorf("Expected the virtual clock to start at the runtime's time, got %s", tw.now())
+	}
+
+	tw.Set("a", "data", 3*ManualInterval) // jittered to 5ms
+	tw.Set("b", "data", 4*ManualInterval) // jittered to 6ms
+	tw.Advance(4 * ManualInterval)
+	rt.drain()
+	if len(fired) != 0 {
+		t.Fatalf("Expected jitter from the runtime to delay both tasks, got %v", fired)
+	}
+
+	tw.Advance(2 * ManualInterval)
+	if len(fired) != 0 {
+		t.Fatal("Expected callbacks to wait for the runtime to run them")
+	}
+	rt.drain()
+	if len(fired) != 2 || fired[0] != "a" || fired[1] != "b" {
+		t.Errorf("Expected a then b to fire, got %v", fired)
+	}
+}
@@ -0,0 +1,36 @@
+package timewheel
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Schedule sets a one-shot task under a key generated for it and returns
+// the key, which is needed only to cancel or move the task.
+func (tw *TimeWheel) Schedule(value any, expiration time.Duration) string {
+	key, _ := tw.ScheduleWith(value, expiration)
+	return key
+}
+
+// ScheduleWith is Schedule with per-task options and an error.
+func (tw *TimeWheel) ScheduleWith(value any, expiration time.Duration, opts ...SetOption) (string, error) {
+	key := tw.keys.next()
+	return key, tw.SetWith(key, value, expiration, opts...)
+}
+
+// keyGen generates keys unique to one wheel: a counter behind a prefix taken
+// from the construction time, so keys replayed from a WAL written by an
+// earlier wheel do not collide with new ones.
+type keyGen struct {
+	prefix string
+	n      atomic.Uint64
+}
+
+func newKeyGen(at time.Time) *keyGen {
+	return &keyGen{prefix: "~" + strconv.FormatInt(at.UnixNano(), 36) + "-"}
+}
+
+func (g *keyGen) next() string {
+	return g.prefix + strconv.FormatUint(g.n.Add(1), 36)
+}
@@ -0,0 +1,33 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedule(t *testing.T) {
+	fired := make(map[string]any)
+	tw := NewTimeWheel(0, 10, func(k string, v any) {
+		fired[k] = v
+	}, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	first := tw.Schedule("a", 2*ManualInterval)
+	second := tw.Schedule("b", 2*ManualInterval)
+	if first == second {
+		t.Fatalf("Expected unique keys, got %s twice", first)
+	}
+	tw.Delete(second)
+	tw.Advance(2 * ManualInterval)
+	if len(fired) != 1 || fired[first] != "a" {
+		t.Errorf("Expected only the first task to fire under its key, got %v", fired)
+	}
+
+	if _, err := tw.ScheduleWith("c", 0, TaskZeroTTL(Reject)); err != ErrZeroTTL {
+		t.Errorf("Expected ErrZeroTTL, got %v", err)
+	}
+
+	if newKeyGen(tw.createdAt.Add(time.Nanosecond)).next() == first {
+		t.Error("Expected wheels created at different times to generate different keys")
+	}
+}
@@ -0,0 +1,135 @@
+// Package sessions is a concurrent map whose entries expire, built on a
+// TimeWheel: the TTL cache behind session stores, lease tables and
+// short-lived lookups. Each entry has its own TTL, Touch slides it, and an
+// eviction callback sees entries that expire.
+package sessions
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nzai/timewheel"
+)
+
+type Store[V any] struct {
+	tw      *timewheel.TimeWheel
+	onEvict func(id string, value V)
+
+	mu      sync.RWMutex
+	entries map[string]*entry[V]
+	gen     uint64
+}
+
+// entry is a stored value; gen tells the firing of a stale deadline from
+// that of the current one.
+type entry[V any] struct {
+	value V
+	ttl   time.Duration
+	gen   uint64
+}
+
+type config struct {
+	interval time.Duration
+}
+
+type Option func(*config)
+
+// WithInterval sets the wheel's tick, which bounds how late an entry can
+// expire. Defaults to a second.
+func WithInterval(d time.Duration) Option {
+	return func(c *config) {
+		c.interval = d
+	}
+}
+
+// New returns an empty store. onEvict, if not nil, is called on its own
+// goroutine with each entry that expires; Delete does not call it.
+func New[V any](onEvict func(id string, value V), opts ...Option) *Store[V] {
+	c := config{interval: time.Second}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	s := &Store[V]{onEvict: onEvict, entries: make(map[string]*entry[V])}
+	s.tw = timewheel.NewTimeWheel(c.interval, 60, s.expire)
+	return s
+}
+
+// Set stores value under id for ttl, replacing any entry and its TTL.
+func (s *Store[V]) Set(id string, value V, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.gen++
+	s.entries[id] = &entry[V]{value: value, ttl: ttl, gen: s.gen}
+	s.tw.Set(id, s.gen, ttl)
+}
+
+// Get returns the value stored under id without touching its TTL.
+func (s *Store[V]) Get(id string) (V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.entries[id]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Touch restarts the entry's TTL from now, for sliding expiration, and
+// reports whether the entry was present.
+func (s *Store[V]) Touch(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[id]
+	if !ok {
+		return false
+	}
+	s.gen++
+	e.gen = s.gen
+	s.tw.Set(id, e.gen, e.ttl)
+	return true
+}
+
+// Delete removes the entry under id and reports whether it was present.
+func (s *Store[V]) Delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[id]; !ok {
+		return false
+	}
+	delete(s.entries, id)
+	s.tw.Delete(id)
+	return true
+}
+
+func (s *Store[V]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.entries)
+}
+
+// Close stops expiring entries. The store stays readable.
+func (s *Store[V]) Close() {
+	s.tw.Stop()
+}
+
+// expire evicts the entry a deadline belongs to, unless a Set or Touch
+// replaced that deadline while it was firing.
+func (s *Store[V]) expire(id string, gen any) {
+	s.mu.Lock()
+	e, ok := s.entries[id]
+	if !ok || e.gen != gen.(uint64) {
+		s.mu.Unlock()
+		return
+	}
+	delete(s.entries, id)
+	s.mu.Unlock()
+
+	if s.onEvict != nil {
+		s.onEvict(id, e.value)
+	}
+}
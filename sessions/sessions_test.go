@@ -0,0 +1,58 @@
+package sessions
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	evicted := make(chan string, 4)
+	s := New(func(id string, value int) {
+		evicted <- id
+	}, WithInterval(10*time.Millisecond))
+	defer s.Close()
+
+	s.Set("a", 1, 50*time.Millisecond)
+	s.Set("b", 2, 50*time.Millisecond)
+	s.Set("c", 3, 50*time.Millisecond)
+	if v, ok := s.Get("b"); !ok || v != 2 {
+		t.Errorf("Expected b=2, got %v, %v", v, ok)
+	}
+	s.Delete("c")
+
+	// Keep a alive past its original deadline
+	for i := 0; i < 4; i++ {
+		time.Sleep(25 * time.Millisecond)
+		if !s.Touch("a") {
+			t.Fatal("Expected a to be present while touched")
+		}
+	}
+	select {
+	case id := <-evicted:
+		if id != "b" {
+			t.Errorf("Expected b to expire first, got %s", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("b did not expire")
+	}
+	if _, ok := s.Get("a"); !ok {
+		t.Error("Expected touching to keep a")
+	}
+
+	select {
+	case id := <-evicted:
+		if id != "a" {
+			t.Errorf("Expected a to expire once no longer touched, got %s", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("a did not expire")
+	}
+	if s.Len() != 0 || s.Touch("a") {
+		t.Error("Expected the store to be empty")
+	}
+	select {
+	case id := <-evicted:
+		t.Errorf("Expected a deleted entry not to be evicted, got %s", id)
+	case <-time.After(30 * time.Millisecond):
+	}
+}
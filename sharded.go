@@ -0,0 +1,180 @@
+package timewheel
+
+import (
+	"hash/maphash"
+	"time"
+)
+
+// ShardedTimeWheel spreads keys over independent wheels, each with its own
+// lock and tick goroutine, so concurrent Set/Delete/Move calls on many cores
+// do not contend on one mutex. A key always maps to the same shard.
+//
+// Options apply to every shard: limits such as WithCapacity are per shard,
+// and each shard has its own Expired channel. Features not mirrored here are
+// reached through Shard.
+type ShardedTimeWheel struct {
+	seed   maphash.Seed
+	shards []*TimeWheel
+	keys   *keyGen
+}
+
+func NewShardedTimeWheel(shards int, baseInterval time.Duration, slotsPerLayer int, callback func(key string, value any), opts ...Option) *ShardedTimeWheel {
+	if shards < 1 {
+		shards = 1
+	}
+	s := &ShardedTimeWheel{
+		seed:   maphash.MakeSeed(),
+		shards: make([]*TimeWheel, shards),
+	}
+	for i := range s.shards {
+		s.shards[i] = NewTimeWheel(baseInterval, slotsPerLayer, callback, opts...)
+	}
+	s.keys = newKeyGen(s.shards[0].createdAt)
+	return s
+}
+
+// Shard returns the wheel holding key.
+func (s *ShardedTimeWheel) Shard(key string) *TimeWheel {
+	return s.shards[maphash.String(s.seed, key)%uint64(len(s.shards))]
+}
+
+func (s *ShardedTimeWheel) Shards() []*TimeWheel {
+	return append([]*TimeWheel(nil), s.shards...)
+}
+
+func (s *ShardedTimeWheel) Set(key string, value any, expiration time.Duration) (time.Duration, bool) {
+	return s.Shard(key).Set(key, value, expiration)
+}
+
+func (s *ShardedTimeWheel) SetWith(key string, value any, expiration time.Duration, opts ...SetOption) error {
+	return s.Shard(key).SetWith(key, value, expiration, opts...)
+}
+
+func (s *ShardedTimeWheel) SetAt(key string, value any, at time.Time, opts ...SetOption) error {
+	return s.Shard(key).SetAt(key, value, at, opts...)
+}
+
+func (s *ShardedTimeWheel) SetNX(key string, value any, expiration time.Duration) bool {
+	return s.Shard(key).SetNX(key, value, expiration)
+}
+
+func (s *ShardedTimeWheel) Schedule(value any, expiration time.Duration) string {
+	key, _ := s.ScheduleWith(value, expiration)
+	return key
+}
+
+func (s *ShardedTimeWheel) ScheduleWith(value any, expiration time.Duration, opts ...SetOption) (string, error) {
+	key := s.keys.next()
+	return key, s.SetWith(key, value, expiration, opts...)
+}
+
+func (s *ShardedTimeWheel) Delete(key string) (time.Duration, bool) {
+	return s.Shard(key).Delete(key)
+}
+
+func (s *ShardedTimeWheel) Take(key string) (any, bool) {
+	return s.Shard(key).Take(key)
+}
+
+func (s *ShardedTimeWheel) UpdateValue(key string, value any) bool {
+	return s.Shard(key).UpdateValue(key, value)
+}
+
+func (s *ShardedTimeWheel) Move(key string, expiration time.Duration) (time.Duration, bool) {
+	return s.Shard(key).Move(key, expiration)
+}
+
+func (s *ShardedTimeWheel) MoveIf(key string, expected any, expiration time.Duration) bool {
+	return s.Shard(key).MoveIf(key, expected, expiration)
+}
+
+func (s *ShardedTimeWheel) Extend(key string, delta time.Duration) (time.Duration, error) {
+	return s.Shard(key).Extend(key, delta)
+}
+
+func (s *ShardedTimeWheel) Shorten(key string, delta time.Duration) (time.Duration, error) {
+	return s.Shard(key).Shorten(key, delta)
+}
+
+func (s *ShardedTimeWheel) FlushAll() {
+	for _, tw := range s.shards {
+		tw.FlushAll()
+	}
+}
+
+func (s *ShardedTimeWheel) FlushWhere(match func(key string, value any) bool) int {
+	n := 0
+	for _, shard := range s.shards {
+		n += shard.FlushWhere(match)
+	}
+	return n
+}
+
+func (s *ShardedTimeWheel) FlushPrefix(prefix string) int {
+	n := 0
+	for _, shard := range s.shards {
+		n += shard.FlushPrefix(prefix)
+	}
+	return n
+}
+
+func (s *ShardedTimeWheel) Stop() {
+	for _, tw := range s.shards {
+		tw.Stop()
+	}
+}
+
+// StopAndDrain stops every shard and returns their pending tasks, shard by
+// shard.
+func (s *ShardedTimeWheel) StopAndDrain() []TaskInfo {
+	var pending []TaskInfo
+	for _, shard := range s.shards {
+		pending = append(pending, shard.StopAndDrain()...)
+	}
+	return pending
+}
+
+// Stats sums the shards' counts and latencies; tick counts and lag are the
+// maximum over shards, since the shards tick side by side.
+func (s *ShardedTimeWheel) Stats() Stats {
+	total := s.shards[0].Stats()
+	for _, tw := range s.shards[1:] {
+		st := tw.Stats()
+		total.Pending += st.Pending
+		total.Deferred += st.Deferred
+		total.OverdueHeld += st.OverdueHeld
+		total.Layers = max(total.Layers, st.Layers)
+		total.Scheduled += st.Scheduled
+		total.Fired += st.Fired
+		total.Deleted += st.Deleted
+		total.Dropped += st.Dropped
+		total.Evicted += st.Evicted
+		total.Ticks = max(total.Ticks, st.Ticks)
+		total.CompensatedTicks = max(total.CompensatedTicks, st.CompensatedTicks)
+		total.TickLag = max(total.TickLag, st.TickLag)
+		total.CatchUps += st.CatchUps
+		total.LateTasks += st.LateTasks
+		total.Panics += st.Panics
+		total.Timeouts += st.Timeouts
+		total.CallbackErrors += st.CallbackErrors
+		total.Retries += st.Retries
+		total.Lateness = total.Lateness.merge(st.Lateness)
+		total.SetLatency = total.SetLatency.merge(st.SetLatency)
+		total.DeleteLatency = total.DeleteLatency.merge(st.DeleteLatency)
+		total.MoveLatency = total.MoveLatency.merge(st.MoveLatency)
+	}
+	return total
+}
+
+func (l OpLatency) merge(o OpLatency) OpLatency {
+	return OpLatency{LockWait: l.LockWait.merge(o.LockWait), Total: l.Total.merge(o.Total)}
+}
+
+// merge adds two histograms with the same bounds.
+func (h Histogram) merge(o Histogram) Histogram {
+	buckets := make([]uint64, len(h.Buckets))
+	for i := range buckets {
+		buckets[i] = h.Buckets[i] + o.Buckets[i]
+	}
+	return Histogram{Count: h.Count + o.Count, Sum: h.Sum + o.Sum, Bounds: h.Bounds, Buckets: buckets}
+}
@@ -0,0 +1,52 @@
+package timewheel
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestShardedTimeWheel(t *testing.T) {
+	var mu sync.Mutex
+	fired := make(map[string]bool)
+	s := NewShardedTimeWheel(4, 0, 10, func(k string, v any) {
+		mu.Lock()
+		fired[k] = true
+		mu.Unlock()
+	}, WithSyncCallbacks(0))
+	defer s.Stop()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				key := fmt.Sprintf("g%d-%d", g, i)
+				s.Set(key, "data", 3*ManualInterval)
+				if i%5 == 0 {
+					s.Delete(key)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	st := s.Stats()
+	if st.Pending != 320 || st.Scheduled != 400 || st.Deleted != 80 {
+		t.Fatalf("Expected 320 pending, 400 scheduled and 80 deleted, got %d, %d and %d", st.Pending, st.Scheduled, st.Deleted)
+	}
+	if st.SetLatency.Total.Count != 400 {
+		t.Errorf("Expected 400 Set observations, got %d", st.SetLatency.Total.Count)
+	}
+
+	for _, tw := range s.Shards() {
+		tw.Advance(3 * ManualInterval)
+	}
+	if len(fired) != 320 {
+		t.Errorf("Expected 320 tasks to fire, got %d", len(fired))
+	}
+	if s.Shard("g1-1") != s.Shard("g1-1") {
+		t.Error("Expected a key to map to one shard")
+	}
+}
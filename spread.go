@@ -0,0 +1,35 @@
+package timewheel
+
+import "time"
+
+// WithSlotSpread keeps keys set with the same TTL from piling into one slot
+// whose tick then stalls the wheel: a task bound for a base slot already
+// holding threshold tasks goes to the nearest later slot within tolerance
+// that holds fewer, or else the least loaded one. Spread tasks fire up to
+// tolerance late.
+func WithSlotSpread(threshold int, tolerance time.Duration) Option {
+	return func(tw *TimeWheel) {
+		tw.spreadThreshold = max(threshold, 1)
+		tw.spreadTolerance = tolerance
+	}
+}
+
+// spreadSlot picks the base slot for a deadline ahead ticks away whose own
+// slot is pos. It only looks as far as the current revolution reaches.
+func (tw *TimeWheel) spreadSlot(l *layer, pos int, ahead uint64) int {
+	if l.buckets[pos].n < tw.spreadThreshold {
+		return pos
+	}
+	best := pos
+	ticks := int(tw.spreadTolerance / tw.baseInterval)
+	for j := 1; j <= ticks && ahead+uint64(j) < uint64(l.slots); j++ {
+		p := (pos + j) % l.slots
+		if l.buckets[p].n < tw.spreadThreshold {
+			return p
+		}
+		if l.buckets[p].n < l.buckets[best].n {
+			best = p
+		}
+	}
+	return best
+}
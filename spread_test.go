@@ -0,0 +1,54 @@
+package timewheel
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSlotSpread(t *testing.T) {
+	var fired int
+	tw := NewTimeWheel(0, 20, func(string, any) { fired++ },
+		WithSyncCallbacks(0), WithSlotSpread(2, 3*ManualInterval))
+	defer tw.Stop()
+
+	for i := 0; i < 9; i++ {
+		tw.Set(fmt.Sprint("key", i), nil, 5*ManualInterval)
+	}
+	tw.Advance(4 * ManualInterval)
+	if fired != 0 {
+		t.Fatalf("Expected nothing to fire early, got %d", fired)
+	}
+
+	// Two per slot over the tolerance, the overflow on the earliest of the
+	// least loaded ones
+	for i, want := range []int{3, 2, 2, 2} {
+		before := fired
+		tw.Advance(ManualInterval)
+		if got := fired - before; got != want {
+			t.Errorf("Tick %d: expected %d tasks, got %d", 5+i, want, got)
+		}
+	}
+}
+
+func TestSlotSpreadLongTTL(t *testing.T) {
+	var fired int
+	tw := NewTimeWheel(0, 10, func(string, any) { fired++ },
+		WithSyncCallbacks(0), WithSlotSpread(1, 2*ManualInterval))
+	defer tw.Stop()
+
+	// Deadlines on an upper layer spread when they come down to the base
+	for i := 0; i < 3; i++ {
+		tw.Set(fmt.Sprint("key", i), nil, 25*ManualInterval)
+	}
+	tw.Advance(24 * ManualInterval)
+	if fired != 0 {
+		t.Fatalf("Expected nothing to fire early, got %d", fired)
+	}
+	for i := 0; i < 3; i++ {
+		before := fired
+		tw.Advance(ManualInterval)
+		if got := fired - before; got != 1 {
+			t.Errorf("Tick %d: expected 1 task, got %d", 25+i, got)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package timewheel
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// WithStartGate holds back every expiration until Start is called, so a
+// service can bulk-load tasks and finish initializing its dependencies first.
+// The wheel keeps ticking meanwhile: deadlines count from Set, and tasks that
+// come due before Start fire, in deadline order, when it is called.
+func WithStartGate() Option {
+	return func(tw *TimeWheel) {
+		tw.gated = true
+	}
+}
+
+// Start runs the loop of a wheel built by NewUnstartedTimeWheel, or of one
+// that was stopped, and opens the start gate; each step is skipped when it
+// does not apply. The wheel stops when ctx is done.
+//
+// A restarted wheel resumes its pending tasks with their deadlines pushed
+// back by the time it spent stopped, so they fire as if it had paused. With
+// WithCatchUp, a stop longer than the threshold counts as a tick gap instead:
+// tasks that came due meanwhile fire at once and the rest keep their
+// deadlines. Tasks reported to the remove hook as stopped are resumed too.
+func (tw *TimeWheel) Start(ctx context.Context) {
+	tw.lifeMu.Lock()
+	tw.mu.Lock()
+	var due []*taskEntry
+	launch := !tw.started || tw.stopped()
+	if tw.stopped() {
+		due = tw.restart()
+	}
+	if !tw.started {
+		tw.started = true
+		tw.rebase(tw.clock.Now().Sub(tw.createdAt))
+	}
+	if tw.gated {
+		tw.gated = false
+		due = append(due, tw.ungate()...)
+	}
+	tw.unlock()
+
+	if launch {
+		tw.run()
+	}
+	quit := tw.quit()
+	tw.lifeMu.Unlock()
+	tw.dispatch(due)
+
+	if ctx.Done() != nil {
+		tw.runtime.Go(func() {
+			select {
+			case <-ctx.Done():
+				tw.Stop()
+			case <-quit:
+			}
+		})
+	}
+}
+
+// lifetime is one run of the wheel, from its start to a Stop.
+type lifetime struct {
+	quit      chan struct{}
+	stoppedAt time.Time
+	// looping is closed when the tick loop of the run exits, or nil if the
+	// run had none.
+	looping chan struct{}
+}
+
+func newLifetime() *lifetime {
+	return &lifetime{quit: make(chan struct{})}
+}
+
+// restart begins a new run of a stopped wheel under the lock and returns the
+// tasks that came due while it was stopped, if it catches up on them.
+func (tw *TimeWheel) restart() []*taskEntry {
+	old := tw.life.Load()
+	if old.looping != nil {
+		// The old loop exits on its own; wait so two never tick at once
+		tw.mu.Unlock()
+		<-old.looping
+		tw.mu.Lock()
+	}
+	tw.life.Store(newLifetime())
+	tw.reopenExpired()
+	if tw.cluster != nil {
+		tw.cluster.done = make(chan struct{})
+	}
+	tw.ticksDone = 0
+	if !tw.started || tw.manual {
+		return nil
+	}
+
+	now := tw.clock.Now()
+	gap := tickGap(old.stoppedAt, now)
+	if tw.catchUpThreshold <= 0 || gap <= tw.catchUpThreshold {
+		tw.rebase(now.Sub(old.stoppedAt))
+		return nil
+	}
+	late := tw.fastForward(now)
+	tw.counters.catchUps.Add(1)
+	tw.counters.late.Add(uint64(len(late)))
+	tw.log(slog.LevelWarn, "timewheel: caught up after a restart", "gap", gap, "late", len(late))
+	sort.Slice(late, func(i, j int) bool {
+		return late[i].expiration.Before(late[j].expiration)
+	})
+	return tw.applyBudget(late)
+}
+
+// ungate takes the tasks that came due behind the gate, in deadline order.
+func (tw *TimeWheel) ungate() []*taskEntry {
+	var due []*taskEntry
+	for key, entry := range tw.parked {
+		if entry.held {
+			continue
+		}
+		delete(tw.parked, key)
+		tw.untrack(entry)
+		tw.board(entry)
+		due = append(due, entry)
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].expiration.Before(due[j].expiration)
+	})
+	return due
+}
+
+// rebase pushes the deadlines of tasks set before Start back by the time the
+// wheel sat unstarted, matching where they wait in the wheel.
+func (tw *TimeWheel) rebase(idle time.Duration) {
+	if tw.manual || idle <= 0 {
+		return
+	}
+	for _, entry := range tw.keyMap {
+		entry.expiration = entry.expiration.Add(idle)
+	}
+}
+
+func (tw *TimeWheel) holding(entry *taskEntry) bool {
+	return entry.held || tw.gated
+}
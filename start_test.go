@@ -0,0 +1,156 @@
+package timewheel
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStartGate(t *testing.T) {
+	fired := make(chan string, 4)
+	tw := NewTimeWheel(0, 10, func(k string, v any) {
+		fired <- k
+	}, WithStartGate(), WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	tw.Set("second", "data", 2*ManualInterval)
+	tw.Set("first", "data", ManualInterval)
+	tw.Set("now", "data", 0)
+	tw.Set("later", "data", 10*ManualInterval)
+	tw.Advance(3 * ManualInterval)
+	select {
+	case k := <-fired:
+		t.Fatalf("Expected nothing to fire before Start, got %s", k)
+	default:
+	}
+	if n := tw.Stats().Pending; n != 4 {
+		t.Errorf("Expected 4 pending tasks before Start, got %d", n)
+	}
+
+	tw.Delete("second")
+	tw.Start(context.Background())
+	for _, want := range []string{"now", "first"} {
+		if got := <-fired; got != want {
+			t.Errorf("Expected %s to fire, got %s", want, got)
+		}
+	}
+
+	tw.Advance(7 * ManualInterval)
+	select {
+	case k := <-fired:
+		if k != "later" {
+			t.Errorf("Expected later to fire, got %s", k)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Task did not fire after Start")
+	}
+}
+
+func TestUnstartedWheel(t *testing.T) {
+	fired := make(chan string, 1)
+	tw := NewUnstartedTimeWheel(10*time.Millisecond, 10, func(k string, v any) {
+		fired <- k
+	})
+
+	tw.Set("task", "data", 20*time.Millisecond)
+	select {
+	case <-fired:
+		t.Fatal("Expected an unstarted wheel not to tick")
+	case <-time.After(60 * time.Millisecond):
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tw.Start(ctx)
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("Task did not fire after Start")
+	}
+
+	cancel()
+	deadline := time.Now().Add(time.Second)
+	for !tw.stopped() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if err := tw.SetWith("late", "data", time.Millisecond); err != ErrStopped {
+		t.Errorf("Expected the wheel to stop with its context, got %v", err)
+	}
+}
+
+func TestRestart(t *testing.T) {
+	fired := make(chan string, 2)
+	clock := newFakeClock()
+	tw := NewTimeWheel(time.Second, 10, func(k string, v any) {
+		fired <- k
+	}, WithClock(clock))
+	defer tw.Stop()
+
+	tw.Set("a", nil, 2*time.Second)
+	clock.tick(time.Second)
+	tw.Stop()
+	if err := tw.SetWith("b", nil, time.Second); err != ErrStopped {
+		t.Errorf("Expected ErrStopped after Stop, got %v", err)
+	}
+
+	// The stopped time does not count against the task
+	clock.sleep(time.Minute)
+	tw.Start(context.Background())
+	if _, hi, _ := tw.Remaining("a"); hi > 2*time.Second {
+		t.Errorf("Expected the deadline to shift by the stop, %v left", hi)
+	}
+	select {
+	case k := <-fired:
+		t.Fatalf("Expected nothing to fire on restart, got %s", k)
+	case <-time.After(20 * time.Millisecond):
+	}
+	clock.tick(time.Second)
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the resumed task to fire on the next tick")
+	}
+}
+
+func TestRestartCatchUp(t *testing.T) {
+	fired := make(chan string, 2)
+	clock := newFakeClock()
+	tw := NewTimeWheel(time.Second, 10, func(k string, v any) {
+		fired <- k
+	}, WithClock(clock), WithCatchUp(5*time.Second, nil))
+	defer tw.Stop()
+
+	tw.Set("due", nil, 3*time.Second)
+	tw.Set("later", nil, 30*time.Second)
+	tw.Stop()
+	clock.sleep(10 * time.Second)
+	tw.Start(context.Background())
+
+	select {
+	case k := <-fired:
+		if k != "due" {
+			t.Errorf("Expected the task due during the stop, got %s", k)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the restart to catch up on the due task")
+	}
+	if _, hi, ok := tw.Remaining("later"); !ok || hi > 21*time.Second {
+		t.Errorf("Expected the later task to keep its deadline, %v left", hi)
+	}
+}
+
+func TestStopAndDrainThenRestart(t *testing.T) {
+	tw := NewTimeWheel(0, 10, func(string, any) {})
+	defer tw.Stop()
+
+	tw.Set("a", nil, ManualInterval)
+	if pending := tw.StopAndDrain(); len(pending) != 1 {
+		t.Fatalf("Expected one drained task, got %v", pending)
+	}
+	tw.Start(context.Background())
+	if n := tw.Stats().Pending; n != 0 {
+		t.Errorf("Expected drained tasks to leave the wheel, %d pending", n)
+	}
+	if err := tw.SetWith("b", nil, ManualInterval); err != nil {
+		t.Errorf("Expected Set to work after a restart, got %v", err)
+	}
+}
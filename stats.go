@@ -0,0 +1,85 @@
+package timewheel
+
+import "time"
+
+// Stats is a structured snapshot of the wheel's state.
+type Stats struct {
+	Pending int
+	// Deferred counts expired tasks waiting for the Manager's budget.
+	Deferred int
+	// OverdueHeld counts held tasks whose deadline has already passed.
+	OverdueHeld int
+	Layers      int
+	// BaseInterval is the effective base interval, after any resolution
+	// adjustment.
+	BaseInterval time.Duration
+	// RequestedInterval is the base interval passed to NewTimeWheel.
+	RequestedInterval time.Duration
+	// TimerResolution is the system timer granularity, measured once per
+	// process; zero in manual mode.
+	TimerResolution time.Duration
+	Scheduled       uint64
+	Fired           uint64
+	Deleted         uint64
+	Dropped         uint64
+	// Evicted counts tasks cancelled to stay within the capacity.
+	Evicted uint64
+	Ticks   uint64
+	// CompensatedTicks counts extra slots advanced to catch up after stalls.
+	CompensatedTicks uint64
+	// TickLag is how far behind its ideal schedule the latest tick ran.
+	TickLag time.Duration
+	// CatchUps counts fast-forwards after large tick gaps; LateTasks counts
+	// the tasks they fired.
+	CatchUps       uint64
+	LateTasks      uint64
+	Panics         uint64
+	Timeouts       uint64
+	CallbackErrors uint64
+	// Retries counts tasks rescheduled after their callback failed.
+	Retries uint64
+	// Lateness is how long after its deadline each task fired.
+	Lateness Histogram
+	// Latencies of the mutation APIs: Set also covers SetWith, SetAt, SetNX
+	// and SetCron; Move covers Extend and Shorten.
+	SetLatency    OpLatency
+	DeleteLatency OpLatency
+	MoveLatency   OpLatency
+}
+
+func (tw *TimeWheel) Stats() Stats {
+	tw.mu.RLock()
+	pending := len(tw.keyMap)
+	overdueHeld := len(tw.parked)
+	deferred := len(tw.backlog)
+	layers := len(tw.layers)
+	tw.mu.RUnlock()
+
+	return Stats{
+		Pending:           pending,
+		OverdueHeld:       overdueHeld,
+		Deferred:          deferred,
+		Layers:            layers,
+		BaseInterval:      tw.baseInterval,
+		RequestedInterval: tw.requestedInterval,
+		TimerResolution:   tw.timerResolution(),
+		Scheduled:         tw.counters.scheduled.Load(),
+		Fired:             tw.counters.fired.Load(),
+		Deleted:           tw.counters.deleted.Load(),
+		Dropped:           tw.counters.dropped.Load(),
+		Evicted:           tw.counters.evicted.Load(),
+		Ticks:             tw.counters.ticks.Load(),
+		CompensatedTicks:  tw.counters.compensated.Load(),
+		TickLag:           time.Duration(tw.tickLag.Load()),
+		CatchUps:          tw.counters.catchUps.Load(),
+		LateTasks:         tw.counters.late.Load(),
+		Panics:            tw.counters.panics.Load(),
+		Timeouts:          tw.counters.timeouts.Load(),
+		CallbackErrors:    tw.counters.callbackErrors.Load(),
+		Retries:           tw.counters.retries.Load(),
+		Lateness:          tw.lateness.snapshot(),
+		SetLatency:        tw.latency.set.snapshot(),
+		DeleteLatency:     tw.latency.delete.snapshot(),
+		MoveLatency:       tw.latency.move.snapshot(),
+	}
+}
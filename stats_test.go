@@ -0,0 +1,49 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil)
+	defer tw.Stop()
+
+	tw.Set("a", "data", ManualInterval)
+	tw.Set("b", "data", 5*ManualInterval)
+	tw.Tick()
+
+	s := tw.Stats()
+	if s.Pending != 1 || s.Scheduled != 2 || s.Fired != 1 || s.Ticks != 1 {
+		t.Errorf("Unexpected stats %+v", s)
+	}
+	if s.TimerResolution != 0 {
+		t.Errorf("Expected no resolution measurement in manual mode, got %s", s.TimerResolution)
+	}
+}
+
+func TestTimerResolution(t *testing.T) {
+	tw := NewTimeWheel(100*time.Millisecond, 10, nil)
+	defer tw.Stop()
+
+	s := tw.Stats()
+	if s.TimerResolution <= 0 {
+		t.Errorf("Expected a measured timer resolution, got %s", s.TimerResolution)
+	}
+	if s.BaseInterval != s.RequestedInterval {
+		t.Errorf("Base interval changed without WithResolutionAdjust: %s", s.BaseInterval)
+	}
+}
+
+func TestAdjustedInterval(t *testing.T) {
+	cases := []struct{ base, resolution, want time.Duration }{
+		{time.Millisecond, 50 * time.Microsecond, time.Millisecond},
+		{time.Millisecond, 15600 * time.Microsecond, 16 * time.Millisecond},
+		{5 * time.Millisecond, 15600 * time.Microsecond, 20 * time.Millisecond},
+	}
+	for _, c := range cases {
+		if got := adjustedInterval(c.base, c.resolution); got != c.want {
+			t.Errorf("adjustedInterval(%s, %s) = %s, want %s", c.base, c.resolution, got, c.want)
+		}
+	}
+}
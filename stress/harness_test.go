@@ -0,0 +1,206 @@
+package stress
+
+import (
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nzai/timewheel"
+)
+
+// base is the tick of every wheel under stress; the harness clock moves in
+// multiples of it, so its size only matters relative to the TTLs.
+const base = time.Millisecond
+
+// runtime is a fake clock whose ticks a driver delivers with skew, and a
+// goroutine launcher that delays some goroutines to widen the windows
+// between the wheel handing work off and that work running.
+type runtime struct {
+	mu   sync.Mutex
+	now  time.Time
+	c    chan time.Time
+	stop chan struct{}
+	// slow is the share of goroutines delayed by lag, out of 100
+	slow int
+	lag  time.Duration
+}
+
+func newRuntime(slow int, lag time.Duration) *runtime {
+	return &runtime{
+		now:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		c:    make(chan time.Time),
+		stop: make(chan struct{}),
+		slow: slow,
+		lag:  lag,
+	}
+}
+
+func (rt *runtime) Now() time.Time {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.now
+}
+
+func (rt *runtime) NewTicker(time.Duration) timewheel.Ticker {
+	return ticker{rt.c}
+}
+
+func (rt *runtime) Int64N(n int64) int64 {
+	return rand.Int64N(n)
+}
+
+func (rt *runtime) Go(f func()) {
+	var delay time.Duration
+	if rand.IntN(100) < rt.slow {
+		delay = time.Duration(rand.Int64N(int64(rt.lag)))
+	} else if rand.IntN(2) == 0 {
+		delay = time.Duration(rand.Int64N(int64(100 * time.Microsecond)))
+	}
+	go func() {
+		if delay > 0 {
+			time.Sleep(delay)
+		}
+		f()
+	}()
+}
+
+// tick moves the clock by d and delivers one tick, unless the driver stops.
+func (rt *runtime) tick(d time.Duration) bool {
+	rt.mu.Lock()
+	rt.now = rt.now.Add(d)
+	now := rt.now
+	rt.mu.Unlock()
+	select {
+	case rt.c <- now:
+		return true
+	case <-rt.stop:
+		return false
+	}
+}
+
+// drive ticks the clock until halt is called: mostly one base interval at
+// a time, sometimes half of one or several at once, as a stalled or
+// overeager ticker would.
+func (rt *runtime) drive(t *testing.T) (halt func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			d := base
+			switch rand.IntN(10) {
+			case 0:
+				d = base / 2
+			case 1:
+				d = time.Duration(2+rand.IntN(4)) * base
+			}
+			if !rt.tick(d) {
+				return
+			}
+			time.Sleep(time.Duration(rand.Int64N(int64(200 * time.Microsecond))))
+		}
+	}()
+	var once sync.Once
+	halt = func() {
+		once.Do(func() {
+			close(rt.stop)
+			<-done
+		})
+	}
+	t.Cleanup(halt)
+	return halt
+}
+
+type ticker struct {
+	c chan time.Time
+}
+
+func (tk ticker) C() <-chan time.Time {
+	return tk.c
+}
+
+func (ticker) Stop() {}
+
+// ledger accounts for every task value: each must end exactly once, and
+// fire by callback exactly when it ends as expired.
+type ledger struct {
+	mu    sync.Mutex
+	set   int
+	ended map[int]timewheel.Reason
+	fired map[int]int
+	taken map[int]bool
+	errs  []string
+}
+
+func newLedger() *ledger {
+	return &ledger{
+		ended: make(map[int]timewheel.Reason),
+		fired: make(map[int]int),
+		taken: make(map[int]bool),
+	}
+}
+
+func (l *ledger) scheduled() {
+	l.mu.Lock()
+	l.set++
+	l.mu.Unlock()
+}
+
+func (l *ledger) fire(id int) {
+	l.mu.Lock()
+	l.fired[id]++
+	l.mu.Unlock()
+}
+
+func (l *ledger) take(id int) {
+	l.mu.Lock()
+	l.taken[id] = true
+	l.mu.Unlock()
+}
+
+func (l *ledger) end(id int, reason timewheel.Reason) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if prev, dup := l.ended[id]; dup {
+		l.errs = append(l.errs, "task ended twice, as "+prev.String()+" and "+reason.String())
+		return
+	}
+	l.ended[id] = reason
+}
+
+// check waits for every scheduled task to end, then verifies the books.
+func (l *ledger) check(t *testing.T) {
+	t.Helper()
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); {
+		l.mu.Lock()
+		done := len(l.ended) == l.set
+		l.mu.Unlock()
+		if done {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, err := range l.errs {
+		t.Error(err)
+	}
+	if len(l.ended) != l.set {
+		t.Fatalf("Expected all %d tasks to end, %d did", l.set, len(l.ended))
+	}
+	for id, reason := range l.ended {
+		fired := l.fired[id]
+		switch {
+		case fired > 1:
+			t.Errorf("Task %d fired %d times", id, fired)
+		case fired == 1 && reason != timewheel.ReasonExpired:
+			t.Errorf("Task %d fired but ended as %s", id, reason)
+		case fired == 0 && reason == timewheel.ReasonExpired:
+			t.Errorf("Task %d ended as expired without firing", id)
+		case l.taken[id] && reason != timewheel.ReasonTaken:
+			t.Errorf("Task %d was taken but ended as %s", id, reason)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package stress
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nzai/timewheel"
+)
+
+const (
+	keys    = 16
+	writers = 8
+	rounds  = 2000
+)
+
+// TestMutationsDuringTicks races every kind of mutation against skewed
+// ticks and delayed callbacks, then checks that each task ended exactly
+// once: fired at most once, and only if it ended as expired; taken only if
+// no fire won the race.
+func TestMutationsDuringTicks(t *testing.T) {
+	rt := newRuntime(10, 5*time.Millisecond)
+	books := newLedger()
+	tw := timewheel.NewTimeWheel(base, 8, func(key string, value any) {
+		books.fire(value.(int))
+	}, timewheel.WithRuntime(rt), timewheel.WithOnRemove(func(key string, value any, reason timewheel.Reason) {
+		books.end(value.(int), reason)
+	}))
+	halt := rt.drive(t)
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				key := "k" + strconv.Itoa(rand.IntN(keys))
+				ttl := time.Duration(1+rand.IntN(80)) * base
+				switch rand.IntN(6) {
+				case 0, 1:
+					id := int(next.Add(1))
+					books.scheduled()
+					tw.Set(key, id, ttl)
+				case 2:
+					if tw.SetNX(key, int(next.Add(1)), ttl) {
+						books.scheduled()
+					}
+				case 3:
+					tw.Move(key, ttl)
+				case 4:
+					tw.Delete(key)
+				case 5:
+					if value, ok := tw.Take(key); ok {
+						books.take(value.(int))
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	tw.Stop()
+	halt()
+	books.check(t)
+}
+
+// TestSetSupersedesHandoff checks that once Set returns, the schedule it
+// replaced does not start its callback, however long the goroutine the tick
+// launched for it is delayed.
+func TestSetSupersedesHandoff(t *testing.T) {
+	const lag = 40 * time.Millisecond
+	rt := newRuntime(20, lag)
+	var mu sync.Mutex
+	replacedAt := make(map[int]time.Time)
+	var late atomic.Int64
+	tw := timewheel.NewTimeWheel(base, 8, func(key string, value any) {
+		mu.Lock()
+		at, replaced := replacedAt[value.(int)]
+		mu.Unlock()
+		// Allow a callback that started just before the Set returned a
+		// margin for the scheduler; the injected lag is far longer
+		if replaced && time.Since(at) > lag/2 {
+			late.Add(1)
+		}
+	}, timewheel.WithRuntime(rt))
+	defer tw.Stop()
+	halt := rt.drive(t)
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := "k" + strconv.Itoa(w)
+			for i := 1; i <= rounds/4; i++ {
+				id := w*rounds + i
+				tw.Set(key, id, time.Duration(1+rand.IntN(3))*base)
+				mu.Lock()
+				replacedAt[id-1] = time.Now()
+				mu.Unlock()
+				time.Sleep(time.Duration(rand.Int64N(int64(2 * time.Millisecond))))
+			}
+		}()
+	}
+	wg.Wait()
+	halt()
+	time.Sleep(lag)
+	if n := late.Load(); n > 0 {
+		t.Errorf("Expected no replaced schedule to fire after Set returned, %d did", n)
+	}
+}
+
+// TestDeleteAndWaitDuringTicks checks that once DeleteAndWait returns, no
+// callback for the key is running or can still start.
+func TestDeleteAndWaitDuringTicks(t *testing.T) {
+	rt := newRuntime(20, 5*time.Millisecond)
+	var running [writers]atomic.Int32
+	var dead [writers]atomic.Int64
+	tw := timewheel.NewTimeWheel(base, 8, func(key string, value any) {
+		w, _ := strconv.Atoi(key[1:])
+		id := value.(int)
+		if int64(id) <= dead[w].Load() {
+			t.Errorf("Callback for %s started after DeleteAndWait returned", key)
+		}
+		running[w].Add(1)
+		time.Sleep(time.Duration(rand.Int64N(int64(500 * time.Microsecond))))
+		running[w].Add(-1)
+	}, timewheel.WithRuntime(rt))
+	defer tw.Stop()
+	halt := rt.drive(t)
+	defer halt()
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := "k" + strconv.Itoa(w)
+			for id := 1; id <= rounds/4; id++ {
+				tw.Set(key, id, time.Duration(1+rand.IntN(3))*base)
+				time.Sleep(time.Duration(rand.Int64N(int64(3 * time.Millisecond))))
+				tw.DeleteAndWait(key)
+				if n := running[w].Load(); n != 0 {
+					t.Errorf("Expected no callback running for %s after DeleteAndWait, got %d", key, n)
+				}
+				dead[w].Store(int64(id))
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,112 @@
+package timewheel
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// TaskTags labels the task so related tasks can be listed or flushed
+// together with KeysByTag and FlushByTag. Tags travel with the task to
+// hooks, the Expired channel and batch callbacks; the context callback
+// reads them with TagsFromContext.
+func TaskTags(tags ...string) SetOption {
+	return func(so *setOptions) {
+		so.tags = normalizeTags(tags)
+	}
+}
+
+// SetWithTags is SetWith with TaskTags.
+func (tw *TimeWheel) SetWithTags(key string, value any, expiration time.Duration, tags ...string) error {
+	return tw.SetWith(key, value, expiration, TaskTags(tags...))
+}
+
+// Tags returns the tags of a pending task.
+func (tw *TimeWheel) Tags(key string) ([]string, bool) {
+	tw.mu.RLock()
+	defer tw.mu.RUnlock()
+
+	entry, exists := tw.keyMap[key]
+	if !exists {
+		return nil, false
+	}
+	return slices.Clone(entry.tags), true
+}
+
+// KeysByTag returns the keys of the pending tasks carrying tag, sorted.
+func (tw *TimeWheel) KeysByTag(tag string) []string {
+	tw.mu.RLock()
+	defer tw.mu.RUnlock()
+
+	keys := make([]string, 0, len(tw.tagIndex[tag]))
+	for entry := range tw.tagIndex[tag] {
+		keys = append(keys, entry.key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// FlushByTag removes every task carrying tag, without firing it, and returns
+// how many were removed.
+func (tw *TimeWheel) FlushByTag(tag string) int {
+	tw.mu.Lock()
+	defer tw.unlock()
+
+	tagged := tw.tagIndex[tag]
+	doomed := make([]*taskEntry, 0, len(tagged))
+	for entry := range tagged {
+		doomed = append(doomed, entry)
+	}
+	for _, entry := range doomed {
+		tw.untrack(entry)
+		tw.unlink(entry)
+		tw.record(hookCancel, entry)
+		tw.removed(entry, ReasonFlushed)
+		releaseEntry(entry)
+	}
+	return len(doomed)
+}
+
+// TagsFromContext returns the tags of the task whose callback received ctx.
+func TagsFromContext(ctx context.Context) []string {
+	tags, _ := ctx.Value(tagsKey{}).([]string)
+	return tags
+}
+
+type tagsKey struct{}
+
+func withTags(ctx context.Context, tags []string) context.Context {
+	if tags == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, tagsKey{}, tags)
+}
+
+// normalizeTags sorts and dedupes tags, returning nil for none.
+func normalizeTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	tags = slices.Clone(tags)
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+// indexTags and unindexTags keep tagIndex in step with keyMap.
+func (tw *TimeWheel) indexTags(entry *taskEntry) {
+	for _, tag := range entry.tags {
+		if tw.tagIndex[tag] == nil {
+			tw.tagIndex[tag] = make(map[*taskEntry]struct{})
+		}
+		tw.tagIndex[tag][entry] = struct{}{}
+	}
+}
+
+func (tw *TimeWheel) unindexTags(entry *taskEntry) {
+	for _, tag := range entry.tags {
+		delete(tw.tagIndex[tag], entry)
+		if len(tw.tagIndex[tag]) == 0 {
+			delete(tw.tagIndex, tag)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package timewheel
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestTags(t *testing.T) {
+	var got []string
+	tw := NewTimeWheel(0, 10, nil, WithSyncCallbacks(0), WithContextCallback(func(ctx context.Context, key string, _ any) {
+		if key == "a" {
+			got = TagsFromContext(ctx)
+		}
+	}))
+	defer tw.Stop()
+
+	tw.SetWithTags("a", nil, ManualInterval, "billing", "retry", "billing")
+	tw.SetWithTags("b", nil, 2*ManualInterval, "billing")
+	tw.SetWithTags("c", nil, 2*ManualInterval, "reports")
+	tw.Set("d", nil, ManualInterval)
+
+	if tags, _ := tw.Tags("a"); !slices.Equal(tags, []string{"billing", "retry"}) {
+		t.Errorf("Expected deduplicated sorted tags, got %v", tags)
+	}
+	if keys := tw.KeysByTag("billing"); !slices.Equal(keys, []string{"a", "b"}) {
+		t.Errorf("Expected billing keys a and b, got %v", keys)
+	}
+
+	tw.Tick()
+	if !slices.Equal(got, []string{"billing", "retry"}) {
+		t.Errorf("Expected the callback to see the tags, got %v", got)
+	}
+	if keys := tw.KeysByTag("retry"); len(keys) != 0 {
+		t.Errorf("Expected fired tasks to leave the index, got %v", keys)
+	}
+
+	if n := tw.FlushByTag("billing"); n != 1 {
+		t.Errorf("Expected FlushByTag to remove 1 task, removed %d", n)
+	}
+	if _, ok := tw.Tags("c"); !ok {
+		t.Error("Expected tasks without the tag to stay")
+	}
+}
@@ -1,7 +1,11 @@
 package timewheel
 
 import (
+	"context"
+	"log/slog"
+	"runtime/pprof"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -9,147 +13,408 @@ type TimeWheel struct {
 	layers        []*layer
 	baseInterval  time.Duration
 	slotsPerLayer int
-	mu            sync.RWMutex
-	keyMap        map[string]*taskEntry
-	callback      func(string, any)
-	ticker        *time.Ticker
-	quit          chan struct{}
+	// requestedInterval is what the caller asked for; baseInterval may be
+	// coarser after resolution adjustment.
+	requestedInterval time.Duration
+	adjustResolution  bool
+	maxLayers         int
+	driftCompensation bool
+	startedAt         time.Time
+	ticksDone         int64
+	tickLag           atomic.Int64
+	lastBeat          atomic.Int64
+	healthThreshold   time.Duration
+	catchUpThreshold  time.Duration
+	onCatchUp         func(gap time.Duration, late int)
+	prevTickAt        time.Time
+	manager           *Manager
+	backlog           []*taskEntry
+	cluster           *cluster
+	gated             bool
+	started           bool
+	createdAt         time.Time
+	life              atomic.Pointer[lifetime]
+	lifeMu            sync.Mutex
+	wal               *WAL
+	jitter            time.Duration
+	capacity          int
+	eviction          EvictionPolicy
+	onWarn            func(task TaskInfo, lead time.Duration)
+	leads             []time.Duration
+	warnings          warnings
+	mu                sync.RWMutex
+	keyMap            map[string]*taskEntry
+	parked            map[string]*taskEntry
+	pinned            map[string]*taskEntry
+	keyIndex          keyNode
+	manyKeys          map[string]*taskEntry
+	tagIndex          map[string]map[*taskEntry]struct{}
+	callback          func(string, any)
+	panicHandler      func(key string, value any, recovered any)
+	syncMode          bool
+	syncTimeout       time.Duration
+	onTimeout         func(key string, value any)
+	counters          counters
+	latency           latencies
+	lateness          histogram
+	history           *history
+	spreadThreshold   int
+	spreadTolerance   time.Duration
+	tickChunk         int
+	clears            uint64
+	hibernate         bool
+	sleeping          bool
+	wake              chan struct{}
+	maxStride         int
+	stride            int
+	wakeAt            uint64
+	retime            chan struct{}
+	execTimeout       time.Duration
+	maxDuration       time.Duration
+	cancelOnDelete    bool
+	flightMu          sync.Mutex
+	inflight          map[string]*flights
+	expired           *expiredChan
+	hooks             hooks
+	listeners         listeners
+	ctxCallback       func(ctx context.Context, key string, value any)
+	errCallback       ErrCallback
+	ctxErrCallback    ContextErrCallback
+	backoff           *backoff
+	onDeadLetter      func(task TaskInfo, err error)
+	ackTimeout        time.Duration
+	batchCallback     func(tasks []ExpiredTask)
+	labels            func(TaskInfo) pprof.LabelSet
+	logger            *slog.Logger
+	lagging           bool
+	maxBatch          int
+	tracer            Tracer
+	realtime          bool
+	nice              int
+	events            []hookEvent
+	emptied           bool
+	clock             Clock
+	nowFunc           func() time.Time
+	runtime           Runtime
+	group             *WheelGroup
+	zeroTTL           ZeroTTLPolicy
+	duplicate         DuplicatePolicy
+	immediate         bool
+	hybrid            bool
+	manual            bool
+	virtualNow        time.Time
+	lastTick          time.Time
+	// cursor counts the ticks stepped; slot positions derive from it.
+	cursor uint64
+	// seq numbers scheduled tasks, breaking ties between equal deadlines.
+	seq  uint64
+	keys *keyGen
 }
 
 type layer struct {
-	interval   time.Duration
+	interval time.Duration
+	// span is the interval in base ticks.
+	span       uint64
 	slots      int
 	currentPos int
-	buckets    []map[string]*taskEntry
+	buckets    []bucket
 }
 
 type taskEntry struct {
-	key        string
-	value      any
-	expiration time.Time
-	layerIndex int
-	bucketPos  int
-	rounds     int
+	key         string
+	value       any
+	expiration  time.Time
+	layerIndex  int
+	bucketPos   int
+	rounds      int
+	held        bool
+	annotations map[string]string
+	tags        []string
+	// retries counts the failed deliveries before this one.
+	retries int
+	// awaitingAck marks a delivery whose redelivery waits for an Ack, and
+	// that redelivery itself.
+	awaitingAck bool
+	ctx         context.Context
+	scheduledAt time.Time
+	firedAt     time.Time
+	seq         uint64
+	prev, next  *taskEntry
+	maint       *maintenance
+	parts       Key
+	// many lists the keys of a SetMany batch.
+	many        []string
+	timer       *shortTimer
+	handle      *Timer
+	handleGen   uint64
+	cron        *cronSchedule
+	jitter      time.Duration
+	execTimeout time.Duration
 }
 
-func NewTimeWheel(baseInterval time.Duration, slotsPerLayer int, callback func(key string, value any)) *TimeWheel {
+func NewTimeWheel(baseInterval time.Duration, slotsPerLayer int, callback func(key string, value any), opts ...Option) *TimeWheel {
+	tw := newTimeWheel(baseInterval, slotsPerLayer, callback, opts)
+	tw.started = true
+	tw.run()
+	return tw
+}
+
+// NewUnstartedTimeWheel builds a wheel without starting its run loop, which
+// Start does. Tasks may be set meanwhile; their delays count from Start.
+func NewUnstartedTimeWheel(baseInterval time.Duration, slotsPerLayer int, callback func(key string, value any), opts ...Option) *TimeWheel {
+	return newTimeWheel(baseInterval, slotsPerLayer, callback, opts)
+}
+
+func newTimeWheel(baseInterval time.Duration, slotsPerLayer int, callback func(key string, value any), opts []Option) *TimeWheel {
 	tw := &TimeWheel{
 		baseInterval:  baseInterval,
 		slotsPerLayer: slotsPerLayer,
 		keyMap:        make(map[string]*taskEntry),
+		parked:        make(map[string]*taskEntry),
+		pinned:        make(map[string]*taskEntry),
+		manyKeys:      make(map[string]*taskEntry),
+		tagIndex:      make(map[string]map[*taskEntry]struct{}),
+		lateness:      histogram{shift: latenessShift},
+		maxLayers:     defaultLayers,
+		tickChunk:     defaultTickChunk,
+		stride:        1,
+		clock:         defaultClock(),
+		runtime:       SystemRuntime(),
 		callback:      callback,
-		ticker:        time.NewTicker(baseInterval),
-		quit:          make(chan struct{}),
+	}
+	tw.life.Store(newLifetime())
+	for _, opt := range opts {
+		opt(tw)
+	}
+	if tw.nowFunc != nil {
+		tw.clock = nowClock{Clock: tw.clock, now: tw.nowFunc}
+	}
+
+	// A non-positive base interval selects manual mode with a virtual clock
+	if tw.baseInterval <= 0 {
+		tw.baseInterval = ManualInterval
+		tw.manual = true
+	}
+	tw.requestedInterval = tw.baseInterval
+	if tw.manual {
+		tw.virtualNow = tw.clock.Now()
+		tw.lastTick = tw.virtualNow
+	} else if lead := tw.groupLead(); lead != nil {
+		tw.clock, tw.runtime = lead.clock, lead.runtime
+		tw.baseInterval = lead.baseInterval
+	} else if tw.adjustResolution {
+		tw.baseInterval = adjustedInterval(tw.baseInterval, tw.timerResolution())
 	}
 
 	// Initialize layers
-	tw.addLayer(baseInterval)
-	tw.addLayer(baseInterval * time.Duration(slotsPerLayer))
-	tw.addLayer(baseInterval * time.Duration(slotsPerLayer*slotsPerLayer))
+	tw.addLayer(tw.baseInterval)
+	tw.addLayer(tw.baseInterval * time.Duration(slotsPerLayer))
+	tw.addLayer(tw.baseInterval * time.Duration(slotsPerLayer*slotsPerLayer))
 
-	go tw.run()
+	tw.replayWAL()
+	if tw.onWarn != nil {
+		tw.Maintain("timewheel/compact-warnings", max(warningCompaction, tw.baseInterval), tw.compactWarnings)
+	}
+	tw.createdAt = tw.clock.Now()
+	tw.keys = newKeyGen(tw.createdAt)
 	return tw
 }
 
+// run starts the goroutines driving the wheel.
+func (tw *TimeWheel) run() {
+	tw.startCluster()
+	if tw.group != nil && !tw.manual {
+		tw.group.join(tw)
+	} else if !tw.manual {
+		tw.startedAt = tw.clock.Now()
+		tw.prevTickAt = tw.startedAt
+		tw.beat(tw.startedAt)
+		life := tw.life.Load()
+		life.looping = make(chan struct{})
+		ticker := tw.clock.NewTicker(tw.baseInterval)
+		tw.runtime.Go(func() { tw.loop(ticker, life) })
+	}
+}
+
 func (tw *TimeWheel) addLayer(interval time.Duration) {
 	l := &layer{
-		interval:   interval,
-		slots:      tw.slotsPerLayer,
-		currentPos: 0,
-		buckets:    make([]map[string]*taskEntry, tw.slotsPerLayer),
-	}
-	for i := 0; i < tw.slotsPerLayer; i++ {
-		l.buckets[i] = make(map[string]*taskEntry)
+		interval: interval,
+		span:     uint64(interval / tw.baseInterval),
+		slots:    tw.slotsPerLayer,
+		buckets:  make([]bucket, tw.slotsPerLayer),
 	}
+	l.currentPos = l.position(tw.cursor)
 	tw.layers = append(tw.layers, l)
 }
 
-func (tw *TimeWheel) run() {
+// position is the slot of l covering the given tick.
+func (l *layer) position(tick uint64) int {
+	return int(tick / l.span % uint64(l.slots))
+}
+
+func (tw *TimeWheel) loop(ticker Ticker, life *lifetime) {
+	defer close(life.looping)
+	if tw.realtime {
+		// The thread exits with the goroutine, taking its priority with it
+		tw.lockTickThread()
+	}
+
+	period := tw.baseInterval
 	for {
 		select {
-		case <-tw.ticker.C:
+		case <-ticker.C():
 			tw.tick()
-		case <-tw.quit:
-			tw.ticker.Stop()
+		case <-tw.retime:
+		case <-life.quit:
+			ticker.Stop()
 			return
 		}
+		if tw.hibernate && tw.idle() {
+			ticker.Stop()
+			if !tw.sleep(life) {
+				return
+			}
+			ticker = tw.clock.NewTicker(tw.baseInterval)
+			period = tw.baseInterval
+		}
+		if tw.maxStride > 1 {
+			ticker, period = tw.restride(ticker, period)
+		}
 	}
 }
 
 func (tw *TimeWheel) tick() {
-	tw.mu.Lock()
-	defer tw.mu.Unlock()
+	now := tw.clock.Now()
+	tw.beat(now)
+	prev := tw.prevTickAt
+	tw.prevTickAt = now
+	if tw.catchUpThreshold > 0 && !prev.IsZero() {
+		// A planned stride of several ticks is not a gap
+		planned := time.Duration(tw.stride-1) * tw.baseInterval
+		if gap := tickGap(prev, now); gap-planned > tw.catchUpThreshold {
+			tw.catchUp(now, gap)
+			return
+		}
+	}
 
-	now := time.Now()
-	prevPositions := make([]int, len(tw.layers))
-	for i, l := range tw.layers {
-		prevPositions[i] = l.currentPos
+	for i := tw.dueSteps(now); i > 0; i-- {
+		tw.mu.Lock()
+		// A tick racing Stop must not fire tasks already reported stopped
+		if tw.stopped() {
+			tw.unlock()
+			return
+		}
+		expired := tw.applyBudget(tw.step(now))
+		tw.unlock()
+
+		tw.dispatch(expired)
 	}
+}
 
-	// Update position for base layer
-	baseLayer := tw.layers[0]
-	baseLayer.currentPos = (baseLayer.currentPos + 1) % baseLayer.slots
-	tw.processLayer(baseLayer, now)
+// step advances the wheel by one tick and returns the entries that expired.
+// Callers dispatch them after releasing the lock.
+func (tw *TimeWheel) step(now time.Time) []*taskEntry {
+	tw.counters.ticks.Add(1)
+	tw.cursor++
 
-	// Check and update higher layers
-	for i := 1; i < len(tw.layers); i++ {
-		prevLayer := tw.layers[i-1]
-		currentLayer := tw.layers[i]
-		if prevPositions[i-1] == prevLayer.slots-1 {
-			currentLayer.currentPos = (currentLayer.currentPos + 1) % currentLayer.slots
-			tw.processLayer(currentLayer, now)
+	// A layer moves on when the cursor crosses one of its slot boundaries,
+	// which is always a boundary of every layer below it as well
+	var expired []*taskEntry
+	for _, l := range tw.layers {
+		if tw.cursor%l.span != 0 {
+			break
 		}
+		l.currentPos = l.position(tw.cursor)
+		expired = tw.processLayer(l, now, expired)
 	}
+	tw.dueWarnings(now)
+	sortExpired(expired)
+	return expired
 }
 
-func (tw *TimeWheel) processLayer(l *layer, now time.Time) {
-	bucket := l.buckets[l.currentPos]
-	for key, entry := range bucket {
+func (tw *TimeWheel) processLayer(l *layer, now time.Time, expired []*taskEntry) []*taskEntry {
+	bucket := &l.buckets[l.currentPos]
+	var next *taskEntry
+	walked := 0
+	for entry := bucket.head; entry != nil; entry = next {
+		if walked++; tw.tickChunk > 0 && walked > tw.tickChunk {
+			if entry = tw.yield(bucket, entry); entry == nil {
+				break
+			}
+			walked = 1
+		}
+		next = entry.next
 		if entry.rounds > 0 {
 			entry.rounds--
 			continue
 		}
 
-		if entry.expiration.After(now) {
-			d := entry.expiration.Sub(now)
+		// One subtraction against the tick's reading decides: on the monotonic
+		// clock when both carry it, so a wall clock step cannot move a deadline
+		// across the tick. Entries re-placed into this same slot go to its
+		// head, behind the walk.
+		if d := entry.expiration.Sub(now); d >= tw.baseInterval {
 			targetLayer, targetPos, rounds := tw.findPosition(d)
-			if targetLayer == nil {
-				if tw.callback != nil {
-					go tw.callback(entry.key, entry.value)
-				}
-				delete(tw.keyMap, key)
-				delete(bucket, key)
-				continue
-			}
+			bucket.remove(entry)
+			tw.place(entry, targetLayer, targetPos, rounds)
+			continue
+		}
 
-			delete(bucket, key)
-			entry.layerIndex = tw.getLayerIndex(targetLayer)
-			entry.bucketPos = targetPos
-			entry.rounds = rounds
-			targetLayer.buckets[targetPos][key] = entry
-		} else {
-			if tw.callback != nil {
-				go tw.callback(entry.key, entry.value)
-			}
-			delete(tw.keyMap, key)
-			delete(bucket, key)
+		bucket.remove(entry)
+		if entry.maint != nil {
+			tw.replan(entry, now)
+			expired = append(expired, entry)
+			continue
 		}
+		if tw.holding(entry) {
+			tw.park(entry)
+			continue
+		}
+		expired = append(expired, entry)
+		tw.untrack(entry)
+		tw.board(entry)
 	}
+	return expired
 }
 
+// findPosition places a deadline d from now, or returns nil if d is shorter
+// than one tick. Slots are aligned to the cursor, Kafka style: the deadline
+// goes to the lowest layer whose slots reach it, and when that slot comes
+// round its entries are demoted exactly once into the layer below. Only the
+// top layer counts rounds.
 func (tw *TimeWheel) findPosition(d time.Duration) (*layer, int, int) {
-	for i := len(tw.layers) - 1; i >= 0; i-- {
-		l := tw.layers[i]
-		if l.interval <= d {
-			slots := int(d / l.interval)
-			rounds := slots / l.slots
-			pos := (l.currentPos + slots) % l.slots
+	if d < tw.baseInterval {
+		return nil, 0, 0
+	}
+	deadline := tw.cursor + uint64(d/tw.baseInterval)
+	top := len(tw.layers) - 1
+	for i, l := range tw.layers {
+		// Slots of l between the current one and the deadline's; at least 1
+		ahead := deadline/l.span - tw.cursor/l.span
+		if ahead < uint64(l.slots) || i == top {
+			pos, rounds := l.position(deadline), int((ahead-1)/uint64(l.slots))
+			if i == 0 && rounds == 0 && tw.spreadTolerance > 0 {
+				pos = tw.spreadSlot(l, pos, ahead)
+			}
 			return l, pos, rounds
 		}
 	}
 	return nil, 0, 0
 }
 
+// schedulePosition places a new deadline. A positive duration shorter than one
+// tick waits for the next tick, so a zero-delay chain set from callbacks costs
+// one tick per step; with immediate dispatch it returns nil and the caller
+// fires right away instead.
+func (tw *TimeWheel) schedulePosition(d time.Duration) (*layer, int, int) {
+	targetLayer, targetPos, rounds := tw.findPosition(d)
+	if targetLayer == nil && !tw.immediate {
+		base := tw.layers[0]
+		return base, (base.currentPos + 1) % base.slots, 0
+	}
+	return targetLayer, targetPos, rounds
+}
+
 func (tw *TimeWheel) getLayerIndex(target *layer) int {
 	for i, l := range tw.layers {
 		if l == target {
@@ -159,114 +424,469 @@ func (tw *TimeWheel) getLayerIndex(target *layer) int {
 	return -1
 }
 
-func (tw *TimeWheel) Set(key string, value any, expiration time.Duration) {
-	tw.mu.Lock()
-	defer tw.mu.Unlock()
+// Set schedules the task, reporting whether it replaced a pending task and
+// how long that task had left. Once Set returns, no earlier schedule of the
+// key runs its callback unless that callback had already started: a fire
+// the tick handed off before the Set is dropped and reported as replaced.
+func (tw *TimeWheel) Set(key string, value any, expiration time.Duration) (prev time.Duration, replaced bool) {
+	prev, replaced, _ = tw.setWith(key, value, expiration, nil)
+	return prev, replaced
+}
 
-	now := time.Now()
-	expireAt := now.Add(expiration)
+func (tw *TimeWheel) SetWith(key string, value any, expiration time.Duration, opts ...SetOption) error {
+	_, _, err := tw.setWith(key, value, expiration, opts)
+	return err
+}
+
+// Swap is SetWith reporting, like Set, whether it replaced a pending task and
+// how long that task had left, all read under the same lock as the write.
+func (tw *TimeWheel) Swap(key string, value any, expiration time.Duration, opts ...SetOption) (prev time.Duration, replaced bool, err error) {
+	return tw.setWith(key, value, expiration, opts)
+}
+
+func (tw *TimeWheel) setWith(key string, value any, expiration time.Duration, opts []SetOption) (time.Duration, bool, error) {
+	if tw.stopped() {
+		return 0, false, ErrStopped
+	}
+	so := tw.newSetOptions(opts)
+	expiration = ttlOf(value, expiration)
+
+	done := tw.lockFor(&tw.latency.set)
+	old, exists := tw.keyMap[key]
+	var prev time.Duration
+	if exists {
+		prev = clampDuration(old.expiration.Sub(tw.now()))
+	}
+	fireNow, err := tw.set(key, value, expiration, so)
+	// set leaves the old entry in keyMap when a duplicate policy keeps it
+	replaced := exists && tw.keyMap[key] != old
+	done()
+	tw.unlock()
+
+	// FireSync callbacks run outside the lock so they may call back into the wheel
+	if fireNow != nil {
+		tw.fireSync(fireNow)
+	}
+	if !replaced {
+		prev = 0
+	}
+	return prev, replaced, err
+}
+
+// SetAt schedules the task for the instant at. The delay is computed under
+// the wheel lock, so it cannot go stale racing a tick, and uses the
+// monotonic clock reading when at carries one (as time.Now().Add does).
+func (tw *TimeWheel) SetAt(key string, value any, at time.Time, opts ...SetOption) error {
+	if tw.stopped() {
+		return ErrStopped
+	}
+	so := tw.newSetOptions(opts)
+
+	done := tw.lockFor(&tw.latency.set)
+	fireNow, err := tw.set(key, value, at.Sub(tw.now()), so)
+	done()
+	tw.unlock()
+
+	if fireNow != nil {
+		tw.fireSync(fireNow)
+	}
+	return err
+}
+
+func (tw *TimeWheel) SetNX(key string, value any, expiration time.Duration) bool {
+	if tw.stopped() {
+		return false
+	}
+	so := tw.newSetOptions(nil)
+	expiration = ttlOf(value, expiration)
+	// set drops an ignored zero TTL without an error, but nothing was added
+	if expiration <= 0 && so.zeroTTL == Ignore {
+		return false
+	}
 
-	if entry, exists := tw.keyMap[key]; exists {
-		delete(tw.keyMap, key)
-		tw.layers[entry.layerIndex].buckets[entry.bucketPos][key] = nil
-		delete(tw.layers[entry.layerIndex].buckets[entry.bucketPos], key)
+	done := tw.lockFor(&tw.latency.set)
+	if _, exists := tw.keyMap[key]; exists {
+		done()
+		tw.unlock()
+		return false
 	}
+	fireNow, err := tw.set(key, value, expiration, so)
+	done()
+	tw.unlock()
 
+	if fireNow != nil {
+		tw.fireSync(fireNow)
+	}
+	return err == nil
+}
+
+func (tw *TimeWheel) newSetOptions(opts []SetOption) *setOptions {
+	so := &setOptions{zeroTTL: tw.zeroTTL, duplicate: tw.duplicate, jitter: tw.jitter, execTimeout: tw.execTimeout}
+	for _, opt := range opts {
+		opt(so)
+	}
+	return so
+}
+
+// set schedules a task under the lock. A returned entry must be fired
+// synchronously by the caller once the lock is released.
+func (tw *TimeWheel) set(key string, value any, expiration time.Duration, so *setOptions) (*taskEntry, error) {
+	if tw.backpressure() {
+		return nil, ErrBackpressure
+	}
+	now := tw.now()
+	if expiration > 0 {
+		expiration = addJitter(expiration, tw.jitterFor(so.jitter))
+	}
+	expireAt := now.Add(expiration)
+	// A rejected or ignored zero TTL leaves a pending task of the key alone
 	if expiration <= 0 {
-		if tw.callback != nil {
-			go tw.callback(key, value)
+		switch so.zeroTTL {
+		case Reject:
+			return nil, ErrZeroTTL
+		case Ignore:
+			return nil, nil
 		}
-		return
 	}
 
-	d := expiration
-	targetLayer, targetPos, rounds := tw.findPosition(d)
-	if targetLayer == nil {
-		if tw.callback != nil {
-			go tw.callback(key, value)
+	old, replaced := tw.keyMap[key]
+	if replaced {
+		switch so.duplicate {
+		case DuplicateReject:
+			return nil, ErrDuplicate
+		case DuplicateKeepEarliest:
+			if !expireAt.Before(old.expiration) {
+				return nil, nil
+			}
+		case DuplicateKeepLatest:
+			if !expireAt.After(old.expiration) {
+				return nil, nil
+			}
 		}
-		return
+		tw.untrack(old)
+		tw.unlink(old)
 	}
 
-	entry := &taskEntry{
-		key:        key,
-		value:      value,
-		expiration: expireAt,
-		layerIndex: tw.getLayerIndex(targetLayer),
-		bucketPos:  targetPos,
-		rounds:     rounds,
+	tw.supersede(key)
+	entry := newEntry()
+	entry.key = key
+	entry.value = value
+	entry.expiration = expireAt
+	entry.annotations = so.annotations
+	entry.tags = so.tags
+	entry.ctx = so.ctx
+	entry.cron = so.cron
+	entry.jitter = so.jitter
+	entry.execTimeout = so.execTimeout
+	entry.parts = so.parts
+	entry.many = so.many
+	if so.handle != nil {
+		entry.handle = so.handle
+		entry.handleGen = so.handle.gen.Load()
+	}
+	entry.scheduledAt = now
+	entry.seq = tw.nextSeq()
+
+	var targetLayer *layer
+	var targetPos, rounds int
+	timed := tw.timed(expiration)
+	if expiration > 0 && !timed {
+		tw.grow(expiration)
+		targetLayer, targetPos, rounds = tw.schedulePosition(expiration)
+	}
+	if targetLayer == nil && !timed {
+		if replaced {
+			tw.record(hookCancel, old)
+			tw.removed(old, ReasonReplaced)
+		}
+		if expiration <= 0 && so.zeroTTL == FireSync && !tw.gated {
+			return entry, nil
+		}
+		if tw.gated {
+			if err := tw.makeRoom(); err != nil {
+				return nil, err
+			}
+			tw.track(entry)
+			tw.park(entry)
+			tw.counters.scheduled.Add(1)
+			tw.record(hookSchedule, entry)
+			return nil, nil
+		}
+		tw.fireAsync(entry)
+		return nil, nil
 	}
 
-	targetLayer.buckets[targetPos][key] = entry
-	tw.keyMap[key] = entry
+	if err := tw.makeRoom(); err != nil {
+		return nil, err
+	}
+	if timed {
+		tw.arm(entry, expiration)
+	} else {
+		tw.place(entry, targetLayer, targetPos, rounds)
+	}
+	tw.track(entry)
+	tw.counters.scheduled.Add(1)
+	if replaced {
+		tw.record(hookReschedule, entry)
+		tw.removed(old, ReasonReplaced)
+	} else {
+		tw.record(hookSchedule, entry)
+	}
+	return nil, nil
 }
 
-func (tw *TimeWheel) Delete(key string) {
-	tw.mu.Lock()
-	defer tw.mu.Unlock()
+// Delete cancels the task, reporting how long it had left. existed is false
+// when there was no task or it has already fired, so a cancel that lost the
+// race with the tick can be told apart from one that won it.
+func (tw *TimeWheel) Delete(key string) (remaining time.Duration, existed bool) {
+	done := tw.lockFor(&tw.latency.delete)
+	defer tw.unlock()
+	defer done()
+
+	tw.abort(key)
+	entry, exists := tw.lookup(key)
+	if !exists {
+		return 0, false
+	}
+
+	remaining = clampDuration(entry.expiration.Sub(tw.now()))
+	if entry.key != key {
+		tw.dropMany(entry, key, ReasonDeleted)
+	} else {
+		tw.cancel(entry, ReasonDeleted)
+	}
+	return remaining, true
+}
+
+// Take removes the task and returns its value, so a caller can claim pending
+// work before it times out: of a Take racing the fire, exactly one wins.
+func (tw *TimeWheel) Take(key string) (value any, ok bool) {
+	done := tw.lockFor(&tw.latency.delete)
+	defer tw.unlock()
+	defer done()
 
 	entry, exists := tw.keyMap[key]
 	if !exists {
-		return
+		return nil, false
 	}
 
-	delete(tw.keyMap, key)
-	layer := tw.layers[entry.layerIndex]
-	delete(layer.buckets[entry.bucketPos], key)
+	value = entry.value
+	tw.cancel(entry, ReasonTaken)
+	return value, true
 }
 
-func (tw *TimeWheel) Move(key string, expiration time.Duration) {
-	tw.mu.Lock()
-	defer tw.mu.Unlock()
+// cancel removes a pending entry on behalf of Delete and its kin.
+func (tw *TimeWheel) cancel(entry *taskEntry, reason Reason) {
+	tw.untrack(entry)
+	tw.unlink(entry)
+	tw.counters.deleted.Add(1)
+	tw.record(hookCancel, entry)
+	tw.removed(entry, reason)
+	releaseEntry(entry)
+}
+
+// Move reschedules the task to fire expiration from now, reporting how long
+// it had left before the move. Missing keys are not scheduled.
+func (tw *TimeWheel) Move(key string, expiration time.Duration) (prev time.Duration, existed bool) {
+	done := tw.lockFor(&tw.latency.move)
+	defer tw.unlock()
+	defer done()
+
+	entry, exists := tw.lookup(key)
+	if !exists {
+		return 0, false
+	}
+
+	prev = clampDuration(entry.expiration.Sub(tw.now()))
+	if entry.key != key {
+		tw.moveMany(entry, key, expiration)
+	} else {
+		tw.reschedule(entry, expiration)
+	}
+	return prev, true
+}
+
+// MoveIf reschedules the task like Move, but only if its value still equals
+// expected, and reports whether it did. A lease renewed with a fencing token
+// as its value thus cannot be extended by a renewer holding a stale token.
+// As with sync.Map's CompareAndSwap, expected must be of a comparable type.
+func (tw *TimeWheel) MoveIf(key string, expected any, expiration time.Duration) bool {
+	done := tw.lockFor(&tw.latency.move)
+	defer tw.unlock()
+	defer done()
+
+	entry, exists := tw.keyMap[key]
+	if !exists || entry.value != expected {
+		return false
+	}
+	tw.reschedule(entry, expiration)
+	return true
+}
+
+// UpdateValue replaces the value the task will deliver without touching its
+// deadline, and reports whether the task was pending.
+func (tw *TimeWheel) UpdateValue(key string, value any) bool {
+	done := tw.lockFor(&tw.latency.move)
+	defer tw.unlock()
+	defer done()
 
 	entry, exists := tw.keyMap[key]
 	if !exists {
-		return
+		return false
 	}
 
-	now := time.Now()
-	newExpireAt := now.Add(expiration)
-	d := expiration
+	entry.value = value
+	tw.journal(hookReschedule, entry)
+	return true
+}
 
-	oldLayer := tw.layers[entry.layerIndex]
-	delete(oldLayer.buckets[entry.bucketPos], key)
+func (tw *TimeWheel) Extend(key string, delta time.Duration) (time.Duration, error) {
+	done := tw.lockFor(&tw.latency.move)
+	defer tw.unlock()
+	defer done()
 
-	if d <= 0 {
-		if tw.callback != nil {
-			go tw.callback(entry.key, entry.value)
-		}
-		delete(tw.keyMap, key)
-		return
+	entry, exists := tw.keyMap[key]
+	if !exists {
+		return 0, ErrNotFound
 	}
 
-	targetLayer, targetPos, rounds := tw.findPosition(d)
-	if targetLayer == nil {
-		if tw.callback != nil {
-			go tw.callback(entry.key, entry.value)
+	remaining := entry.expiration.Add(delta).Sub(tw.now())
+	tw.reschedule(entry, remaining)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, nil
+}
+
+func (tw *TimeWheel) Shorten(key string, delta time.Duration) (time.Duration, error) {
+	return tw.Extend(key, -delta)
+}
+
+// reschedule moves an existing entry to fire d from now, firing it right
+// away when d is too short for any layer.
+func (tw *TimeWheel) reschedule(entry *taskEntry, d time.Duration) {
+	now := tw.now()
+	newExpireAt := now.Add(d)
+
+	tw.unlink(entry)
+	entry.expiration = newExpireAt
+
+	var targetLayer *layer
+	var targetPos, rounds int
+	timed := tw.timed(d)
+	if d > 0 && !timed {
+		tw.grow(d)
+		targetLayer, targetPos, rounds = tw.schedulePosition(d)
+	}
+	if targetLayer == nil && !timed {
+		if tw.holding(entry) {
+			tw.park(entry)
+			tw.record(hookReschedule, entry)
+			return
 		}
-		delete(tw.keyMap, key)
+		// The firing is logged with the new deadline, so log that first
+		tw.journal(hookReschedule, entry)
+		tw.fireAsync(entry)
+		tw.untrack(entry)
 		return
 	}
 
-	entry.expiration = newExpireAt
-	entry.layerIndex = tw.getLayerIndex(targetLayer)
-	entry.bucketPos = targetPos
-	entry.rounds = rounds
-	targetLayer.buckets[targetPos][key] = entry
+	if timed {
+		tw.arm(entry, d)
+	} else {
+		tw.place(entry, targetLayer, targetPos, rounds)
+	}
+	tw.record(hookReschedule, entry)
+}
+
+// unlink removes an entry from whichever bucket holds it, leaving keyMap alone.
+func (tw *TimeWheel) unlink(entry *taskEntry) {
+	if entry.timer != nil {
+		entry.timer.stop()
+		entry.timer = nil
+		return
+	}
+	if entry.layerIndex < 0 {
+		delete(tw.parked, entry.key)
+		return
+	}
+	tw.layers[entry.layerIndex].buckets[entry.bucketPos].remove(entry)
 }
 
 func (tw *TimeWheel) FlushAll() {
 	tw.mu.Lock()
-	defer tw.mu.Unlock()
+	defer tw.unlock()
+
+	for _, entry := range tw.keyMap {
+		tw.record(hookCancel, entry)
+		tw.removed(entry, ReasonFlushed)
+	}
+	tw.clearTasks()
+}
 
+// clearTasks empties the wheel of every task but the pinned ones.
+func (tw *TimeWheel) clearTasks() {
+	had := len(tw.keyMap) > 0
+	tw.clears++
+	for _, entry := range tw.keyMap {
+		if entry.timer != nil {
+			entry.timer.stop()
+		}
+		releaseEntry(entry)
+	}
 	tw.keyMap = make(map[string]*taskEntry)
+	tw.keyIndex = keyNode{}
+	clear(tw.manyKeys)
+	clear(tw.tagIndex)
+	tw.parked = make(map[string]*taskEntry)
+	tw.warnings = nil
 	for _, l := range tw.layers {
-		for i := range l.buckets {
-			l.buckets[i] = make(map[string]*taskEntry)
-		}
+		clear(l.buckets)
+	}
+	tw.repin()
+	if had {
+		tw.noteEmpty()
 	}
 }
 
+// Stop halts the wheel. Pending tasks stay put and resume if Start is called
+// again; until then Set and its variants fail with ErrStopped.
 func (tw *TimeWheel) Stop() {
-	close(tw.quit)
+	tw.stop(false)
+}
+
+// StopAndDrain stops the wheel like Stop and returns the tasks still pending,
+// in deadline order, so they can be persisted or handed to another wheel.
+// They leave the wheel, so a restart does not fire them too. Only the call
+// that stops the wheel gets them.
+func (tw *TimeWheel) StopAndDrain() []TaskInfo {
+	return tw.stop(true)
+}
+
+func (tw *TimeWheel) stop(drain bool) []TaskInfo {
+	tw.lifeMu.Lock()
+	defer tw.lifeMu.Unlock()
+
+	if tw.stopped() {
+		return nil
+	}
+	life := tw.life.Load()
+	life.stoppedAt = tw.clock.Now()
+	close(life.quit)
+	tw.closeExpired()
+	return tw.dropPending(drain)
+}
+
+// quit returns the channel closed when the current run of the wheel stops.
+func (tw *TimeWheel) quit() <-chan struct{} {
+	return tw.life.Load().quit
+}
+
+func (tw *TimeWheel) stopped() bool {
+	select {
+	case <-tw.quit():
+		return true
+	default:
+		return false
+	}
 }
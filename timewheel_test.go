@@ -1,6 +1,7 @@
 package timewheel
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -89,3 +90,249 @@ func TestConcurrentAccess(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestSetNX(t *testing.T) {
+	var wg sync.WaitGroup
+	var got any
+	tw := NewTimeWheel(100*time.Millisecond, 10, func(k string, v any) {
+		got = v
+		wg.Done()
+	})
+	defer tw.Stop()
+
+	wg.Add(1)
+	if !tw.SetNX("test", "first", 200*time.Millisecond) {
+		t.Error("Expected first SetNX to add the key")
+	}
+	if tw.SetNX("test", "second", 200*time.Millisecond) {
+		t.Error("Expected second SetNX to be refused")
+	}
+	wg.Wait()
+
+	if got != "first" {
+		t.Errorf("Expected value from first SetNX, got %v", got)
+	}
+}
+
+func TestSetAt(t *testing.T) {
+	fired := make(chan string, 2)
+	tw := NewTimeWheel(0, 10, func(k string, v any) {
+		fired <- k
+	}, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	start := tw.now()
+	tw.SetAt("wall", "data", start.Round(0).Add(5*ManualInterval))
+	tw.SetAt("past", "data", start.Add(-time.Hour), TaskZeroTTL(FireSync))
+	if got := <-fired; got != "past" {
+		t.Fatalf("Expected a past instant to fire at once, got %s", got)
+	}
+
+	tw.Advance(4 * ManualInterval)
+	select {
+	case k := <-fired:
+		t.Fatalf("Expected nothing to fire early, got %s", k)
+	default:
+	}
+	tw.Tick()
+	if got := <-fired; got != "wall" {
+		t.Errorf("Expected wall to fire at its instant, got %s", got)
+	}
+}
+
+func TestOutcomes(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	if _, replaced := tw.Set("key", "first", 8*ManualInterval); replaced {
+		t.Error("Expected a new key not to report a replacement")
+	}
+	tw.Advance(3 * ManualInterval)
+	if prev, replaced := tw.Set("key", "second", 8*ManualInterval); !replaced || prev != 5*ManualInterval {
+		t.Errorf("Expected to replace a task with 5ms left, got %s %v", prev, replaced)
+	}
+	if prev, existed := tw.Move("key", 2*ManualInterval); !existed || prev != 8*ManualInterval {
+		t.Errorf("Expected to move a task with 8ms left, got %s %v", prev, existed)
+	}
+	if _, existed := tw.Move("missing", ManualInterval); existed {
+		t.Error("Expected Move of a missing key to report it absent")
+	}
+	if left, existed := tw.Delete("key"); !existed || left != 2*ManualInterval {
+		t.Errorf("Expected to cancel a task with 2ms left, got %s %v", left, existed)
+	}
+
+	tw.Set("fired", "data", ManualInterval)
+	tw.Tick()
+	if _, existed := tw.Delete("fired"); existed {
+		t.Error("Expected Delete after the fire to report the task gone")
+	}
+}
+
+func TestSwap(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	if _, replaced, err := tw.Swap("key", "first", 8*ManualInterval); err != nil || replaced {
+		t.Errorf("Expected a new key to be added without a replacement, got %v %v", replaced, err)
+	}
+	tw.Advance(3 * ManualInterval)
+	if prev, replaced, err := tw.Swap("key", "second", 8*ManualInterval); err != nil || !replaced || prev != 5*ManualInterval {
+		t.Errorf("Expected to replace a task with 5ms left, got %s %v %v", prev, replaced, err)
+	}
+	if _, replaced, err := tw.Swap("key", "third", time.Minute, TaskDuplicate(DuplicateReject)); !errors.Is(err, ErrDuplicate) || replaced {
+		t.Errorf("Expected a rejected duplicate to replace nothing, got %v %v", replaced, err)
+	}
+
+	tw.Stop()
+	if _, _, err := tw.Swap("key", "fourth", time.Minute); !errors.Is(err, ErrStopped) {
+		t.Errorf("Expected ErrStopped, got %v", err)
+	}
+}
+
+func TestCascade(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil)
+	defer tw.Stop()
+
+	// 10 slots: layers span 1, 10 and 100 ticks, the top one revolving every 1000
+	tw.Set("k", "data", 255*ManualInterval)
+	tw.Set("top", "data", 2000*ManualInterval)
+	layerOf := func(key string) int {
+		tw.mu.RLock()
+		defer tw.mu.RUnlock()
+		return tw.keyMap[key].layerIndex
+	}
+
+	for _, step := range []struct {
+		at    time.Duration
+		layer int
+	}{{0, 2}, {199, 2}, {200, 1}, {249, 1}, {250, 0}, {254, 0}} {
+		tw.Advance((step.at - time.Duration(tw.cursor)) * ManualInterval)
+		if got := layerOf("k"); got != step.layer {
+			t.Errorf("Expected the task in layer %d at %dms, got %d", step.layer, step.at, got)
+		}
+	}
+	tw.Tick()
+	if _, _, ok := tw.Remaining("k"); ok {
+		t.Error("Expected the task to fire at 255ms")
+	}
+
+	tw.Advance(1744 * ManualInterval)
+	if _, _, ok := tw.Remaining("top"); !ok {
+		t.Fatal("Expected a task two revolutions out not to fire early")
+	}
+	tw.Tick()
+	if _, _, ok := tw.Remaining("top"); ok {
+		t.Error("Expected a task two revolutions out to fire on time")
+	}
+}
+
+func TestCascadeAccuracy(t *testing.T) {
+	var tw *TimeWheel
+	deadlines := make(map[string]time.Time)
+	tw = NewTimeWheel(0, 8, func(k string, v any) {
+		// A task fires on the first tick within one interval of its deadline
+		if d := tw.virtualNow.Sub(deadlines[k]); d > 0 || d <= -ManualInterval {
+			t.Errorf("Expected %s to fire within a tick before its deadline, off by %s", k, d)
+		}
+		delete(deadlines, k)
+	}, WithSyncCallbacks(0), WithMaxLayers(6))
+	defer tw.Stop()
+
+	for i := 0; i < 2000; i++ {
+		if i%7 == 0 {
+			tw.Advance(time.Duration(i%5) * ManualInterval / 4)
+		}
+		key := fmt.Sprint(i)
+		d := time.Duration(i*7919%50000)*ManualInterval/2 + ManualInterval
+		deadlines[key] = tw.now().Add(d)
+		tw.Set(key, nil, d)
+	}
+	tw.Advance(time.Minute)
+	if len(deadlines) != 0 {
+		t.Errorf("Expected every task to fire, %d left", len(deadlines))
+	}
+}
+
+func TestTake(t *testing.T) {
+	fired := 0
+	tw := NewTimeWheel(0, 10, func(string, any) { fired++ }, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	tw.Set("job", "payload", 2*ManualInterval)
+	if v, ok := tw.Take("job"); !ok || v != "payload" {
+		t.Errorf("Expected to take the payload, got %v %v", v, ok)
+	}
+	if _, ok := tw.Take("job"); ok {
+		t.Error("Expected a second Take to find nothing")
+	}
+	tw.Advance(2 * ManualInterval)
+	if fired != 0 {
+		t.Error("Expected a taken task not to fire")
+	}
+}
+
+func TestMoveIf(t *testing.T) {
+	fired := 0
+	tw := NewTimeWheel(0, 10, func(string, any) { fired++ }, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	tw.Set("lease", 2, 2*ManualInterval)
+	if tw.MoveIf("lease", 1, 10*ManualInterval) {
+		t.Error("Expected a stale token not to extend the lease")
+	}
+	if tw.MoveIf("missing", 2, 10*ManualInterval) {
+		t.Error("Expected MoveIf to skip missing keys")
+	}
+	if !tw.MoveIf("lease", 2, 4*ManualInterval) {
+		t.Fatal("Expected the current token to extend the lease")
+	}
+	tw.Advance(3 * ManualInterval)
+	if fired != 0 {
+		t.Fatal("Expected the lease to keep its extended deadline")
+	}
+	tw.Advance(ManualInterval)
+	if fired != 1 {
+		t.Errorf("Expected the lease to expire once, got %d", fired)
+	}
+}
+
+func TestUpdateValue(t *testing.T) {
+	var got any
+	tw := NewTimeWheel(0, 10, func(_ string, v any) { got = v }, WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	tw.Set("job", 1, 3*ManualInterval)
+	tw.Advance(ManualInterval)
+	if !tw.UpdateValue("job", 2) {
+		t.Fatal("Expected UpdateValue to find the pending task")
+	}
+	tw.Advance(ManualInterval)
+	if got != nil {
+		t.Fatal("Expected UpdateValue to keep the deadline")
+	}
+	tw.Advance(ManualInterval)
+	if got != 2 {
+		t.Errorf("Expected the updated value, got %v", got)
+	}
+	if tw.UpdateValue("job", 3) {
+		t.Error("Expected UpdateValue to miss a fired task")
+	}
+}
+
+func TestStopAndDrain(t *testing.T) {
+	tw := NewTimeWheel(0, 10, func(string, any) {})
+	tw.Set("late", 2, 3*ManualInterval)
+	tw.Set("soon", 1, ManualInterval)
+	tw.Hold("late")
+
+	pending := tw.StopAndDrain()
+	if len(pending) != 2 || pending[0].Key != "soon" || pending[1].Key != "late" {
+		t.Fatalf("Expected both tasks in deadline order, got %v", pending)
+	}
+	if pending[1].Value != 2 {
+		t.Errorf("Expected the drained value, got %v", pending[1].Value)
+	}
+	if again := tw.StopAndDrain(); again != nil {
+		t.Errorf("Expected a second stop to drain nothing, got %v", again)
+	}
+}
@@ -0,0 +1,34 @@
+package timewheel
+
+import (
+	"context"
+	"time"
+)
+
+// Tracer bridges the wheel to a tracing system such as OpenTelemetry.
+type Tracer interface {
+	// Start is called when a task fires, with the context it was scheduled
+	// with. Implementations typically start a span beginning at scheduledAt
+	// so the timer wait appears in the trace. The returned context is passed
+	// to the context callback and end is called once the callbacks return.
+	Start(ctx context.Context, task TaskInfo, scheduledAt, firedAt time.Time) (_ context.Context, end func())
+}
+
+func WithTracer(t Tracer) Option {
+	return func(tw *TimeWheel) {
+		tw.tracer = t
+	}
+}
+
+// WithContextCallback registers a callback that receives the context stored
+// by SetWithContext (or derived from it by the Tracer). It runs before the
+// plain callback passed to NewTimeWheel, if any.
+func WithContextCallback(cb func(ctx context.Context, key string, value any)) Option {
+	return func(tw *TimeWheel) {
+		tw.ctxCallback = cb
+	}
+}
+
+func (tw *TimeWheel) SetWithContext(ctx context.Context, key string, value any, expiration time.Duration, opts ...SetOption) error {
+	return tw.SetWith(key, value, expiration, append(opts, TaskContext(ctx))...)
+}
@@ -0,0 +1,45 @@
+package timewheel
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type ctxKey string
+
+type recordingTracer struct {
+	ended chan time.Duration
+}
+
+func (rt *recordingTracer) Start(ctx context.Context, task TaskInfo, scheduledAt, firedAt time.Time) (context.Context, func()) {
+	return context.WithValue(ctx, ctxKey("span"), task.Key), func() {
+		rt.ended <- firedAt.Sub(scheduledAt)
+	}
+}
+
+func TestTracing(t *testing.T) {
+	tracer := &recordingTracer{ended: make(chan time.Duration, 1)}
+	got := make(chan context.Context, 1)
+	tw := NewTimeWheel(0, 10, nil, WithTracer(tracer), WithContextCallback(func(ctx context.Context, k string, v any) {
+		got <- ctx
+	}))
+	defer tw.Stop()
+
+	ctx := context.WithValue(context.Background(), ctxKey("trace"), "parent")
+	tw.SetWithContext(ctx, "test", "data", 3*ManualInterval)
+	tw.Advance(3 * ManualInterval)
+
+	select {
+	case ctx := <-got:
+		if ctx.Value(ctxKey("trace")) != "parent" || ctx.Value(ctxKey("span")) != "test" {
+			t.Error("Expected callback context to carry the scheduling and tracer values")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Context callback was not called")
+	}
+
+	if wait := <-tracer.ended; wait != 3*ManualInterval {
+		t.Errorf("Expected a 3ms schedule-to-fire span, got %s", wait)
+	}
+}
@@ -0,0 +1,19 @@
+package timewheel
+
+import "time"
+
+// TTLProvider lets a value own its expiration policy: Set, SetWith and
+// SetNX called with a zero duration ask the value for its TTL instead.
+type TTLProvider interface {
+	TTL() time.Duration
+}
+
+func ttlOf(value any, expiration time.Duration) time.Duration {
+	if expiration != 0 {
+		return expiration
+	}
+	if p, ok := value.(TTLProvider); ok {
+		return p.TTL()
+	}
+	return expiration
+}
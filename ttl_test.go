@@ -0,0 +1,39 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+type session struct {
+	ttl time.Duration
+}
+
+func (s session) TTL() time.Duration {
+	return s.ttl
+}
+
+func TestTTLProvider(t *testing.T) {
+	tw := NewTimeWheel(0, 10, nil)
+	defer tw.Stop()
+
+	start := tw.now()
+	tw.Set("inherited", session{ttl: 5 * ManualInterval}, 0)
+	tw.Set("explicit", session{ttl: 5 * ManualInterval}, 2*ManualInterval)
+	tw.SetNX("nx", session{ttl: 7 * ManualInterval}, 0)
+
+	for key, want := range map[string]time.Duration{
+		"inherited": 5 * ManualInterval,
+		"explicit":  2 * ManualInterval,
+		"nx":        7 * ManualInterval,
+	} {
+		entry, ok := tw.keyMap[key]
+		if !ok {
+			t.Errorf("Expected %s to be pending", key)
+			continue
+		}
+		if got := entry.expiration.Sub(start); got != want {
+			t.Errorf("Expected %s to expire after %s, got %s", key, want, got)
+		}
+	}
+}
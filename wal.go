@@ -0,0 +1,289 @@
+package timewheel
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WAL is an append-only, file-backed log of the wheel's mutations. Replaying
+// it at construction restores the pending tasks after a crash.
+//
+// Values are stored as JSON unless WALCodec says otherwise, so after a
+// replay they come back as the types encoding/json decodes into
+// (map[string]any, float64, ...). Records are written unbuffered to the OS
+// but not fsynced; call Sync for that.
+type WAL struct {
+	mu        sync.Mutex
+	file      *os.File
+	err       error
+	live      map[string]walRecord
+	replaying bool
+	codec     Codec
+}
+
+// WALOption configures a WAL opened by OpenWAL.
+type WALOption func(*WAL)
+
+// WALCodec stores values encoded by c rather than as JSON. A log must be
+// reopened with the codec that wrote it.
+func WALCodec(c Codec) WALOption {
+	return func(w *WAL) {
+		w.codec = c
+	}
+}
+
+type walRecord struct {
+	Op          string            `json:"op"`
+	Key         string            `json:"key"`
+	Value       any               `json:"value,omitempty"`
+	Data        []byte            `json:"data,omitempty"`
+	Expiration  int64             `json:"exp,omitempty"`
+	Annotations map[string]string `json:"ann,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Cron        string            `json:"cron,omitempty"`
+}
+
+var errNoCodec = errors.New("timewheel: WAL record is encoded; open the log with WALCodec")
+
+const (
+	walSet    = "set"
+	walDelete = "del"
+	walFire   = "fire"
+)
+
+// OpenWAL opens or creates the log at path and compacts it down to the tasks
+// still pending.
+func OpenWAL(path string, opts ...WALOption) (*WAL, error) {
+	live, err := readWAL(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rewrite the live set to a temporary file and swap it in
+	tmp := path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	w := &WAL{file: file, live: live}
+	for _, opt := range opts {
+		opt(w)
+	}
+	for _, r := range w.records() {
+		w.write(r)
+	}
+	if w.err == nil {
+		w.err = file.Sync()
+	}
+	if w.err == nil {
+		w.err = os.Rename(tmp, filepath.Clean(path))
+	}
+	if w.err != nil {
+		file.Close()
+		return nil, w.err
+	}
+	return w, nil
+}
+
+func readWAL(path string) (map[string]walRecord, error) {
+	live := make(map[string]walRecord)
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return live, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var r walRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			// A torn final write from a crash; everything before it stands
+			break
+		}
+		switch r.Op {
+		case walSet:
+			live[r.Key] = r
+		case walDelete:
+			delete(live, r.Key)
+		case walFire:
+			// Only the firing of the recorded deadline retires the task; a
+			// Set that raced the callback survives
+			if prev, ok := live[r.Key]; ok && prev.Expiration == r.Expiration {
+				delete(live, r.Key)
+			}
+		}
+	}
+	return live, scanner.Err()
+}
+
+// value returns the task value a record holds.
+func (w *WAL) value(r walRecord) (any, error) {
+	if r.Data == nil {
+		return r.Value, nil
+	}
+	if w.codec == nil {
+		return nil, errNoCodec
+	}
+	return w.codec.Decode(r.Data)
+}
+
+// records returns the live set in deadline order.
+func (w *WAL) records() []walRecord {
+	records := make([]walRecord, 0, len(w.live))
+	for _, r := range w.live {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Expiration < records[j].Expiration
+	})
+	return records
+}
+
+// write appends one record and returns the error that stopped the log, only
+// on the write that hit it.
+func (w *WAL) write(r walRecord) error {
+	if w.err != nil {
+		return nil
+	}
+	if w.codec != nil && r.Value != nil {
+		data, err := w.codec.Encode(r.Value)
+		if err != nil {
+			w.err = err
+			return err
+		}
+		r.Value, r.Data = nil, data
+	}
+	line, err := json.Marshal(r)
+	if err != nil {
+		w.err = err
+		return err
+	}
+	_, w.err = w.file.Write(append(line, '\n'))
+	return w.err
+}
+
+func (w *WAL) append(r walRecord) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.replaying {
+		return nil
+	}
+	return w.write(r)
+}
+
+// Err returns the first write error; the log stops recording after one.
+func (w *WAL) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+func (w *WAL) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Sync()
+}
+
+// Close closes the log file. Call it after stopping the wheel.
+func (w *WAL) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
+
+// WithWAL records every Set, Delete and Move to w and, at construction,
+// reschedules the tasks w recovered. Tasks whose deadline passed while the
+// process was down fire right away. A task's firing is logged once its
+// callback returns, so a crash mid-callback fires it again on replay.
+func WithWAL(w *WAL) Option {
+	return func(tw *TimeWheel) {
+		tw.wal = w
+	}
+}
+
+func (tw *TimeWheel) replayWAL() {
+	w := tw.wal
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	records := w.records()
+	w.live = nil
+	w.replaying = true
+	w.mu.Unlock()
+
+	// The compacted log already holds these tasks, so replay must not log
+	// them again; the firing of overdue ones is logged as usual
+	for _, r := range records {
+		value, err := w.value(r)
+		if err != nil {
+			tw.log(slog.LevelError, "timewheel: cannot decode WAL record, dropping it", "key", r.Key, "err", err)
+			continue
+		}
+		ttl := time.Unix(0, r.Expiration).Sub(tw.now())
+		var opts []SetOption
+		if r.Annotations != nil {
+			opts = append(opts, TaskAnnotations(r.Annotations))
+		}
+		if r.Tags != nil {
+			opts = append(opts, TaskTags(r.Tags...))
+		}
+		if parts := SplitKey(r.Key); len(parts) > 1 && parts.valid() {
+			opts = append(opts, taskParts(parts))
+		}
+		if r.Cron != "" {
+			tw.SetCron(r.Key, value, r.Cron, opts...)
+			continue
+		}
+		// The logged deadline already includes any jitter
+		opts = append(opts, TaskJitter(0))
+		if ttl <= 0 {
+			opts = append(opts, TaskZeroTTL(FireAsync))
+		}
+		tw.SetWith(r.Key, value, ttl, opts...)
+	}
+
+	w.mu.Lock()
+	w.replaying = false
+	w.mu.Unlock()
+}
+
+// journal logs a mutation to the WAL under the wheel lock, so records land
+// in the order the wheel applied them.
+func (tw *TimeWheel) journal(kind hookKind, entry *taskEntry) {
+	if tw.wal == nil {
+		return
+	}
+	r := walRecord{Key: entry.key}
+	switch kind {
+	case hookSchedule, hookReschedule:
+		r.Op = walSet
+		r.Value = entry.value
+		r.Expiration = entry.expiration.UnixNano()
+		r.Annotations = entry.annotations
+		r.Tags = entry.tags
+		if entry.cron != nil {
+			r.Cron = entry.cron.spec
+		}
+	case hookCancel:
+		r.Op = walDelete
+	default:
+		r.Op = walFire
+		r.Expiration = entry.expiration.UnixNano()
+	}
+	if err := tw.wal.append(r); err != nil {
+		tw.log(slog.LevelError, "timewheel: WAL write failed, no longer recording", "err", err)
+	}
+}
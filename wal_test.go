@@ -0,0 +1,52 @@
+package timewheel
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestWALReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wheel.wal")
+	w, err := OpenWAL(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fired := make(chan string, 4)
+	tw := NewTimeWheel(0, 10, func(k string, v any) {
+		fired <- k
+	}, WithWAL(w), WithSyncCallbacks(0))
+	tw.Set("fired", "data", ManualInterval)
+	tw.Set("deleted", "data", 5*ManualInterval)
+	tw.Set("moved", "data", 5*ManualInterval)
+	tw.SetWith("kept", map[string]any{"n": 1.0}, 50*ManualInterval, TaskAnnotations(map[string]string{"owner": "billing"}))
+	tw.Delete("deleted")
+	tw.Move("moved", 30*ManualInterval)
+	tw.Tick()
+	if got := <-fired; got != "fired" {
+		t.Fatalf("Expected fired to fire, got %s", got)
+	}
+	// Crash: no Stop, no Close
+	w.file.Close()
+
+	w, err = OpenWAL(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	tw = NewTimeWheel(0, 10, func(k string, v any) {
+		fired <- k
+	}, WithWAL(w), WithSyncCallbacks(0))
+	defer tw.Stop()
+
+	if n := tw.Stats().Pending; n != 2 {
+		t.Fatalf("Expected 2 recovered tasks, got %d", n)
+	}
+	if a, _ := tw.Annotations("kept"); a["owner"] != "billing" {
+		t.Errorf("Expected annotations to survive replay, got %v", a)
+	}
+	tw.Advance(35 * ManualInterval)
+	if got := <-fired; got != "moved" {
+		t.Errorf("Expected moved to fire at its new deadline, got %s", got)
+	}
+}
@@ -0,0 +1,80 @@
+package timewheel
+
+import (
+	"container/heap"
+	"time"
+)
+
+// WithWarnings calls h as each task comes within one of the lead times of
+// its deadline, e.g. 5*time.Minute and time.Minute, for "about to expire"
+// notices without shadow timers. A lead time already passed when the task
+// is set or moved is skipped. h runs on the tick goroutine after the lock is
+// released.
+func WithWarnings(h func(task TaskInfo, lead time.Duration), leads ...time.Duration) Option {
+	return func(tw *TimeWheel) {
+		tw.onWarn = h
+		tw.leads = append([]time.Duration(nil), leads...)
+	}
+}
+
+// warning names its task by key and seq rather than by entry, since entries
+// are pooled and one removed since may be back under the same key.
+type warning struct {
+	at         time.Time
+	lead       time.Duration
+	key        string
+	seq        uint64
+	expiration time.Time
+}
+
+// warnings is a min-heap by warning time. Tasks rescheduled or removed since
+// are skipped when they surface.
+type warnings []warning
+
+func (w warnings) Len() int           { return len(w) }
+func (w warnings) Less(i, j int) bool { return w[i].at.Before(w[j].at) }
+func (w warnings) Swap(i, j int)      { w[i], w[j] = w[j], w[i] }
+func (w *warnings) Push(x any)        { *w = append(*w, x.(warning)) }
+func (w *warnings) Pop() any {
+	old := *w
+	x := old[len(old)-1]
+	*w = old[:len(old)-1]
+	return x
+}
+
+// planWarnings queues the warnings of a task that was just scheduled or
+// given a new deadline.
+func (tw *TimeWheel) planWarnings(entry *taskEntry) {
+	if tw.onWarn == nil {
+		return
+	}
+	now := tw.now()
+	for _, lead := range tw.leads {
+		at := entry.expiration.Add(-lead)
+		if at.After(now) {
+			heap.Push(&tw.warnings, warning{at: at, lead: lead, key: entry.key, seq: entry.seq, expiration: entry.expiration})
+		}
+	}
+}
+
+// dueWarnings queues the warnings due by now to run on unlock.
+func (tw *TimeWheel) dueWarnings(now time.Time) {
+	for len(tw.warnings) > 0 && !tw.warnings[0].at.After(now) {
+		w := heap.Pop(&tw.warnings).(warning)
+		entry := tw.warned(w)
+		if entry == nil {
+			continue
+		}
+		tw.events = append(tw.events, hookEvent{kind: hookWarn, info: entry.info(), lead: w.lead})
+	}
+}
+
+// warned returns the task w warns of, or nil if it has since been
+// rescheduled or removed.
+func (tw *TimeWheel) warned(w warning) *taskEntry {
+	entry := tw.keyMap[w.key]
+	if entry == nil || entry.seq != w.seq || !entry.expiration.Equal(w.expiration) {
+		return nil
+	}
+	return entry
+}
@@ -0,0 +1,56 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWarnings(t *testing.T) {
+	type notice struct {
+		key  string
+		lead time.Duration
+	}
+	var got []notice
+	tw := NewTimeWheel(0, 10, nil, WithWarnings(func(task TaskInfo, lead time.Duration) {
+		got = append(got, notice{task.Key, lead})
+	}, 5*ManualInterval, 2*ManualInterval))
+	defer tw.Stop()
+
+	tw.Set("a", "data", 8*ManualInterval)
+	tw.Set("short", "data", 3*ManualInterval) // already inside the first lead
+	tw.Set("moved", "data", 6*ManualInterval)
+	tw.Set("deleted", "data", 6*ManualInterval)
+	tw.Move("moved", 20*ManualInterval)
+	tw.Delete("deleted")
+
+	tw.Advance(3 * ManualInterval)
+	expected := []notice{{"short", 2 * ManualInterval}, {"a", 5 * ManualInterval}}
+	if len(got) != 2 || got[0] != expected[0] || got[1] != expected[1] {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+
+	got = nil
+	tw.Advance(3 * ManualInterval)
+	expected = []notice{{"a", 2 * ManualInterval}}
+	if len(got) != 1 || got[0] != expected[0] {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestWarningsReusedEntry(t *testing.T) {
+	var got int
+	tw := NewTimeWheel(0, 10, nil, WithWarnings(func(task TaskInfo, lead time.Duration) {
+		got++
+	}, 5*ManualInterval))
+	defer tw.Stop()
+
+	// The second Set may reuse the pooled entry of the first
+	tw.Set("a", "data", 10*ManualInterval)
+	tw.Delete("a")
+	tw.Set("a", "data", 10*ManualInterval)
+
+	tw.Advance(5 * ManualInterval)
+	if got != 1 {
+		t.Errorf("Expected one warning for the live task, got %d", got)
+	}
+}
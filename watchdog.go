@@ -0,0 +1,84 @@
+package timewheel
+
+import (
+	"sync"
+	"time"
+)
+
+// Watchdog detects peers that stop checking in: each registered id must be
+// kicked within its timeout, or its miss callback runs. A miss ends the
+// watch; register the id again to resume watching it.
+type Watchdog struct {
+	tw      *TimeWheel
+	mu      sync.Mutex
+	watches map[string]*watch
+}
+
+type watch struct {
+	timeout time.Duration
+	onMiss  func(id string)
+}
+
+// NewWatchdog builds a watchdog on a wheel of its own, configured by opts.
+func NewWatchdog(baseInterval time.Duration, slotsPerLayer int, opts ...Option) *Watchdog {
+	wd := &Watchdog{watches: make(map[string]*watch)}
+	wd.tw = NewTimeWheel(baseInterval, slotsPerLayer, wd.expire, opts...)
+	return wd
+}
+
+// Register watches id, calling onMiss if timeout passes without a Kick.
+// Registering an id again replaces its timeout and callback and restarts it.
+func (wd *Watchdog) Register(id string, timeout time.Duration, onMiss func(id string)) {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+
+	w := &watch{timeout: timeout, onMiss: onMiss}
+	wd.watches[id] = w
+	wd.tw.Set(id, w, timeout)
+}
+
+// Kick restarts id's timeout and reports whether id is still watched.
+func (wd *Watchdog) Kick(id string) bool {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+
+	w, ok := wd.watches[id]
+	return ok && wd.tw.MoveIf(id, w, w.timeout)
+}
+
+// Unregister stops watching id without calling its callback.
+func (wd *Watchdog) Unregister(id string) bool {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+
+	if _, ok := wd.watches[id]; !ok {
+		return false
+	}
+	delete(wd.watches, id)
+	wd.tw.Delete(id)
+	return true
+}
+
+// Wheel returns the wheel behind the watchdog, for its stats and options.
+func (wd *Watchdog) Wheel() *TimeWheel {
+	return wd.tw
+}
+
+func (wd *Watchdog) Stop() {
+	wd.tw.Stop()
+}
+
+// expire reports a miss unless the id was registered again while it fired.
+func (wd *Watchdog) expire(id string, value any) {
+	w := value.(*watch)
+	wd.mu.Lock()
+	current := wd.watches[id] == w
+	if current {
+		delete(wd.watches, id)
+	}
+	wd.mu.Unlock()
+
+	if current && w.onMiss != nil {
+		w.onMiss(id)
+	}
+}
@@ -0,0 +1,53 @@
+package timewheel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWatchdog(t *testing.T) {
+	wd := NewWatchdog(0, 10)
+	defer wd.Stop()
+
+	missed := make(chan string, 3)
+	onMiss := func(id string) { missed <- id }
+	wd.Register("alive", 3*ManualInterval, onMiss)
+	wd.Register("dead", 3*ManualInterval, onMiss)
+	wd.Register("gone", 3*ManualInterval, onMiss)
+	if !wd.Unregister("gone") {
+		t.Error("Expected Unregister to find gone")
+	}
+
+	for i := 0; i < 3; i++ {
+		wd.Wheel().Advance(2 * ManualInterval)
+		if !wd.Kick("alive") {
+			t.Fatal("Expected alive to still be watched")
+		}
+	}
+	select {
+	case id := <-missed:
+		if id != "dead" {
+			t.Errorf("Expected dead to miss, got %s", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("dead did not miss")
+	}
+
+	wd.Wheel().Advance(3 * ManualInterval)
+	select {
+	case id := <-missed:
+		if id != "alive" {
+			t.Errorf("Expected alive to miss once no longer kicked, got %s", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("alive did not miss")
+	}
+	if wd.Kick("alive") || wd.Kick("dead") {
+		t.Error("Expected a miss to end the watch")
+	}
+	select {
+	case id := <-missed:
+		t.Errorf("Expected no more misses, got %s", id)
+	case <-time.After(20 * time.Millisecond):
+	}
+}